@@ -0,0 +1,39 @@
+resource "okta_user" "admin" {
+  first_name = "TestAcc"
+  last_name  = "Smith"
+  login      = "testAcc_replace_with_uuid@example.com"
+  email      = "testAcc_replace_with_uuid@example.com"
+}
+
+resource "okta_resource_set" "test" {
+  label       = "testAcc_replace_with_uuid"
+  description = "testing, testing"
+  resources   = ["https://example.okta.com/api/v1/users"]
+}
+
+resource "okta_admin_role_custom" "test" {
+  label       = "testAcc_replace_with_uuid"
+  description = "testing, testing"
+  permissions = ["okta.users.read"]
+}
+
+variable "break_glass_user_id" {
+  type = string
+}
+
+resource "okta_admin_role_assignments" "users" {
+  principal_type = "USER"
+  allow_list     = [var.break_glass_user_id]
+
+  assignment {
+    principal_id = okta_user.admin.id
+    role_type    = "APP_ADMIN"
+  }
+
+  assignment {
+    principal_id    = okta_user.admin.id
+    role_type       = "CUSTOM"
+    custom_role_id  = okta_admin_role_custom.test.id
+    resource_set_id = okta_resource_set.test.id
+  }
+}
@@ -0,0 +1,11 @@
+data "okta_admin_role_assignments" "test" {}
+
+check "no_unexpected_super_admins" {
+  assert {
+    condition = length([
+      for a in data.okta_admin_role_assignments.test.assignments : a
+      if a.role_type == "SUPER_ADMIN" && a.principal_type == "USER"
+    ]) <= 2
+    error_message = "More than 2 users are super admins."
+  }
+}
@@ -0,0 +1,28 @@
+resource "okta_app_oauth" "test" {
+  label          = "testAcc_replace_with_uuid"
+  type           = "web"
+  grant_types    = ["implicit", "authorization_code"]
+  redirect_uris  = ["http://d.com/"]
+  response_types = ["code", "token", "id_token"]
+  issuer_mode    = "ORG_URL"
+}
+
+resource "okta_user" "test" {
+  count      = 3
+  first_name = "TestAcc"
+  last_name  = "Smith"
+  login      = "testAcc_${count.index}_replace_with_uuid@example.com"
+  email      = "testAcc_${count.index}_replace_with_uuid@example.com"
+}
+
+resource "okta_app_user_assignments" "test" {
+  app_id = okta_app_oauth.test.id
+
+  dynamic "users" {
+    for_each = okta_user.test
+    content {
+      id       = users.value.id
+      username = users.value.email
+    }
+  }
+}
@@ -0,0 +1,30 @@
+resource "okta_app_oauth" "test" {
+  label          = "testAcc_replace_with_uuid"
+  type           = "web"
+  grant_types    = ["implicit", "authorization_code"]
+  redirect_uris  = ["http://d.com/"]
+  response_types = ["code", "token", "id_token"]
+  issuer_mode    = "ORG_URL"
+}
+
+resource "okta_user" "test" {
+  count      = 3
+  first_name = "TestAcc"
+  last_name  = "Smith"
+  login      = "testAcc_${count.index}_replace_with_uuid@example.com"
+  email      = "testAcc_${count.index}_replace_with_uuid@example.com"
+}
+
+resource "okta_app_user_assignments" "test" {
+  app_id = okta_app_oauth.test.id
+
+  users {
+    id       = okta_user.test[0].id
+    username = "renamed_${okta_user.test[0].email}"
+  }
+
+  users {
+    id       = okta_user.test[2].id
+    username = okta_user.test[2].email
+  }
+}
@@ -0,0 +1,10 @@
+resource "okta_auth_server_key_rotation" "test" {
+  auth_server_id   = okta_auth_server.test.id
+  rotation_trigger = "2024-01"
+}
+
+resource "okta_auth_server" "test" {
+  name        = "testAcc_replace_with_uuid"
+  description = "test"
+  audiences   = ["whatever.rise.zone"]
+}
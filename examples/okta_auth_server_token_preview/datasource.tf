@@ -0,0 +1,21 @@
+resource "okta_user" "test" {
+  first_name = "TestAcc"
+  last_name  = "Smith"
+  login      = "testAcc-replace_with_uuid@example.com"
+  email      = "testAcc-replace_with_uuid@example.com"
+}
+
+resource "okta_app_oauth" "test" {
+  label          = "testAcc_replace_with_uuid"
+  type           = "web"
+  grant_types    = ["authorization_code"]
+  redirect_uris  = ["http://d.com/"]
+  response_types = ["code"]
+}
+
+data "okta_auth_server_token_preview" "test" {
+  auth_server_id = "default"
+  client_id      = okta_app_oauth.test.client_id
+  user_id        = okta_user.test.id
+  scopes         = ["openid", "profile"]
+}
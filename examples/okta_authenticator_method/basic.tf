@@ -0,0 +1,21 @@
+data "okta_authenticator" "phone" {
+  key = "phone_number"
+}
+
+data "okta_authenticator" "webauthn" {
+  key = "webauthn"
+}
+
+resource "okta_authenticator_method" "voice" {
+  authenticator_id = data.okta_authenticator.phone.id
+  type             = "voice"
+  status           = "INACTIVE"
+}
+
+resource "okta_authenticator_method" "webauthn" {
+  authenticator_id = data.okta_authenticator.webauthn.id
+  type             = "webauthn"
+  settings = jsonencode({
+    userVerification = "REQUIRED"
+  })
+}
@@ -0,0 +1,22 @@
+resource "okta_inline_hook" "example" {
+  name    = "example"
+  type    = "com.okta.user.pre-registration"
+  version = "1.0.3"
+
+  channel = {
+    type    = "HTTP"
+    version = "1.0.0"
+    uri     = "https://example.com/registration"
+    method  = "POST"
+  }
+
+  auth = {
+    type  = "HEADER"
+    key   = "Authorization"
+    value = "secret"
+  }
+}
+
+data "okta_inline_hook_preview" "example" {
+  inline_hook_id = okta_inline_hook.example.id
+}
@@ -0,0 +1,11 @@
+resource "okta_group" "test" {
+  name        = "testAcc_replace_with_uuid"
+  description = "testing, testing"
+}
+
+data "okta_system_logs" "test" {
+  depends_on = [okta_group.test]
+  filter     = "eventType eq \"group.lifecycle.create\""
+  sort_order = "DESCENDING"
+  max_events = 5
+}
@@ -0,0 +1,43 @@
+resource "okta_user_schema" "testAcc_replace_with_uuid" {
+  property {
+    index       = "testAcc_size_replace_with_uuid"
+    title       = "terraform acceptance test"
+    type        = "string"
+    description = "terraform acceptance test"
+    min_length  = 1
+    max_length  = 50
+    enum        = ["S", "M", "L"]
+
+    one_of {
+      const = "S"
+      title = "Small"
+    }
+
+    one_of {
+      const = "M"
+      title = "Medium"
+    }
+
+    one_of {
+      const = "L"
+      title = "Large"
+    }
+  }
+
+  property {
+    index       = "testAcc_employee_id_replace_with_uuid"
+    title       = "terraform acceptance test employee id"
+    type        = "string"
+    permissions = "READ_WRITE"
+    unique      = "UNIQUE_VALIDATED"
+    pattern     = "[0-9]+"
+  }
+
+  property {
+    index      = "testAcc_teams_replace_with_uuid"
+    title      = "terraform acceptance test teams"
+    type       = "array"
+    array_type = "string"
+    array_enum = ["red", "blue"]
+  }
+}
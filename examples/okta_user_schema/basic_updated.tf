@@ -0,0 +1,41 @@
+resource "okta_user_schema" "testAcc_replace_with_uuid" {
+  property {
+    index       = "testAcc_size_replace_with_uuid"
+    title       = "terraform acceptance test updated"
+    type        = "string"
+    description = "terraform acceptance test updated"
+    min_length  = 1
+    max_length  = 70
+    master      = "OKTA"
+    enum        = ["S", "M", "L", "XL"]
+
+    one_of {
+      const = "S"
+      title = "Small"
+    }
+
+    one_of {
+      const = "M"
+      title = "Medium"
+    }
+
+    one_of {
+      const = "L"
+      title = "Large"
+    }
+
+    one_of {
+      const = "XL"
+      title = "Extra Large"
+    }
+  }
+
+  property {
+    index       = "testAcc_employee_id_replace_with_uuid"
+    title       = "terraform acceptance test employee id"
+    type        = "string"
+    permissions = "READ_WRITE"
+    unique      = "UNIQUE_VALIDATED"
+    pattern     = "[0-9]+"
+  }
+}
@@ -3,7 +3,10 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
+	"os"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
@@ -19,6 +22,11 @@ import (
 //go:generate go run github.com/hashicorp/terraform-plugin-docs/cmd/tfplugindocs
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		export(os.Args[2:])
+		return
+	}
+
 	var debug bool
 	providers := []func() tfprotov5.ProviderServer{
 		// v2 plugin
@@ -48,3 +56,18 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+// export writes resource and import blocks for an existing org, e.g.
+//
+//	OKTA_ORG_NAME=example OKTA_BASE_URL=okta.com OKTA_API_TOKEN=... \
+//	  terraform-provider-okta export -dir ./generated -resources groups,apps
+func export(args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	dir := flags.String("dir", ".", "directory the .tf files are written to")
+	resources := flags.String("resources", strings.Join(okta.ExportKinds(), ","), "comma separated kinds of objects to export")
+	_ = flags.Parse(args)
+
+	if err := okta.Export(context.Background(), *dir, strings.Split(*resources, ",")); err != nil {
+		log.Fatal(err)
+	}
+}
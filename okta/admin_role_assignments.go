@@ -0,0 +1,356 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/okta/terraform-provider-okta/sdk/query"
+)
+
+const (
+	customRoleType = "CUSTOM"
+	groupScope     = "GROUP"
+)
+
+// adminRoleAssignment is a standard admin role, or a custom role on a resource
+// set, held directly by a user or a group.
+type adminRoleAssignment struct {
+	principalType string
+	principalID   string
+	roleType      string
+	customRoleID  string
+	resourceSetID string
+	// roleID is the ID of the role assignment of a standard role, used to
+	// remove it
+	roleID string
+	// memberID is the ID of the principal's membership of the custom role's
+	// binding, used to remove it
+	memberID string
+}
+
+func (a *adminRoleAssignment) key() string {
+	return strings.Join([]string{a.principalType, a.principalID, a.roleType, a.customRoleID, a.resourceSetID}, "/")
+}
+
+func (a *adminRoleAssignment) String() string {
+	if a.roleType == customRoleType {
+		return fmt.Sprintf("custom role (%s) on resource set (%s) of %s (%s)", a.customRoleID, a.resourceSetID, strings.ToLower(a.principalType), a.principalID)
+	}
+	return fmt.Sprintf("%s of %s (%s)", a.roleType, strings.ToLower(a.principalType), a.principalID)
+}
+
+// listAdminRoleAssignments returns every admin role assignment held directly
+// by a principal of the given types, USER or GROUP. Standard roles of users are
+// read for each user with role assignments and those of groups for each group
+// of the org. Custom roles are read from the members of every binding of every
+// resource set.
+func listAdminRoleAssignments(ctx context.Context, m interface{}, principalTypes ...string) ([]*adminRoleAssignment, error) {
+	var assignments []*adminRoleAssignment
+	for _, principalType := range principalTypes {
+		var (
+			standard []*adminRoleAssignment
+			err      error
+		)
+		switch principalType {
+		case userScope:
+			standard, err = listUserStandardRoleAssignments(ctx, m)
+		case groupScope:
+			standard, err = listGroupStandardRoleAssignments(ctx, m)
+		default:
+			return nil, fmt.Errorf("unknown principal type %q, expected %s or %s", principalType, userScope, groupScope)
+		}
+		if err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, standard...)
+	}
+	custom, err := listCustomRoleAssignments(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+	for _, assignment := range custom {
+		if contains(principalTypes, assignment.principalType) {
+			assignments = append(assignments, assignment)
+		}
+	}
+	sort.Slice(assignments, func(i, j int) bool {
+		return assignments[i].key() < assignments[j].key()
+	})
+	return assignments, nil
+}
+
+func listUserStandardRoleAssignments(ctx context.Context, m interface{}) ([]*adminRoleAssignment, error) {
+	client := getOktaClientFromMetadata(m)
+	userIDs, err := listRoleAssigneeUserIDs(ctx, getAPISupplementFromMetadata(m))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users with role assignments: %v", err)
+	}
+	roles := make([][]*sdk.Role, len(userIDs))
+	err = forEachParallel(ctx, m, len(userIDs), func(ctx context.Context, i int) error {
+		userRoles, resp, err := listUserOnlyRoles(ctx, client, userIDs[i])
+		if err := suppressErrorOn404(resp, err); err != nil {
+			return fmt.Errorf("failed to list roles of user (%s): %v", userIDs[i], err)
+		}
+		roles[i] = userRoles
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var assignments []*adminRoleAssignment
+	for i, userRoles := range roles {
+		for _, role := range userRoles {
+			assignments = append(assignments, &adminRoleAssignment{
+				principalType: userScope,
+				principalID:   userIDs[i],
+				roleType:      role.Type,
+				roleID:        role.Id,
+			})
+		}
+	}
+	return assignments, nil
+}
+
+func listGroupStandardRoleAssignments(ctx context.Context, m interface{}) ([]*adminRoleAssignment, error) {
+	client := getOktaClientFromMetadata(m)
+	groups, err := listGroups(ctx, client, &query.Params{Limit: maxGroupsPageSize})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list groups: %v", err)
+	}
+	roles := make([][]*sdk.Role, len(groups))
+	err = forEachParallel(ctx, m, len(groups), func(ctx context.Context, i int) error {
+		groupRoles, resp, err := client.Group.ListGroupAssignedRoles(ctx, groups[i].Id, nil)
+		if err := suppressErrorOn404(resp, err); err != nil {
+			return fmt.Errorf("failed to list roles of group (%s): %v", groups[i].Id, err)
+		}
+		roles[i] = groupRoles
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var assignments []*adminRoleAssignment
+	for i, groupRoles := range roles {
+		for _, role := range groupRoles {
+			if role.Type == customRoleType {
+				continue
+			}
+			assignments = append(assignments, &adminRoleAssignment{
+				principalType: groupScope,
+				principalID:   groups[i].Id,
+				roleType:      role.Type,
+				roleID:        role.Id,
+			})
+		}
+	}
+	return assignments, nil
+}
+
+func listRoleAssigneeUserIDs(ctx context.Context, client *sdk.APISupplement) ([]string, error) {
+	var ids []string
+	qp := &query.Params{Limit: defaultPaginationLimit}
+	for {
+		page, _, err := client.ListRoleAssigneeUsers(ctx, qp)
+		if err != nil {
+			return nil, err
+		}
+		for _, assignee := range page.Value {
+			ids = append(ids, assignee.Id)
+		}
+		after := page.Links.After()
+		if after == "" || len(page.Value) == 0 {
+			return ids, nil
+		}
+		qp = &query.Params{Limit: defaultPaginationLimit, After: after}
+	}
+}
+
+// listCustomRoleAssignments returns the custom role assignments of users and
+// groups from the members of the bindings of every resource set.
+func listCustomRoleAssignments(ctx context.Context, m interface{}) ([]*adminRoleAssignment, error) {
+	client := getAPISupplementFromMetadata(m)
+	resourceSets, _, err := client.ListResourceSets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resource sets: %v", err)
+	}
+	if resourceSets == nil {
+		return nil, nil
+	}
+	type binding struct {
+		resourceSetID string
+		customRoleID  string
+	}
+	var bindings []binding
+	for _, resourceSet := range resourceSets.ResourceSets {
+		qp := &query.Params{}
+		for {
+			page, _, err := client.ListResourceSetBindings(ctx, resourceSet.Id, qp)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list bindings of resource set (%s): %v", resourceSet.Id, err)
+			}
+			for _, role := range page.Roles {
+				bindings = append(bindings, binding{resourceSetID: resourceSet.Id, customRoleID: role.Id})
+			}
+			after := page.Links.After()
+			if after == "" || len(page.Roles) == 0 {
+				break
+			}
+			qp = &query.Params{After: after}
+		}
+	}
+
+	var (
+		lock        sync.Mutex
+		assignments []*adminRoleAssignment
+	)
+	err = forEachParallel(ctx, m, len(bindings), func(ctx context.Context, i int) error {
+		members, _, err := listResourceSetBindingMembers(ctx, client, bindings[i].resourceSetID, bindings[i].customRoleID)
+		if err != nil {
+			return fmt.Errorf("failed to list members of custom role (%s) on resource set (%s): %v", bindings[i].customRoleID, bindings[i].resourceSetID, err)
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		for _, member := range members {
+			principalType, principalID := customRoleMemberPrincipal(member)
+			if principalID == "" {
+				continue
+			}
+			assignments = append(assignments, &adminRoleAssignment{
+				principalType: principalType,
+				principalID:   principalID,
+				roleType:      customRoleType,
+				customRoleID:  bindings[i].customRoleID,
+				resourceSetID: bindings[i].resourceSetID,
+				memberID:      member.Id,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return assignments, nil
+}
+
+// customRoleMemberPrincipal returns the type and ID of the user or group a
+// member of a custom role binding links to.
+func customRoleMemberPrincipal(member *sdk.CustomRoleBindingMember) (string, string) {
+	links, _ := member.Links.(map[string]interface{})
+	for _, v := range links {
+		link, _ := v.(map[string]interface{})
+		href, _ := link["href"].(string)
+		if principalType, principalID := principalFromHref(href); principalID != "" {
+			return principalType, principalID
+		}
+	}
+	return "", ""
+}
+
+// principalFromHref returns the type and ID of the user or group an API URL
+// such as https://example.okta.com/api/v1/users/00u1234 refers to.
+func principalFromHref(href string) (string, string) {
+	for principalType, path := range map[string]string{userScope: "/api/v1/users/", groupScope: "/api/v1/groups/"} {
+		if i := strings.Index(href, path); i >= 0 {
+			id := strings.SplitN(href[i+len(path):], "/", 2)[0]
+			return principalType, strings.SplitN(id, "?", 2)[0]
+		}
+	}
+	return "", ""
+}
+
+// listAdminRoleTargets returns the apps and groups a standard role is limited
+// to, empty when the role isn't limited. An app target is the ID of an app
+// instance or the name of a catalog app, which covers all its instances.
+func listAdminRoleTargets(ctx context.Context, m interface{}, assignment *adminRoleAssignment) (apps, groups []string, err error) {
+	client := getOktaClientFromMetadata(m)
+	qp := &query.Params{Limit: defaultPaginationLimit}
+	if assignment.roleType == "APP_ADMIN" {
+		err = forEachPage(ctx, func() ([]*sdk.CatalogApplication, *sdk.Response, error) {
+			if assignment.principalType == groupScope {
+				return client.Group.ListApplicationTargetsForApplicationAdministratorRoleForGroup(ctx, assignment.principalID, assignment.roleID, qp)
+			}
+			return client.User.ListApplicationTargetsForApplicationAdministratorRoleForUser(ctx, assignment.principalID, assignment.roleID, qp)
+		}, func(app *sdk.CatalogApplication) bool {
+			if app.Id != "" {
+				apps = append(apps, app.Id)
+			} else {
+				apps = append(apps, app.Name)
+			}
+			return true
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list app targets of %s: %v", assignment, err)
+		}
+	}
+	if contains(rolesWithTargets, assignment.roleType) && assignment.roleType != "APP_ADMIN" {
+		err = forEachPage(ctx, func() ([]*sdk.Group, *sdk.Response, error) {
+			if assignment.principalType == groupScope {
+				return client.Group.ListGroupTargetsForGroupRole(ctx, assignment.principalID, assignment.roleID, qp)
+			}
+			return client.User.ListGroupTargetsForRole(ctx, assignment.principalID, assignment.roleID, qp)
+		}, func(group *sdk.Group) bool {
+			groups = append(groups, group.Id)
+			return true
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list group targets of %s: %v", assignment, err)
+		}
+	}
+	return apps, groups, nil
+}
+
+// principalHref returns the API URL of a user or group used as a member of a
+// custom role binding.
+func principalHref(m interface{}, principalType, principalID string) string {
+	orgURL := strings.TrimSuffix(getOktaClientFromMetadata(m).GetConfig().Okta.Client.OrgUrl, "/")
+	if principalType == groupScope {
+		return fmt.Sprintf("%s/api/v1/groups/%s", orgURL, principalID)
+	}
+	return fmt.Sprintf("%s/api/v1/users/%s", orgURL, principalID)
+}
+
+// assignAdminRole grants the role to the principal, a custom role is granted
+// by adding the principal to the role's binding on the resource set, creating
+// the binding when the role isn't bound to the resource set yet.
+func assignAdminRole(ctx context.Context, m interface{}, assignment *adminRoleAssignment) error {
+	client := getOktaClientFromMetadata(m)
+	if assignment.roleType == customRoleType {
+		supplement := getAPISupplementFromMetadata(m)
+		href := principalHref(m, assignment.principalType, assignment.principalID)
+		resp, err := supplement.AddResourceSetBindingMembers(ctx, assignment.resourceSetID, assignment.customRoleID,
+			sdk.AddCustomRoleBindingMemberRequest{Additions: []string{href}})
+		if is404(resp) {
+			_, err = supplement.CreateResourceSetBinding(ctx, assignment.resourceSetID,
+				sdk.CreateCustomRoleBindingRequest{Role: assignment.customRoleID, Members: []string{href}})
+		}
+		return err
+	}
+	var err error
+	if assignment.principalType == groupScope {
+		_, _, err = client.Group.AssignRoleToGroup(ctx, assignment.principalID, sdk.AssignRoleRequest{Type: assignment.roleType}, nil)
+	} else {
+		_, _, err = client.User.AssignRoleToUser(ctx, assignment.principalID, sdk.AssignRoleRequest{Type: assignment.roleType}, nil)
+	}
+	return err
+}
+
+// unassignAdminRole removes an assignment listed by listAdminRoleAssignments.
+func unassignAdminRole(ctx context.Context, m interface{}, assignment *adminRoleAssignment) error {
+	var (
+		resp *sdk.Response
+		err  error
+	)
+	switch {
+	case assignment.roleType == customRoleType:
+		resp, err = getAPISupplementFromMetadata(m).DeleteResourceSetBindingMember(ctx, assignment.resourceSetID, assignment.customRoleID, assignment.memberID)
+	case assignment.principalType == groupScope:
+		resp, err = getOktaClientFromMetadata(m).Group.RemoveRoleFromGroup(ctx, assignment.principalID, assignment.roleID)
+	default:
+		resp, err = getOktaClientFromMetadata(m).User.RemoveRoleFromUser(ctx, assignment.principalID, assignment.roleID)
+	}
+	return suppressErrorOn404(resp, err)
+}
@@ -313,6 +313,18 @@ func listApplicationGroupAssignments(ctx context.Context, client *sdk.Client, id
 	return groups, resp, nil
 }
 
+// listApplicationUsers returns every user assigned to the app, directly or
+// through a group, see the assignment's scope.
+func listApplicationUsers(ctx context.Context, client *sdk.Client, id string) ([]*sdk.AppUser, *sdk.Response, error) {
+	var resp *sdk.Response
+	users, err := collectPages(ctx, func() ([]*sdk.AppUser, *sdk.Response, error) {
+		users, firstResp, err := client.Application.ListApplicationUsers(ctx, id, &query.Params{Limit: maxAppUsersPageSize})
+		resp = firstResp
+		return users, firstResp, err
+	})
+	return users, resp, err
+}
+
 func handleAppLogo(ctx context.Context, d *schema.ResourceData, m interface{}, appID string, links interface{}) error {
 	l, ok := d.GetOk("logo")
 	if !ok {
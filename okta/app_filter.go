@@ -35,23 +35,17 @@ func listApps(ctx context.Context, client *sdk.Client, filters *appFilters, limi
 		params.Filter = filters.Status
 		params.Q = filters.getQ()
 	}
-	apps, resp, err := client.Application.ListApplications(ctx, params)
-	if err != nil {
-		return nil, err
-	}
-	resultingApps := make([]*sdk.Application, len(apps))
-	for i := range apps {
-		resultingApps[i] = apps[i].(*sdk.Application)
-	}
-	for resp.HasNextPage() {
-		var nextApps []*sdk.Application
-		resp, err = resp.Next(ctx, &nextApps)
+	return collectPages(ctx, func() ([]*sdk.Application, *sdk.Response, error) {
+		apps, resp, err := client.Application.ListApplications(ctx, params)
 		if err != nil {
-			return nil, err
+			return nil, resp, err
 		}
-		resultingApps = append(resultingApps, nextApps...)
-	}
-	return resultingApps, nil
+		resultingApps := make([]*sdk.Application, len(apps))
+		for i := range apps {
+			resultingApps[i] = apps[i].(*sdk.Application)
+		}
+		return resultingApps, resp, nil
+	})
 }
 
 func getAppFilters(d *schema.ResourceData) (*appFilters, error) {
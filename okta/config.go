@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -21,11 +22,13 @@ import (
 	"github.com/okta/terraform-provider-okta/okta/internal/apimutex"
 	"github.com/okta/terraform-provider-okta/okta/internal/transport"
 	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/okta/terraform-provider-okta/sdk/cache"
 )
 
 const (
 	OktaTerraformProviderVersion   = "4.3.0"
 	OktaTerraformProviderUserAgent = "okta-terraform/" + OktaTerraformProviderVersion
+	superAdminRole                 = "SUPER_ADMIN"
 )
 
 var (
@@ -56,6 +59,11 @@ type (
 		logLevel                int
 		requestTimeout          int
 		maxAPICapacity          int // experimental
+		requestCacheTTL         int
+		apiTokenRole            string
+		readOnly                bool
+		detectUpdateConflicts   bool
+		permissionTransport     *transport.PermissionTransport
 		oktaSDKClientV2         *sdk.Client
 		oktaSDKClientV3         *okta.APIClient
 		oktaSDKsupplementClient *sdk.APISupplement
@@ -187,6 +195,25 @@ func NewConfig(d *schema.ResourceData) *Config {
 		}
 	}
 
+	if val, ok := d.GetOk("request_cache_ttl"); ok {
+		config.requestCacheTTL = val.(int)
+	}
+
+	if val, ok := d.GetOk("api_token_role"); ok {
+		config.apiTokenRole = val.(string)
+	}
+	if config.apiTokenRole == "" && os.Getenv("OKTA_API_TOKEN_ROLE") != "" {
+		config.apiTokenRole = os.Getenv("OKTA_API_TOKEN_ROLE")
+	}
+
+	if val, ok := d.GetOk("read_only"); ok {
+		config.readOnly = val.(bool)
+	}
+
+	if val, ok := d.GetOk("detect_update_conflicts"); ok {
+		config.detectUpdateConflicts = val.(bool)
+	}
+
 	if httpProxy, ok := d.Get("http_proxy").(string); ok {
 		config.httpProxy = httpProxy
 	}
@@ -223,9 +250,13 @@ func (c *Config) SetTimeOperations(op TimeOperations) {
 	c.timeOperations = op
 }
 
-func (c *Config) resetHttpTransport(transport *http.RoundTripper) {
-	c.oktaSDKClientV3.GetConfig().HTTPClient.Transport = *transport
-	c.oktaSDKClientV2.GetConfig().HttpClient.Transport = *transport
+func (c *Config) resetHttpTransport(rt *http.RoundTripper) {
+	httpTransport := *rt
+	if c.readOnly {
+		httpTransport = transport.NewReadOnlyTransport(httpTransport, c.logger)
+	}
+	c.oktaSDKClientV3.GetConfig().HTTPClient.Transport = httpTransport
+	c.oktaSDKClientV2.GetConfig().HttpClient.Transport = httpTransport
 
 	re := c.oktaSDKClientV2.CloneRequestExecutor()
 	re.SetHTTPTransport(c.oktaSDKClientV3.GetConfig().HTTPClient.Transport)
@@ -277,11 +308,38 @@ func (c *Config) verifyCredentials(ctx context.Context) error {
 		if _, _, err := c.oktaSDKClientV2.User.GetUser(ctx, "me"); err != nil {
 			return fmt.Errorf("error with v2 SDK client: %v", err)
 		}
+		if c.apiTokenRole == "" {
+			c.detectAPITokenRole(ctx)
+		}
 	}
 
 	return nil
 }
 
+// detectAPITokenRole discovers the API token's role with a call to
+// GET /api/v1/users/me/roles and soft fails permission errors on known
+// permission gated endpoints when the token isn't a Super Admin token.
+func (c *Config) detectAPITokenRole(ctx context.Context) {
+	roles, resp, err := c.oktaSDKClientV2.User.ListAssignedRolesForUser(ctx, "me", nil)
+	if err != nil {
+		if resp != nil && (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden) {
+			c.logger.Warn("API token is not permitted to list its own admin roles, permission errors on known endpoints will be reported as warnings")
+			c.permissionTransport.SetSoftFail(true)
+			return
+		}
+		c.logger.Error("error querying GET /api/v1/users/me/roles", "error", err)
+		return
+	}
+	for _, role := range roles {
+		c.apiTokenRole = role.Type
+		if role.Type == superAdminRole {
+			break
+		}
+	}
+	c.logger.Info(fmt.Sprintf("detected API token role %q", c.apiTokenRole))
+	c.permissionTransport.SetSoftFail(c.apiTokenRole != superAdminRole)
+}
+
 func (c *Config) handleFrameworkDefaults(ctx context.Context, data *FrameworkProviderData) error {
 	var err error
 	if data.OrgName.IsNull() && os.Getenv("OKTA_ORG_NAME") != "" {
@@ -318,6 +376,9 @@ func (c *Config) handleFrameworkDefaults(ctx context.Context, data *FrameworkPro
 			data.BaseURL = types.StringValue(os.Getenv("OKTA_BASE_URL"))
 		}
 	}
+	if data.APITokenRole.IsNull() && os.Getenv("OKTA_API_TOKEN_ROLE") != "" {
+		data.APITokenRole = types.StringValue(os.Getenv("OKTA_API_TOKEN_ROLE"))
+	}
 	if data.HTTPProxy.IsNull() && os.Getenv("OKTA_HTTP_PROXY") != "" {
 		data.HTTPProxy = types.StringValue(os.Getenv("OKTA_HTTP_PROXY"))
 	}
@@ -339,6 +400,9 @@ func (c *Config) handleFrameworkDefaults(ctx context.Context, data *FrameworkPro
 	data.Parallelism = types.Int64Value(1)
 	data.LogLevel = types.Int64Value(int64(hclog.Error))
 	data.RequestTimeout = types.Int64Value(0)
+	if data.RequestCacheTTL.IsNull() {
+		data.RequestCacheTTL = types.Int64Value(0)
+	}
 
 	if os.Getenv("TF_LOG") != "" {
 		data.LogLevel = types.Int64Value(int64(hclog.LevelFromString(os.Getenv("TF_LOG"))))
@@ -447,6 +511,39 @@ func oktaV3SDKClient(c *Config) (client *okta.APIClient, err error) {
 		}
 		httpClient.Transport = transport.NewGovernedTransport(httpClient.Transport, apiMutex, c.logger)
 	}
+
+	// adds checking that objects read for an update weren't updated by someone
+	// else before being overwritten, beneath the cache so that an object is
+	// read again from the API right before it is overwritten
+	if c.detectUpdateConflicts {
+		c.logger.Info("running with update conflict detection")
+		httpClient.Transport = transport.NewConflictTransport(httpClient.Transport, c.logger)
+	}
+
+	// adds read cache to retryable or default client, cached GET responses are
+	// shared by the v2 and v3 SDK clients as they share this http client
+	if c.requestCacheTTL > 0 {
+		c.logger.Info(fmt.Sprintf("running with request cache, ttl %d seconds", c.requestCacheTTL))
+		requestCache := cache.NewGoCache(int32(c.requestCacheTTL), int32(c.requestCacheTTL))
+		httpClient.Transport = transport.NewCachingTransport(httpClient.Transport, requestCache, c.logger)
+	}
+
+	// adds soft failing of permission errors on known endpoints, enabled when
+	// the API token is known to have a lesser role than Super Admin
+	c.permissionTransport = transport.NewPermissionTransport(httpClient.Transport, c.logger)
+	if c.apiTokenRole != "" && c.apiTokenRole != superAdminRole {
+		c.logger.Info(fmt.Sprintf("running with api_token_role %q, permission errors on known endpoints will be reported as warnings", c.apiTokenRole))
+		c.permissionTransport.SetSoftFail(true)
+	}
+	httpClient.Transport = c.permissionTransport
+
+	// refuses any request that could change the org, e.g. for reviewing the
+	// exact requests an apply would make with production credentials
+	if c.readOnly {
+		c.logger.Info("running in read only mode, requests that could change the org will be refused")
+		httpClient.Transport = transport.NewReadOnlyTransport(httpClient.Transport, c.logger)
+	}
+
 	var orgUrl string
 	var disableHTTPS bool
 	if c.httpProxy != "" {
@@ -491,6 +588,12 @@ func oktaV3SDKClient(c *Config) (client *okta.APIClient, err error) {
 	}
 
 	config := okta.NewConfiguration(setters...)
+	if c.httpProxy != "" {
+		// the v3 SDK drops the port of the org URL, keep the one of the proxy
+		if proxyURL, err := url.Parse(orgUrl); err == nil {
+			config.Host = proxyURL.Host
+		}
+	}
 	client = okta.NewAPIClient(config)
 	return
 }
@@ -0,0 +1,110 @@
+package okta
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceAdminRoleAssignments() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAdminRoleAssignmentsRead,
+		Description: "Get every admin role assignment of the users and groups of the org.",
+		Schema: map[string]*schema.Schema{
+			"principal_type": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{userScope, groupScope}, false)),
+				Description:      "Only read the admin roles of principals of this type, USER or GROUP",
+			},
+			"skip_targets": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Do not read the apps and groups the standard roles are limited to",
+			},
+			"assignments": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Admin roles held directly by a user or group",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"principal_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"principal_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"role_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"role_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"custom_role_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"resource_set_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"target_apps": {
+							Type:     schema.TypeSet,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"target_groups": {
+							Type:     schema.TypeSet,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAdminRoleAssignmentsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	principalTypes := []string{userScope, groupScope}
+	if principalType, ok := d.GetOk("principal_type"); ok {
+		principalTypes = []string{principalType.(string)}
+	}
+	assignments, err := listAdminRoleAssignments(ctx, m, principalTypes...)
+	if err != nil {
+		return diag.Errorf("failed to list admin role assignments: %v", err)
+	}
+	arr := make([]map[string]interface{}, len(assignments))
+	for i, assignment := range assignments {
+		arr[i] = flattenAdminRoleAssignment(assignment)
+		arr[i]["principal_type"] = assignment.principalType
+		arr[i]["role_id"] = assignment.roleID
+	}
+	if !d.Get("skip_targets").(bool) {
+		err = forEachParallel(ctx, m, len(assignments), func(ctx context.Context, i int) error {
+			if assignment := assignments[i]; assignment.roleType != customRoleType {
+				apps, groups, err := listAdminRoleTargets(ctx, m, assignment)
+				if err != nil {
+					return err
+				}
+				arr[i]["target_apps"] = convertStringSliceToSet(apps)
+				arr[i]["target_groups"] = convertStringSliceToSet(groups)
+			}
+			return nil
+		})
+		if err != nil {
+			return diag.Errorf("failed to list admin role targets: %v", err)
+		}
+	}
+	d.SetId(strings.Join(principalTypes, ","))
+	_ = d.Set("assignments", arr)
+	return nil
+}
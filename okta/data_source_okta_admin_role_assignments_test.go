@@ -0,0 +1,90 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// adminRolesOrgHandler serves an org where user u1 is super admin, user u2 is
+// app admin of salesforce and of app 0oa1, group g1 is user admin of group
+// g2, and user u3 and group g2 hold custom role cr1 on resource set rs1.
+func adminRolesOrgHandler() http.Handler {
+	mux := http.NewServeMux()
+	respond := func(path, body string) {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, body)
+		})
+	}
+	mux.HandleFunc("/api/v1/iam/assignees/users", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("after") == "" {
+			_, _ = fmt.Fprintf(w, `{"value":[{"id":"u1"}],"_links":{"next":{"href":"http://%s/api/v1/iam/assignees/users?after=u1&limit=20"}}}`, r.Host)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"value":[{"id":"u2"}],"_links":{}}`)
+	})
+	respond("/api/v1/users/u1/roles", `[{"id":"ra1","type":"SUPER_ADMIN","assignmentType":"USER"},{"id":"ra9","type":"APP_ADMIN","assignmentType":"GROUP"}]`)
+	respond("/api/v1/users/u2/roles", `[{"id":"ra2","type":"APP_ADMIN","assignmentType":"USER"}]`)
+	respond("/api/v1/users/u2/roles/ra2/targets/catalog/apps", `[{"name":"salesforce"},{"id":"0oa1","name":"bookmark"}]`)
+	respond("/api/v1/groups", `[{"id":"g1"},{"id":"g2"}]`)
+	respond("/api/v1/groups/g1/roles", `[{"id":"ra3","type":"USER_ADMIN","assignmentType":"GROUP"}]`)
+	respond("/api/v1/groups/g2/roles", `[{"id":"ra4","type":"CUSTOM","assignmentType":"GROUP"}]`)
+	respond("/api/v1/groups/g1/roles/ra3/targets/groups", `[{"id":"g2"}]`)
+	respond("/api/v1/iam/resource-sets", `{"resource-sets":[{"id":"rs1"}]}`)
+	respond("/api/v1/iam/resource-sets/rs1/bindings", `{"roles":[{"id":"cr1"}]}`)
+	respond("/api/v1/iam/resource-sets/rs1/bindings/cr1/members", `{"members":[`+
+		`{"id":"m1","_links":{"self":{"href":"https://example.okta.com/api/v1/users/u3"}}},`+
+		`{"id":"m2","_links":{"self":{"href":"https://example.okta.com/api/v1/groups/g2"}}}]}`)
+	return mux
+}
+
+func TestDataSourceAdminRoleAssignmentsRead(t *testing.T) {
+	m := newTestConfig(t, adminRolesOrgHandler())
+	d := schema.TestResourceDataRaw(t, dataSourceAdminRoleAssignments().Schema, map[string]interface{}{})
+	if diags := dataSourceAdminRoleAssignmentsRead(context.Background(), d, m); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	sorted := func(set interface{}) []string {
+		list := convertInterfaceToStringSet(set)
+		sort.Strings(list)
+		return list
+	}
+	var got []string
+	for _, raw := range d.Get("assignments").([]interface{}) {
+		a := raw.(map[string]interface{})
+		got = append(got, fmt.Sprintf("%s %s %s %s %s %s apps=%v groups=%v", a["principal_type"], a["principal_id"], a["role_type"],
+			a["role_id"], a["custom_role_id"], a["resource_set_id"], sorted(a["target_apps"]), sorted(a["target_groups"])))
+	}
+	expected := []string{
+		"GROUP g1 USER_ADMIN ra3   apps=[] groups=[g2]",
+		"GROUP g2 CUSTOM  cr1 rs1 apps=[] groups=[]",
+		"USER u1 SUPER_ADMIN ra1   apps=[] groups=[]",
+		"USER u2 APP_ADMIN ra2   apps=[0oa1 salesforce] groups=[]",
+		"USER u3 CUSTOM  cr1 rs1 apps=[] groups=[]",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected assignments\n%v\ngot\n%v", expected, got)
+	}
+
+	d = schema.TestResourceDataRaw(t, dataSourceAdminRoleAssignments().Schema, map[string]interface{}{
+		"principal_type": "GROUP",
+		"skip_targets":   true,
+	})
+	if diags := dataSourceAdminRoleAssignmentsRead(context.Background(), d, m); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if n := d.Get("assignments.#").(int); n != 2 {
+		t.Errorf("expected the 2 group assignments, got %d", n)
+	}
+	if n := d.Get("assignments.0.target_groups.#").(int); n != 0 {
+		t.Errorf("expected targets to be skipped, got %d target groups", n)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/crewjam/saml"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -47,10 +48,85 @@ func dataSourceAppMetadataSaml() *schema.Resource {
 				Description: "Entity URL for instance https://www.okta.com/saml2/service-provider/sposcfdmlybtwkdcgtuf",
 				Computed:    true,
 			},
+			"metadata_url": {
+				Type:        schema.TypeString,
+				Description: "Public URL of the SAML xml metadata, serving the metadata of the active signing key",
+				Computed:    true,
+			},
+			"single_sign_on_services": {
+				Type:        schema.TypeList,
+				Description: "SSO endpoints of the IdP per binding",
+				Computed:    true,
+				Elem:        samlEndpointResource,
+			},
+			"single_logout_services": {
+				Type:        schema.TypeList,
+				Description: "SLO endpoints of the IdP per binding",
+				Computed:    true,
+				Elem:        samlEndpointResource,
+			},
+			"signing_certificates": {
+				Type:        schema.TypeList,
+				Description: "Every signing certificate of the SAML metadata",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"certificate": {
+							Type:        schema.TypeString,
+							Description: "Base64 encoded certificate",
+							Computed:    true,
+						},
+						"sha1_fingerprint": {
+							Type:        schema.TypeString,
+							Description: "SHA-1 fingerprint of the certificate, colon separated upper case hex",
+							Computed:    true,
+						},
+						"sha256_fingerprint": {
+							Type:        schema.TypeString,
+							Description: "SHA-256 fingerprint of the certificate, colon separated upper case hex",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"name_id_formats": {
+				Type:        schema.TypeList,
+				Description: "NameID formats supported by the IdP",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"shibboleth_config": {
+				Type:        schema.TypeString,
+				Description: "SSO and MetadataProvider elements for the Sessions of a Shibboleth SP shibboleth2.xml",
+				Computed:    true,
+			},
+			"adfs_config": {
+				Type:        schema.TypeString,
+				Description: "PowerShell command adding the IdP as an AD FS claims provider trust",
+				Computed:    true,
+			},
+			"simplesamlphp_config": {
+				Type:        schema.TypeString,
+				Description: "Entry of the IdP for a simpleSAMLphp saml20-idp-remote.php metadata file",
+				Computed:    true,
+			},
 		},
 	}
 }
 
+var samlEndpointResource = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"binding": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"location": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	},
+}
+
 func dataSourceAppMetadataSamlRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	id := d.Get("app_id").(string)
 	kid := d.Get("key_id").(string)
@@ -65,5 +141,54 @@ func dataSourceAppMetadataSamlRead(ctx context.Context, d *schema.ResourceData,
 	_ = d.Set("entity_id", metadataRoot.EntityID)
 	_ = d.Set("want_authn_requests_signed", desc.WantAuthnRequestsSigned)
 	_ = d.Set("certificate", desc.KeyDescriptors[0].KeyInfo.X509Data.X509Certificates[0].Data)
+	// the public metadata URL, the one of the API requires an API token
+	metadataURL := fmt.Sprintf("%s/app/%s/sso/saml/metadata", getOktaClientFromMetadata(m).GetConfig().Okta.Client.OrgUrl, id)
+	if err := setAppMetadataSamlDetails(d, metadataRoot, metadataURL); err != nil {
+		return diag.Errorf("failed to set app's SAML metadata details: %v", err)
+	}
 	return nil
 }
+
+// setAppMetadataSamlDetails sets the parsed fields of the metadata and the
+// configuration snippets rendered from them.
+func setAppMetadataSamlDetails(d *schema.ResourceData, metadataRoot *saml.EntityDescriptor, metadataURL string) error {
+	desc := metadataRoot.IDPSSODescriptors[0]
+	certs := samlSigningCertificates(desc.KeyDescriptors)
+	signingCertificates := make([]interface{}, len(certs))
+	for i, cert := range certs {
+		sha1Fingerprint, sha256Fingerprint, err := samlCertificateFingerprints(cert)
+		if err != nil {
+			return err
+		}
+		signingCertificates[i] = map[string]interface{}{
+			"certificate":        cert,
+			"sha1_fingerprint":   sha1Fingerprint,
+			"sha256_fingerprint": sha256Fingerprint,
+		}
+	}
+	nameIDFormats := make([]string, len(desc.NameIDFormats))
+	for i, format := range desc.NameIDFormats {
+		nameIDFormats[i] = string(format)
+	}
+	_ = d.Set("metadata_url", metadataURL)
+	_ = d.Set("name_id_formats", nameIDFormats)
+	_ = d.Set("shibboleth_config", samlShibbolethConfig(metadataRoot.EntityID, metadataURL))
+	_ = d.Set("adfs_config", samlADFSConfig(metadataRoot.EntityID, metadataURL))
+	_ = d.Set("simplesamlphp_config", samlSimpleSAMLphpConfig(metadataRoot.EntityID, desc.SingleSignOnServices, desc.SingleLogoutServices, certs, desc.NameIDFormats))
+	return setNonPrimitives(d, map[string]interface{}{
+		"single_sign_on_services": flattenSamlEndpoints(desc.SingleSignOnServices),
+		"single_logout_services":  flattenSamlEndpoints(desc.SingleLogoutServices),
+		"signing_certificates":    signingCertificates,
+	})
+}
+
+func flattenSamlEndpoints(endpoints []saml.Endpoint) []interface{} {
+	result := make([]interface{}, len(endpoints))
+	for i, endpoint := range endpoints {
+		result[i] = map[string]interface{}{
+			"binding":  endpoint.Binding,
+			"location": endpoint.Location,
+		}
+	}
+	return result
+}
@@ -1,9 +1,14 @@
 package okta
 
 import (
+	"encoding/xml"
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/crewjam/saml"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestAccDataSourceOktaAppMetadataSaml_read(t *testing.T) {
@@ -23,8 +28,91 @@ func TestAccDataSourceOktaAppMetadataSaml_read(t *testing.T) {
 					resource.TestCheckResourceAttrSet(resourceName, "http_post_binding"),
 					resource.TestCheckResourceAttrSet(resourceName, "metadata"),
 					resource.TestCheckResourceAttrSet(resourceName, "entity_id"),
+					resource.TestCheckResourceAttrSet(resourceName, "metadata_url"),
+					resource.TestCheckResourceAttr(resourceName, "signing_certificates.#", "1"),
+					resource.TestCheckResourceAttrSet(resourceName, "signing_certificates.0.sha256_fingerprint"),
+					resource.TestCheckResourceAttr(resourceName, "single_sign_on_services.#", "2"),
+					resource.TestCheckResourceAttrSet(resourceName, "shibboleth_config"),
 				),
 			},
 		},
 	})
 }
+
+const testSamlMetadata = `<?xml version="1.0" encoding="UTF-8"?><md:EntityDescriptor entityID="http://www.okta.com/exk9i6zkgyWqJEkz51d7" xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata"><md:IDPSSODescriptor WantAuthnRequestsSigned="false" protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol"><md:KeyDescriptor use="signing"><ds:KeyInfo xmlns:ds="http://www.w3.org/2000/09/xmldsig#"><ds:X509Data><ds:X509Certificate>MIIDujCCAqKgAwIBAgIGAYn7htfSMA0GCSqGSIb3DQEBCwUAMIGdMQswCQYDVQQGEwJVUzETMBEG
+A1UECAwKQ2FsaWZvcm5pYTEWMBQGA1UEBwwNU2FuIEZyYW5jaXNjbzENMAsGA1UECgwET2t0YTEU
+MBIGA1UECwwLU1NPUHJvdmlkZXIxHjAcBgNVBAMMFW1tLW9pZS0yMDIyLTEwLTA3LW1heDEcMBoG
+CSqGSIb3DQEJARYNaW5mb0Bva3RhLmNvbTAeFw0yMzA4MTUyMzI2NDVaFw0zMzA4MTUyMzI3NDVa
+MIGdMQswCQYDVQQGEwJVUzETMBEGA1UECAwKQ2FsaWZvcm5pYTEWMBQGA1UEBwwNU2FuIEZyYW5j
+aXNjbzENMAsGA1UECgwET2t0YTEUMBIGA1UECwwLU1NPUHJvdmlkZXIxHjAcBgNVBAMMFW1tLW9p
+ZS0yMDIyLTEwLTA3LW1heDEcMBoGCSqGSIb3DQEJARYNaW5mb0Bva3RhLmNvbTCCASIwDQYJKoZI
+hvcNAQEBBQADggEPADCCAQoCggEBAJY3wV2UwWLdNSrh9m6fBivns1b7YMmP2MW9GpjlzeHhkkDd
+QxKeuRHg9IoBbst9xS2hvMuKmwSRMRpnHaIe7JTJI2ubYbt/B6f+q/ipBm82HxTl777LqVTIM8ED
+0ICpwz+bw0b0FsbxABXb2fiPzh9wfbovnSf8O186UZH5axyfYi7HUwPaVAqrP9kpnrxkDAga2icU
+GAYsORD7WLPhz/+a8N2XBBB0nvSe0L7PqMvlFeXxvUrJcO+8Uft8ZI0TcMB9JNXPku6G6mAD3P9M
+qSr8ct9maVICCom8JOCqmAA1KjIthgb1oHxhAEWL6ADK6c1AS8RP5OCL+B1MYCkHehsCAwEAATAN
+BgkqhkiG9w0BAQsFAAOCAQEAJMw3nhXcXQZDhlB8YfTJk2swCXCWaKAzLn3+xDU0FYA/1+IsK/7s
+CSk0LTSGDKVgZ3ow6pYUDAJHefDncLatp1cHbbX89BDpJ4PJQCv3ROnwOkMMtA5x5H9Xg0CQaqDz
+/PuMbCWauG47DfQ5fgCmcCm7jeSGwwGaraQnOtmhh7qEzaegrCw7m8jG0caqxK/d+TEv8GUhK0i3
+9pUc3kqCbhsNHowkp9GZs4dtSdbF05SmkcIi+Rc/hU1Ok0V3du5TXR5F9mfCO/4Z1c3cp7UJitLo
+sTtVSZLwwOrpI9CqsEaUsxNZcI3B1GdedYX/SsWoryeuWz3Meb/HnHawAfmjQw==</ds:X509Certificate></ds:X509Data></ds:KeyInfo></md:KeyDescriptor><md:NameIDFormat>urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress</md:NameIDFormat><md:SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://example.okta.com/app/example_app_1/exk9i6zkgyWqJEkz51d7/slo/saml"/><md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://example.okta.com/app/example_app_1/exk9i6zkgyWqJEkz51d7/sso/saml"/><md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://example.okta.com/app/example_app_1/exk9i6zkgyWqJEkz51d7/sso/saml"/></md:IDPSSODescriptor></md:EntityDescriptor>`
+
+func TestAppMetadataSamlDetails(t *testing.T) {
+	var metadataRoot saml.EntityDescriptor
+	if err := xml.Unmarshal([]byte(testSamlMetadata), &metadataRoot); err != nil {
+		t.Fatalf("failed to parse metadata: %v", err)
+	}
+	metadataURL := "https://example.okta.com/app/0oa1234/sso/saml/metadata"
+	d := schema.TestResourceDataRaw(t, dataSourceAppMetadataSaml().Schema, map[string]interface{}{"app_id": "0oa1234"})
+	if err := setAppMetadataSamlDetails(d, &metadataRoot, metadataURL); err != nil {
+		t.Fatalf("failed to set details: %v", err)
+	}
+
+	expected := map[string]string{
+		"metadata_url":                              metadataURL,
+		"signing_certificates.#":                    "1",
+		"signing_certificates.0.sha1_fingerprint":   "DF:D4:6E:B1:FD:0C:6F:FA:13:6D:39:6D:45:9E:14:5E:1E:69:D6:9F",
+		"signing_certificates.0.sha256_fingerprint": "75:4F:FE:47:66:9C:49:EF:B2:DE:B5:6E:92:CE:81:99:84:52:67:E9:55:5E:68:0F:89:C0:CC:AF:CA:FF:2A:DD",
+		"name_id_formats.0":                         "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress",
+		"single_sign_on_services.#":                 "2",
+		"single_sign_on_services.1.binding":         saml.HTTPRedirectBinding,
+		"single_logout_services.#":                  "1",
+		"single_logout_services.0.location":         "https://example.okta.com/app/example_app_1/exk9i6zkgyWqJEkz51d7/slo/saml",
+	}
+	for key, value := range expected {
+		if got := fmt.Sprint(d.Get(key)); got != value {
+			t.Errorf("expected %s to be %q, got %v", key, value, got)
+		}
+	}
+	cert := d.Get("signing_certificates.0.certificate").(string)
+	if strings.ContainsAny(cert, " \n") || !strings.HasPrefix(cert, "MIIDujCCAqKgAwIBAgIGAYn7htfSMA0") {
+		t.Errorf("unexpected certificate %q", cert)
+	}
+	for _, key := range []string{"shibboleth_config", "adfs_config", "simplesamlphp_config"} {
+		snippet := d.Get(key).(string)
+		if !strings.Contains(snippet, "http://www.okta.com/exk9i6zkgyWqJEkz51d7") {
+			t.Errorf("expected %s to contain the entity ID, got %s", key, snippet)
+		}
+	}
+	for _, key := range []string{"shibboleth_config", "adfs_config"} {
+		if snippet := d.Get(key).(string); !strings.Contains(snippet, metadataURL) {
+			t.Errorf("expected %s to contain the metadata URL, got %s", key, snippet)
+		}
+	}
+	if snippet := d.Get("simplesamlphp_config").(string); !strings.Contains(snippet, "'X509Certificate' => '"+cert+"'") ||
+		!strings.Contains(snippet, "'SingleLogoutService' => [") {
+		t.Errorf("unexpected simplesamlphp_config %s", snippet)
+	}
+}
+
+func TestSamlSimpleSAMLphpConfigEscaping(t *testing.T) {
+	snippet := samlSimpleSAMLphpConfig(`https://idp.example.com/it's\`, []saml.Endpoint{{Binding: saml.HTTPPostBinding, Location: "https://example.okta.com/sso?a='b'"}}, nil, nil, nil)
+	for _, expected := range []string{
+		`$metadata['https://idp.example.com/it\'s\\'] = [`,
+		`'Location' => 'https://example.okta.com/sso?a=\'b\''`,
+	} {
+		if !strings.Contains(snippet, expected) {
+			t.Errorf("expected %s to contain %s", snippet, expected)
+		}
+	}
+}
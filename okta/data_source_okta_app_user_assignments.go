@@ -5,8 +5,6 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/okta/terraform-provider-okta/sdk"
-	"github.com/okta/terraform-provider-okta/sdk/query"
 )
 
 func dataSourceAppUserAssignments() *schema.Resource {
@@ -33,20 +31,11 @@ func dataSourceAppUserAssignmentsRead(ctx context.Context, d *schema.ResourceDat
 	client := getOktaClientFromMetadata(m)
 	id := d.Get("id").(string)
 
-	userAssignments, resp, err := client.Application.ListApplicationUsers(ctx, id, &query.Params{})
+	userAssignments, _, err := listApplicationUsers(ctx, client, id)
 	if err != nil {
 		return diag.Errorf("unable to query for users from app (%s): %s", id, err)
 	}
 
-	for resp.HasNextPage() {
-		var moreAssignments []*sdk.AppUser
-		resp, err = resp.Next(ctx, &moreAssignments)
-		if err != nil {
-			return diag.Errorf("unable to query for users from app (%s): %s", id, err)
-		}
-		userAssignments = append(userAssignments, moreAssignments...)
-	}
-
 	var users []string
 	for _, assignment := range userAssignments {
 		users = append(users, assignment.Id)
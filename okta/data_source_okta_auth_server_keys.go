@@ -0,0 +1,107 @@
+package okta
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+func dataSourceAuthServerKeys() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAuthServerKeysRead,
+		Schema: map[string]*schema.Schema{
+			"auth_server_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Auth server ID",
+			},
+			"keys": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The ACTIVE, NEXT and EXPIRED signing keys of the auth server as JSON Web Keys",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"kid": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"use": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"alg": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"kty": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"n": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"e": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"x5c": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"created": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"expires_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAuthServerKeysRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	authServerID := d.Get("auth_server_id").(string)
+	keys, _, err := getOktaClientFromMetadata(m).AuthorizationServer.ListAuthorizationServerKeys(ctx, authServerID)
+	if err != nil {
+		return diag.Errorf("failed to list auth server keys: %v", err)
+	}
+	arr := make([]map[string]interface{}, len(keys))
+	for i := range keys {
+		arr[i] = flattenJsonWebKey(keys[i])
+	}
+	_ = d.Set("keys", arr)
+	d.SetId(authServerID)
+	return nil
+}
+
+func flattenJsonWebKey(key *sdk.JsonWebKey) map[string]interface{} {
+	k := map[string]interface{}{
+		"kid":    key.Kid,
+		"status": key.Status,
+		"use":    key.Use,
+		"alg":    key.Alg,
+		"kty":    key.Kty,
+		"n":      key.N,
+		"e":      key.E,
+		"x5c":    key.X5c,
+	}
+	if key.Created != nil {
+		k["created"] = key.Created.String()
+	}
+	if key.ExpiresAt != nil {
+		k["expires_at"] = key.ExpiresAt.String()
+	}
+	return k
+}
@@ -0,0 +1,105 @@
+package okta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+func dataSourceAuthServerTokenPreview() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAuthServerTokenPreviewRead,
+		Schema: map[string]*schema.Schema{
+			"auth_server_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Auth server ID",
+			},
+			"client_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Client ID of the OAuth app the tokens are minted for",
+			},
+			"user_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "ID of the user the tokens are minted for",
+			},
+			"scopes": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Scopes requested",
+			},
+			"grant_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     authorizationCode,
+				Description: "Grant type of the token request: authorization_code, implicit, password or client_credentials",
+			},
+			"id_token_claims": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Claims of the ID token, values that aren't strings are JSON encoded",
+			},
+			"access_token_claims": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Claims of the access token, values that aren't strings are JSON encoded",
+			},
+		},
+	}
+}
+
+func dataSourceAuthServerTokenPreviewRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	authServerID := d.Get("auth_server_id").(string)
+	clientID := d.Get("client_id").(string)
+	userID := d.Get("user_id").(string)
+	scopes := convertInterfaceToStringSet(d.Get("scopes"))
+	sort.Strings(scopes)
+	preview, _, err := getAPISupplementFromMetadata(m).PreviewAuthorizationServerTokens(ctx, authServerID, clientID, sdk.TokenPreviewRequest{
+		UserID:    userID,
+		GrantType: d.Get("grant_type").(string),
+		Scopes:    scopes,
+	})
+	if err != nil {
+		return diag.Errorf("failed to preview tokens of client '%s' of auth server '%s', the token preview endpoint is undocumented and may have changed: %v", clientID, authServerID, err)
+	}
+	idTokenClaims, err := flattenTokenClaims(preview.IDToken)
+	if err != nil {
+		return diag.Errorf("failed to flatten ID token claims: %v", err)
+	}
+	accessTokenClaims, err := flattenTokenClaims(preview.AccessToken)
+	if err != nil {
+		return diag.Errorf("failed to flatten access token claims: %v", err)
+	}
+	_ = d.Set("id_token_claims", idTokenClaims)
+	_ = d.Set("access_token_claims", accessTokenClaims)
+	d.SetId(fmt.Sprintf("%s/%s/%s", authServerID, clientID, userID))
+	return nil
+}
+
+// flattenTokenClaims returns the claims of a token as strings, JSON encoding
+// the values that aren't strings, e.g. the groups claim.
+func flattenTokenClaims(claims map[string]interface{}) (map[string]interface{}, error) {
+	flattened := make(map[string]interface{}, len(claims))
+	for name, value := range claims {
+		if s, ok := value.(string); ok {
+			flattened[name] = s
+			continue
+		}
+		b, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("claim '%s': %w", name, err)
+		}
+		flattened[name] = string(b)
+	}
+	return flattened, nil
+}
@@ -0,0 +1,97 @@
+package okta
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/okta/terraform-provider-okta/okta/internal/mockokta"
+)
+
+func TestAccDataSourceOktaAuthServerTokenPreview_read(t *testing.T) {
+	mgr := newFixtureManager(authServerTokenPreview, t.Name())
+	config := mgr.GetFixtures("datasource.tf", t)
+	dataSourceName := fmt.Sprintf("data.%s.test", authServerTokenPreview)
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(dataSourceName, "id_token_claims.sub", "okta_user.test", "id"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "id_token_claims.aud", "okta_app_oauth.test", "client_id"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "access_token_claims.cid", "okta_app_oauth.test", "client_id"),
+					resource.TestCheckResourceAttr(dataSourceName, "access_token_claims.scp", `["openid","profile"]`),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceAuthServerTokenPreviewRead(t *testing.T) {
+	server := mockokta.NewServer()
+	t.Cleanup(server.Close)
+	post := func(path, body string) map[string]interface{} {
+		req, _ := http.NewRequest(http.MethodPost, server.URL+path, bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "SSWS token")
+		req.Header.Set("Content-Type", "application/json")
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		var v map[string]interface{}
+		if err := json.NewDecoder(res.Body).Decode(&v); err != nil || res.StatusCode != http.StatusOK {
+			t.Fatalf("POST %s failed with %d: %v", path, res.StatusCode, v)
+		}
+		return v
+	}
+	user := post("/api/v1/users?activate=true", `{"profile":{"login":"jane@example.com","email":"jane@example.com","firstName":"Jane","lastName":"Doe","department":"R&D"}}`)
+	for _, name := range []string{"Eng-Admins", "Eng-Users", "Sales"} {
+		group := post("/api/v1/groups", fmt.Sprintf(`{"profile":{"name":%q}}`, name))
+		if name != "Eng-Users" {
+			req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/api/v1/groups/%s/users/%s", server.URL, group["id"], user["id"]), nil)
+			req.Header.Set("Authorization", "SSWS token")
+			if _, err := http.DefaultClient.Do(req); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	app := post("/api/v1/apps", `{"label":"web","signOnMode":"OPENID_CONNECT"}`)
+	post("/api/v1/authorizationServers/default/scopes", `{"name":"groups"}`)
+	post("/api/v1/authorizationServers/default/claims", `{"name":"groups","claimType":"IDENTITY","valueType":"GROUPS","groupFilterType":"STARTS_WITH","value":"Eng","conditions":{"scopes":["groups"]}}`)
+	post("/api/v1/authorizationServers/default/claims", `{"name":"department","claimType":"RESOURCE","valueType":"EXPRESSION","value":"user.department","conditions":{"scopes":[]}}`)
+
+	m := newTestConfig(t, server)
+	d := dataSourceAuthServerTokenPreview().Data(nil)
+	_ = d.Set("auth_server_id", "default")
+	_ = d.Set("client_id", app["id"])
+	_ = d.Set("user_id", user["id"])
+	_ = d.Set("scopes", convertStringSliceToSet([]string{"openid", "groups"}))
+	_ = d.Set("grant_type", authorizationCode)
+	if diags := dataSourceAuthServerTokenPreviewRead(context.Background(), d, m); diags.HasError() {
+		t.Fatalf("failed to preview tokens: %v", diags)
+	}
+	idTokenClaims := d.Get("id_token_claims").(map[string]interface{})
+	accessTokenClaims := d.Get("access_token_claims").(map[string]interface{})
+	if idTokenClaims["groups"] != `["Eng-Admins"]` || idTokenClaims["sub"] != user["id"] {
+		t.Errorf("expected the groups claim of the ID token to hold the Eng groups of the user, got %v", idTokenClaims)
+	}
+	if accessTokenClaims["department"] != "R&D" || accessTokenClaims["scp"] != `["groups","openid"]` {
+		t.Errorf("expected the department claim and the scopes in the access token, got %v", accessTokenClaims)
+	}
+
+	_ = d.Set("scopes", convertStringSliceToSet([]string{"openid", "unknown"}))
+	diags := dataSourceAuthServerTokenPreviewRead(context.Background(), d, m)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "unknown") {
+		t.Errorf("expected an error for the unknown scope, got %v", diags)
+	}
+}
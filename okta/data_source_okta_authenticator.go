@@ -71,6 +71,34 @@ func dataSourceAuthenticator() *schema.Resource {
 				Computed:    true,
 				Description: "Format expected by the provider",
 			},
+			"include_methods": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Read the methods of the authenticator",
+			},
+			"methods": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Methods of the authenticator, read when include_methods is true",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"settings": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Method settings in JSON format",
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -134,6 +162,22 @@ func dataSourceAuthenticatorRead(ctx context.Context, d *schema.ResourceData, m
 			_ = d.Set("provider_user_name_template", authenticator.Provider.Configuration.UserNameTemplate.Template)
 		}
 	}
+	if d.Get("include_methods").(bool) {
+		methods, _, err := getAPISupplementFromMetadata(m).ListAuthenticatorMethods(ctx, authenticator.Id)
+		if err != nil {
+			return diag.Errorf("failed to list methods of authenticator (%s): %v", authenticator.Id, err)
+		}
+		arr := make([]map[string]interface{}, len(methods))
+		for i, method := range methods {
+			settings, _ := flattenAuthenticatorMethodSettings("", method.Settings)
+			arr[i] = map[string]interface{}{
+				"type":     method.Type,
+				"status":   method.Status,
+				"settings": settings,
+			}
+		}
+		_ = d.Set("methods", arr)
+	}
 	return nil
 }
 
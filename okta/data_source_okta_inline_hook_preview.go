@@ -0,0 +1,67 @@
+package okta
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
+)
+
+func dataSourceInlineHookPreview() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceInlineHookPreviewRead,
+		Schema: map[string]*schema.Schema{
+			"inline_hook_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Inline hook ID",
+			},
+			"payload": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: stringIsJSON,
+				Description:      "JSON payload sent to the hook, a sample payload of the type of the hook by default",
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Type of the inline hook",
+			},
+			"response": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "JSON response of the hook",
+			},
+		},
+	}
+}
+
+func dataSourceInlineHookPreviewRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id := d.Get("inline_hook_id").(string)
+	hook, _, err := getOktaClientFromMetadata(m).InlineHook.GetInlineHook(ctx, id)
+	if err != nil {
+		return diag.Errorf("failed to get inline hook: %v", err)
+	}
+	payload := d.Get("payload").(string)
+	if payload == "" {
+		contract, ok := inlineHookContracts[hook.Type]
+		if !ok {
+			return diag.Errorf("there is no sample payload for inline hooks of type '%s', set the payload", hook.Type)
+		}
+		payload = contract.samplePayload
+	}
+	response, _, err := getAPISupplementFromMetadata(m).PreviewInlineHook(ctx, id, json.RawMessage(payload))
+	if err != nil {
+		return diag.Errorf("failed to preview inline hook: %v", err)
+	}
+	normalized, err := structure.NormalizeJsonString(string(response))
+	if err != nil {
+		return diag.Errorf("failed to read the response of inline hook: %v", err)
+	}
+	d.SetId(id)
+	_ = d.Set("type", hook.Type)
+	_ = d.Set("response", normalized)
+	return nil
+}
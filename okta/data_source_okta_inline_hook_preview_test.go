@@ -0,0 +1,110 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/okta/terraform-provider-okta/okta/internal/hookreceiver"
+	"github.com/okta/terraform-provider-okta/okta/internal/mockokta"
+)
+
+// registrationCommands are the commands of a registration inline hook
+// denying the registration.
+var registrationCommands = map[string]interface{}{
+	"commands": []interface{}{map[string]interface{}{
+		"type":  "com.okta.action.update",
+		"value": map[string]interface{}{"registration": "DENY"},
+	}},
+}
+
+// TestAccDataSourceOktaInlineHookPreview_read previews a hook of a local
+// receiver, which only the mock Okta org can reach.
+func TestAccDataSourceOktaInlineHookPreview_read(t *testing.T) {
+	if os.Getenv("OKTA_MOCK_TF_ACC") == "" {
+		t.Skipf("%q test is only run against the mock Okta org, see .github/CONTRIBUTING.md#acceptance-tests-with-the-mock-okta-org", t.Name())
+	}
+	receiver := hookreceiver.New(hookreceiver.Config{AuthKey: "Authorization", AuthValue: "secret", Response: registrationCommands})
+	t.Cleanup(receiver.Close)
+	dataSourceName := fmt.Sprintf("data.%s.test", inlineHookPreview)
+	config := fmt.Sprintf(`
+resource "okta_inline_hook" "test" {
+  name    = "testAcc_%d"
+  type    = "com.okta.user.pre-registration"
+  version = "1.0.3"
+  channel = {
+    type    = "HTTP"
+    version = "1.0.0"
+    uri     = %q
+    method  = "POST"
+  }
+  auth = {
+    type  = "HEADER"
+    key   = "Authorization"
+    value = "secret"
+  }
+}
+
+data "okta_inline_hook_preview" "test" {
+  inline_hook_id = okta_inline_hook.test.id
+}`, acctest.RandInt(), receiver.URL)
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "type", "com.okta.user.pre-registration"),
+					resource.TestCheckResourceAttr(dataSourceName, "response", `{"commands":[{"type":"com.okta.action.update","value":{"registration":"DENY"}}]}`),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceInlineHookPreviewRead(t *testing.T) {
+	server := mockokta.NewServer()
+	t.Cleanup(server.Close)
+	receiver := hookreceiver.New(hookreceiver.Config{AuthKey: "Authorization", AuthValue: "secret", Response: registrationCommands})
+	t.Cleanup(receiver.Close)
+	m := newTestConfig(t, server)
+	ctx := context.Background()
+
+	hook := resourceInlineHook().Data(nil)
+	_ = hook.Set("name", "test")
+	_ = hook.Set("status", statusActive)
+	_ = hook.Set("type", "com.okta.user.pre-registration")
+	_ = hook.Set("version", "1.0.3")
+	_ = hook.Set("channel", map[string]interface{}{"type": "HTTP", "version": "1.0.0", "uri": receiver.URL, "method": "POST"})
+	_ = hook.Set("auth", map[string]interface{}{"type": "HEADER", "key": "Authorization", "value": "secret"})
+	if diags := resourceInlineHookCreate(ctx, hook, m); diags.HasError() {
+		t.Fatalf("failed to create inline hook: %v", diags)
+	}
+
+	d := dataSourceInlineHookPreview().Data(nil)
+	_ = d.Set("inline_hook_id", hook.Id())
+	if diags := dataSourceInlineHookPreviewRead(ctx, d, m); diags.HasError() {
+		t.Fatalf("failed to preview inline hook: %v", diags)
+	}
+	if response := d.Get("response"); response != `{"commands":[{"type":"com.okta.action.update","value":{"registration":"DENY"}}]}` {
+		t.Errorf("expected the commands of the hook, got %v", response)
+	}
+	payloads := receiver.Payloads()
+	if len(payloads) != 1 || payloads[0]["eventType"] != "com.okta.user.pre-registration" {
+		t.Fatalf("expected the sample payload of the registration hooks to be sent, got %v", payloads)
+	}
+
+	_ = d.Set("payload", `{"eventType":"com.okta.user.pre-registration","data":{"userProfile":{"login":"john@example.com"}}}`)
+	if diags := dataSourceInlineHookPreviewRead(ctx, d, m); diags.HasError() {
+		t.Fatalf("failed to preview inline hook: %v", diags)
+	}
+	if payloads = receiver.Payloads(); len(payloads) != 2 || fmt.Sprint(payloads[1]["data"]) != "map[userProfile:map[login:john@example.com]]" {
+		t.Errorf("expected the payload to be sent, got %v", payloads)
+	}
+}
@@ -0,0 +1,238 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/okta/terraform-provider-okta/sdk/query"
+)
+
+func dataSourceSystemLogs() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceSystemLogsRead,
+		Schema: map[string]*schema.Schema{
+			"since": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Filters the lower time bound of the log events published property, ISO 8601 format. Defaults to 7 days ago.",
+			},
+			"until": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Filters the upper time bound of the log events published property, ISO 8601 format. Defaults to now.",
+			},
+			"filter": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Filter expression that filters the results, e.g. `eventType eq \"policy.lifecycle.update\" and outcome.result eq \"FAILURE\"`",
+			},
+			"q": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Filters the log events results by one or more exact keywords",
+			},
+			"sort_order": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "ASCENDING",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"ASCENDING", "DESCENDING"}, false)),
+				Description:      "The order of the returned events sorted by published: ASCENDING or DESCENDING",
+			},
+			"max_events": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          100,
+				ValidateDiagFunc: intBetween(1, 10000),
+				Description:      "Maximum number of log events to return",
+			},
+			"logs": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "List of log events",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"uuid": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"published": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"event_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"legacy_event_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"display_message": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"severity": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"actor": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     systemLogEntityResource,
+						},
+						"targets": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     systemLogEntityResource,
+						},
+						"outcome": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"result": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"reason": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+								},
+							},
+						},
+						"client_ip_address": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"transaction_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+var systemLogEntityResource = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"type": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"alternate_id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"display_name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	},
+}
+
+func dataSourceSystemLogsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	maxEvents := d.Get("max_events").(int)
+	qp := &query.Params{
+		Since:     d.Get("since").(string),
+		Until:     d.Get("until").(string),
+		Filter:    d.Get("filter").(string),
+		Q:         d.Get("q").(string),
+		SortOrder: d.Get("sort_order").(string),
+		Limit:     int64(maxEvents),
+	}
+	if qp.Limit > 1000 {
+		qp.Limit = 1000
+	}
+	logs, err := listSystemLogs(ctx, getOktaClientFromMetadata(m), qp, maxEvents)
+	if err != nil {
+		return diag.Errorf("failed to list system logs: %v", err)
+	}
+	d.SetId(fmt.Sprintf("%d", crc32.ChecksumIEEE([]byte(qp.String()))))
+	arr := make([]map[string]interface{}, len(logs))
+	for i, log := range logs {
+		arr[i] = flattenSystemLog(log)
+	}
+	err = d.Set("logs", arr)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// listSystemLogs pages through the system log until there are no more events
+// or maxEvents have been collected. The system log always returns a next link
+// when until isn't set, paging ends on its empty page.
+func listSystemLogs(ctx context.Context, client *sdk.Client, qp *query.Params, maxEvents int) ([]*sdk.LogEvent, error) {
+	var logs []*sdk.LogEvent
+	err := forEachPage(ctx, func() ([]*sdk.LogEvent, *sdk.Response, error) {
+		return client.LogEvent.GetLogs(ctx, qp)
+	}, func(log *sdk.LogEvent) bool {
+		logs = append(logs, log)
+		return len(logs) < maxEvents
+	})
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+func flattenSystemLog(log *sdk.LogEvent) map[string]interface{} {
+	m := map[string]interface{}{
+		"uuid":              log.Uuid,
+		"event_type":        log.EventType,
+		"legacy_event_type": log.LegacyEventType,
+		"display_message":   log.DisplayMessage,
+		"severity":          log.Severity,
+	}
+	if log.Published != nil {
+		m["published"] = log.Published.Format(time.RFC3339)
+	}
+	if log.Actor != nil {
+		m["actor"] = []interface{}{
+			map[string]interface{}{
+				"id":           log.Actor.Id,
+				"type":         log.Actor.Type,
+				"alternate_id": log.Actor.AlternateId,
+				"display_name": log.Actor.DisplayName,
+			},
+		}
+	}
+	targets := make([]interface{}, len(log.Target))
+	for i, target := range log.Target {
+		targets[i] = map[string]interface{}{
+			"id":           target.Id,
+			"type":         target.Type,
+			"alternate_id": target.AlternateId,
+			"display_name": target.DisplayName,
+		}
+	}
+	m["targets"] = targets
+	if log.Outcome != nil {
+		m["outcome"] = []interface{}{
+			map[string]interface{}{
+				"result": log.Outcome.Result,
+				"reason": log.Outcome.Reason,
+			},
+		}
+	}
+	if log.Client != nil {
+		m["client_ip_address"] = log.Client.IpAddress
+	}
+	if log.Transaction != nil {
+		m["transaction_id"] = log.Transaction.Id
+	}
+	return m
+}
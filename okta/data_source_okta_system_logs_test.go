@@ -0,0 +1,81 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceOktaSystemLogs_read(t *testing.T) {
+	mgr := newFixtureManager(systemLogs, t.Name())
+	config := mgr.GetFixtures("datasource.tf", t)
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.okta_system_logs.test", "id"),
+					resource.TestCheckResourceAttrSet("data.okta_system_logs.test", "logs.#"),
+					resource.TestCheckResourceAttr("data.okta_system_logs.test", "logs.0.event_type", "group.lifecycle.create"),
+					resource.TestCheckResourceAttr("data.okta_system_logs.test", "logs.0.outcome.0.result", "SUCCESS"),
+				),
+			},
+		},
+	})
+}
+
+// TestDataSourceSystemLogsRead reads a system log of two pages of two events
+// followed by the empty page the system log ends with.
+func TestDataSourceSystemLogsRead(t *testing.T) {
+	var queries []string
+	m := newTestConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		page := len(queries)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s/api/v1/logs?after=%d>; rel="next"`, r.Host, page))
+		if page > 2 {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		fmt.Fprintf(w, `[{"uuid":"%d-1","eventType":"group.lifecycle.create","outcome":{"result":"SUCCESS"}},{"uuid":"%d-2","eventType":"group.lifecycle.delete"}]`, page, page)
+	}))
+	ds := dataSourceSystemLogs()
+
+	d := ds.Data(nil)
+	_ = d.Set("sort_order", "DESCENDING")
+	_ = d.Set("max_events", 10)
+	if diags := ds.ReadContext(context.Background(), d, m); diags.HasError() {
+		t.Fatalf("failed to read system logs: %v", diags)
+	}
+	if len(queries) != 3 || queries[0] != "limit=10&sortOrder=DESCENDING" {
+		t.Errorf("expected the events to be paged in descending order until the empty page, got %v", queries)
+	}
+	if d.Get("logs.#") != 4 || d.Get("logs.0.uuid") != "1-1" || d.Get("logs.0.outcome.0.result") != "SUCCESS" {
+		t.Errorf("expected the 4 events, got %v", d.Get("logs"))
+	}
+
+	queries = nil
+	d = ds.Data(nil)
+	_ = d.Set("max_events", 3)
+	if diags := ds.ReadContext(context.Background(), d, m); diags.HasError() {
+		t.Fatalf("failed to read system logs: %v", diags)
+	}
+	if len(queries) != 2 || d.Get("logs.#") != 3 {
+		t.Errorf("expected paging to stop at 3 events, got %d events in %d pages", d.Get("logs.#"), len(queries))
+	}
+
+	for sortOrder, valid := range map[string]bool{"ASCENDING": true, "DESCENDING": true, "descending": false, "NEWEST": false} {
+		diags := ds.Schema["sort_order"].ValidateDiagFunc(sortOrder, cty.GetAttrPath("sort_order"))
+		if diags.HasError() == valid {
+			t.Errorf("expected sort_order %q valid to be %v, got %v", sortOrder, valid, diags)
+		}
+	}
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"hash/crc32"
+	"math"
 	"strconv"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/okta/terraform-provider-okta/sdk"
 	"github.com/okta/terraform-provider-okta/sdk/query"
 )
@@ -21,19 +23,53 @@ func dataSourceUsers() *schema.Resource {
 				Type:          schema.TypeString,
 				Optional:      true,
 				Description:   "Find users based on group membership using the id of the group.",
-				ConflictsWith: []string{"search"},
+				ConflictsWith: []string{"search", "sort_by"},
 			},
 			"include_groups": {
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Default:     false,
-				Description: "Fetch group memberships for each user",
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       false,
+				Description:   "Fetch group memberships for each user",
+				ConflictsWith: []string{"ids_only"},
 			},
 			"include_roles": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       false,
+				Description:   "Fetch user roles for each user",
+				ConflictsWith: []string{"ids_only"},
+			},
+			"attributes": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				Description:   "Profile attributes to set for each user, other attributes are left empty. Standard attributes are named as the user attributes, e.g. `department`, custom attributes as in the profile. The login and status are always set.",
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				ConflictsWith: []string{"ids_only"},
+			},
+			"ids_only": {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Default:     false,
-				Description: "Fetch user roles for each user",
+				Description: "Only set the ID and login of each user",
+			},
+			"max_results": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Description:      "Fail instead of reading more than this many users",
+				ValidateDiagFunc: intBetween(1, math.MaxInt32),
+			},
+			"sort_by": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Attribute the search results are sorted by, e.g. `id`, `created` or `profile.lastName`",
+				ConflictsWith: []string{"group_id"},
+			},
+			"sort_order": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "asc",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"asc", "desc"}, false)),
+				Description:      "Order of the search results sorted by `sort_by`, `asc` or `desc`",
 			},
 			"search": {
 				Type:          schema.TypeSet,
@@ -91,13 +127,24 @@ func dataSourceUsersRead(ctx context.Context, d *schema.ResourceData, m interfac
 
 	client := getOktaClientFromMetadata(m)
 
+	maxResults := d.Get("max_results").(int)
 	if groupId, ok := d.GetOk("group_id"); ok {
 		id = groupId.(string)
-		users, err = listGroupUsers(ctx, m, id)
+		params := &query.Params{Limit: usersPageSize(maxGroupUsersPageSize, maxResults)}
+		users, err = collectUsersWithin(ctx, func() ([]*sdk.User, *sdk.Response, error) {
+			return client.Group.ListGroupUsers(ctx, id, params)
+		}, maxResults)
 	} else if _, ok := d.GetOk("search"); ok {
-		params := &query.Params{Search: getSearchCriteria(d), Limit: defaultPaginationLimit, SortOrder: "0"}
+		params := &query.Params{Search: getSearchCriteria(d), Limit: maxUsersPageSize, SortOrder: "0"}
+		if sortBy, ok := d.GetOk("sort_by"); ok {
+			params.SortBy = sortBy.(string)
+			params.SortOrder = d.Get("sort_order").(string)
+		}
 		id = fmt.Sprintf("%d", crc32.ChecksumIEEE([]byte(params.String())))
-		users, err = collectUsers(ctx, client, params)
+		params.Limit = usersPageSize(maxUsersPageSize, maxResults)
+		users, err = collectUsersWithin(ctx, func() ([]*sdk.User, *sdk.Response, error) {
+			return client.User.ListUsers(ctx, params)
+		}, maxResults)
 	} else {
 		return diag.Errorf("must specify either group_id or search attributes")
 	}
@@ -106,45 +153,115 @@ func dataSourceUsersRead(ctx context.Context, d *schema.ResourceData, m interfac
 		return diag.Errorf("failed to list users: %v", err)
 	}
 	d.SetId(id)
+	if d.Get("ids_only").(bool) {
+		arr := make([]map[string]interface{}, len(users))
+		for i, user := range users {
+			arr[i] = map[string]interface{}{"id": user.Id}
+			if user.Profile != nil {
+				arr[i]["login"] = (*user.Profile)["login"]
+			}
+		}
+		_ = d.Set("users", arr)
+		return nil
+	}
 	includeGroups := d.Get("include_groups").(bool)
 	includeRoles := d.Get("include_roles").(bool)
+	attributes, projected := d.GetOk("attributes")
 	arr := make([]map[string]interface{}, len(users))
 	for i, user := range users {
-		rawMap := flattenUser(user, []string{})
+		var rawMap map[string]interface{}
+		if projected {
+			rawMap = flattenProjectedUser(user, convertInterfaceToStringArr(attributes))
+		} else {
+			rawMap = flattenUser(user, []string{})
+		}
 		rawMap["id"] = user.Id
-		if includeGroups {
-			groups, err := getGroupsForUser(ctx, user.Id, client)
-			if err != nil {
-				return diag.Errorf("failed to list users: %v", err)
-			}
-			rawMap["group_memberships"] = groups
+		arr[i] = rawMap
+	}
+	if includeGroups {
+		err = forEachParallel(ctx, m, len(users), func(ctx context.Context, i int) error {
+			groups, err := getGroupsForUser(ctx, users[i].Id, client)
+			arr[i]["group_memberships"] = groups
+			return err
+		})
+		if err != nil {
+			return diag.Errorf("failed to list users: %v", err)
 		}
-		if includeRoles {
-			roles, _, err := getAdminRoles(ctx, user.Id, client)
-			if err != nil {
-				return diag.Errorf("failed to set user's admin roles: %v", err)
-			}
-			rawMap["admin_roles"] = roles
+	}
+	if includeRoles {
+		err = forEachParallel(ctx, m, len(users), func(ctx context.Context, i int) error {
+			roles, _, err := getAdminRoles(ctx, users[i].Id, client)
+			arr[i]["admin_roles"] = roles
+			return err
+		})
+		if err != nil {
+			return diag.Errorf("failed to set user's admin roles: %v", err)
 		}
-		arr[i] = rawMap
 	}
 
 	_ = d.Set("users", arr)
 	return nil
 }
 
-func collectUsers(ctx context.Context, client *sdk.Client, qp *query.Params) ([]*sdk.User, error) {
-	users, resp, err := client.User.ListUsers(ctx, qp)
+// collectUsersWithin returns the users of every page, see collectPages. When
+// maxResults is set it fails as soon as a user beyond maxResults is read,
+// rather than reading every page of a search that matched too many users.
+func collectUsersWithin(ctx context.Context, first func() ([]*sdk.User, *sdk.Response, error), maxResults int) ([]*sdk.User, error) {
+	var (
+		users    []*sdk.User
+		exceeded bool
+	)
+	err := forEachPage(ctx, first, func(user *sdk.User) bool {
+		if maxResults > 0 && len(users) == maxResults {
+			exceeded = true
+			return false
+		}
+		users = append(users, user)
+		return true
+	})
 	if err != nil {
 		return nil, err
 	}
-	for resp.HasNextPage() {
-		var nextUsers []*sdk.User
-		resp, err = resp.Next(ctx, &nextUsers)
-		if err != nil {
-			return nil, err
-		}
-		users = append(users, nextUsers...)
+	if exceeded {
+		return nil, fmt.Errorf("more than max_results (%d) users matched, narrow the search or raise max_results", maxResults)
 	}
 	return users, nil
 }
+
+// usersPageSize returns the page size needed to detect that more than
+// maxResults users matched with a single request where possible.
+func usersPageSize(maxPageSize int64, maxResults int) int64 {
+	if maxResults > 0 && int64(maxResults) < maxPageSize {
+		return int64(maxResults) + 1
+	}
+	return maxPageSize
+}
+
+// flattenProjectedUser flattens the user with only the login, the status and
+// the given attributes, custom_profile_attributes is only set when one of them
+// is a custom attribute.
+func flattenProjectedUser(user *sdk.User, attributes []string) map[string]interface{} {
+	attrs := flattenUser(projectUserProfile(user, attributes), []string{})
+	if attrs["custom_profile_attributes"] == "{}" {
+		delete(attrs, "custom_profile_attributes")
+	}
+	return attrs
+}
+
+// projectUserProfile returns a copy of the user with only the login and the
+// given attributes, named either as the profile property or as the user
+// attribute, in its profile.
+func projectUserProfile(user *sdk.User, attributes []string) *sdk.User {
+	if user.Profile == nil {
+		return user
+	}
+	profile := sdk.UserProfile{}
+	for k, v := range *user.Profile {
+		if k == "login" || contains(attributes, k) || contains(attributes, camelCaseToUnderscore(k)) {
+			profile[k] = v
+		}
+	}
+	projected := *user
+	projected.Profile = &profile
+	return &projected
+}
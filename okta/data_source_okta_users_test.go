@@ -1,11 +1,17 @@
 package okta
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/okta/terraform-provider-okta/sdk/query"
 )
 
 var (
@@ -277,3 +283,79 @@ output "output_group_memberships" {
 
 	return fmt.Sprintf("%s%s%s", prepend, clause, append)
 }
+
+func TestCollectUsersWithin(t *testing.T) {
+	ctx := context.Background()
+	first := func(client *sdk.Client) func() ([]*sdk.User, *sdk.Response, error) {
+		return func() ([]*sdk.User, *sdk.Response, error) {
+			return client.Group.ListGroupUsers(ctx, "g1", &query.Params{Limit: 2})
+		}
+	}
+
+	client, _ := newPagedGroupUsersServer(t, 5)
+	users, err := collectUsersWithin(ctx, first(client), 5)
+	if err != nil || len(users) != 5 {
+		t.Errorf("expected 5 users, got %d: %v", len(users), err)
+	}
+
+	client, requests := newPagedGroupUsersServer(t, 9)
+	_, err = collectUsersWithin(ctx, first(client), 3)
+	if err == nil || !strings.Contains(err.Error(), "more than max_results (3) users matched") {
+		t.Errorf("expected max_results error, got %v", err)
+	}
+	if *requests != 2 {
+		t.Errorf("expected paging to stop after 2 requests, got %d", *requests)
+	}
+
+	if size := usersPageSize(maxUsersPageSize, 10); size != 11 {
+		t.Errorf("expected page size 11, got %d", size)
+	}
+	if size := usersPageSize(maxUsersPageSize, 0); size != maxUsersPageSize {
+		t.Errorf("expected page size %d, got %d", maxUsersPageSize, size)
+	}
+}
+
+func TestProjectUserProfile(t *testing.T) {
+	user := &sdk.User{
+		Id: "00u1",
+		Profile: &sdk.UserProfile{
+			"login":      "john@example.com",
+			"firstName":  "John",
+			"department": "Engineering",
+			"costCenter": "10",
+			"badge":      "1234",
+			"shoeSize":   "44",
+		},
+	}
+	projected := projectUserProfile(user, []string{"department", "cost_center", "badge"})
+	attrs := flattenUser(projected, []string{})
+	if attrs["login"] != "john@example.com" || attrs["department"] != "Engineering" || attrs["cost_center"] != "10" {
+		t.Errorf("expected login, department and cost_center, got %v", attrs)
+	}
+	if _, ok := attrs["first_name"]; ok {
+		t.Errorf("expected first_name to be left out, got %v", attrs)
+	}
+	if attrs["custom_profile_attributes"] != `{"badge":"1234"}` {
+		t.Errorf("expected only the badge custom attribute, got %v", attrs["custom_profile_attributes"])
+	}
+	if len(*user.Profile) != 6 {
+		t.Errorf("expected the user's profile to be left as is, got %v", *user.Profile)
+	}
+
+	attrs = flattenProjectedUser(user, []string{"department"})
+	expected := map[string]interface{}{"login": "john@example.com", "department": "Engineering", "status": ""}
+	if !reflect.DeepEqual(attrs, expected) {
+		t.Errorf("expected only the projected attributes %v, got %v", expected, attrs)
+	}
+	if attrs = flattenProjectedUser(user, []string{"badge"}); attrs["custom_profile_attributes"] != `{"badge":"1234"}` {
+		t.Errorf("expected the badge custom attribute, got %v", attrs)
+	}
+
+	sortOrder := dataSourceUsers().Schema["sort_order"]
+	if diags := sortOrder.ValidateDiagFunc("desc", cty.GetAttrPath("sort_order")); diags.HasError() {
+		t.Errorf("expected desc to be valid, got %v", diags)
+	}
+	if diags := sortOrder.ValidateDiagFunc("DESCENDING", cty.GetAttrPath("sort_order")); !diags.HasError() {
+		t.Error("expected DESCENDING to be invalid")
+	}
+}
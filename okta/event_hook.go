@@ -0,0 +1,133 @@
+package okta
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// eventHookEventTypes is the catalog of the event types Okta delivers to event
+// hooks, the event types of
+// https://developer.okta.com/docs/reference/api/event-types/?q=event-hook-eligible.
+// Okta refuses to activate a hook subscribed to any other event type.
+var eventHookEventTypes = []string{
+	"application.lifecycle.activate",
+	"application.lifecycle.create",
+	"application.lifecycle.deactivate",
+	"application.lifecycle.delete",
+	"application.lifecycle.update",
+	"application.user_membership.add",
+	"application.user_membership.change_password",
+	"application.user_membership.change_username",
+	"application.user_membership.remove",
+	"device.enrollment.create",
+	"device.lifecycle.activate",
+	"device.lifecycle.deactivate",
+	"device.lifecycle.delete",
+	"device.lifecycle.suspend",
+	"device.lifecycle.unsuspend",
+	"device.user.add",
+	"device.user.remove",
+	"group.application_assignment.add",
+	"group.application_assignment.remove",
+	"group.application_assignment.update",
+	"group.lifecycle.create",
+	"group.lifecycle.delete",
+	"group.privilege.grant",
+	"group.privilege.revoke",
+	"group.profile.update",
+	"group.user_membership.add",
+	"group.user_membership.remove",
+	"policy.lifecycle.activate",
+	"policy.lifecycle.create",
+	"policy.lifecycle.deactivate",
+	"policy.lifecycle.delete",
+	"policy.lifecycle.update",
+	"policy.rule.activate",
+	"policy.rule.add",
+	"policy.rule.deactivate",
+	"policy.rule.delete",
+	"policy.rule.update",
+	"security.threat.detected",
+	"system.api_token.create",
+	"system.api_token.revoke",
+	"system.idp.lifecycle.activate",
+	"system.idp.lifecycle.create",
+	"system.idp.lifecycle.deactivate",
+	"system.idp.lifecycle.delete",
+	"system.idp.lifecycle.update",
+	"system.org.rate_limit.violation",
+	"system.org.rate_limit.warning",
+	"user.account.lock",
+	"user.account.privilege.grant",
+	"user.account.privilege.revoke",
+	"user.account.report_suspicious_activity_by_enduser",
+	"user.account.reset_password",
+	"user.account.unlock",
+	"user.account.unlock_by_admin",
+	"user.account.update_password",
+	"user.account.update_profile",
+	"user.authentication.auth_via_IDP",
+	"user.authentication.auth_via_social",
+	"user.authentication.sso",
+	"user.authentication.universal_logout",
+	"user.lifecycle.activate",
+	"user.lifecycle.create",
+	"user.lifecycle.deactivate",
+	"user.lifecycle.delete.completed",
+	"user.lifecycle.delete.initiated",
+	"user.lifecycle.reactivate",
+	"user.lifecycle.suspend",
+	"user.lifecycle.unsuspend",
+	"user.mfa.factor.activate",
+	"user.mfa.factor.deactivate",
+	"user.mfa.factor.reset_all",
+	"user.mfa.factor.suspend",
+	"user.mfa.factor.unsuspend",
+	"user.mfa.factor.update",
+	"user.mfa.okta_verify.deny_push",
+	"user.risk.change",
+	"user.session.access_admin_app",
+	"user.session.clear",
+	"user.session.context.change",
+	"user.session.end",
+	"user.session.impersonation.end",
+	"user.session.impersonation.extend",
+	"user.session.impersonation.grant",
+	"user.session.impersonation.initiate",
+	"user.session.impersonation.revoke",
+	"user.session.start",
+	"zone.activate",
+	"zone.create",
+	"zone.deactivate",
+	"zone.delete",
+	"zone.update",
+}
+
+// stringIsEventHookEventType checks that an event type is in the catalog of
+// the event types delivered to event hooks, suggesting the ones of the same
+// family, e.g. user.lifecycle, otherwise.
+func stringIsEventHookEventType(i interface{}, k cty.Path) diag.Diagnostics {
+	v, ok := i.(string)
+	if !ok {
+		return diag.Errorf("expected type of %s to be string", k)
+	}
+	idx := sort.SearchStrings(eventHookEventTypes, v)
+	if idx < len(eventHookEventTypes) && eventHookEventTypes[idx] == v {
+		return nil
+	}
+	if dot := strings.LastIndex(v, "."); dot > 0 {
+		var family []string
+		for _, eventType := range eventHookEventTypes {
+			if strings.HasPrefix(eventType, v[:dot+1]) {
+				family = append(family, eventType)
+			}
+		}
+		if len(family) > 0 {
+			return diag.Errorf("'%s' is not an event type eligible for event hooks, eligible event types of '%s' are: %s", v, v[:dot], strings.Join(family, ", "))
+		}
+	}
+	return diag.Errorf("'%s' is not an event type eligible for event hooks, see https://developer.okta.com/docs/reference/api/event-types/?q=event-hook-eligible", v)
+}
@@ -0,0 +1,453 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/okta/terraform-provider-okta/sdk/query"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// exportKind is a group of objects the export command writes to its own .tf
+// file, e.g. all groups are written to groups.tf.
+type exportKind struct {
+	name string
+	list func(ctx context.Context, e *exporter) error
+}
+
+var exportKinds = []exportKind{
+	{"groups", exportGroups},
+	{"group_rules", exportGroupRules},
+	{"apps", exportApps},
+	{"policies", exportPolicies},
+}
+
+// ExportKinds returns the names of the kinds of objects Export supports.
+func ExportKinds() []string {
+	kinds := make([]string, len(exportKinds))
+	for i, kind := range exportKinds {
+		kinds[i] = kind.name
+	}
+	return kinds
+}
+
+// exportSkippedAttributes are arguments that only control how the provider
+// reads or writes a resource and have no counterpart in the org. Where present
+// they are enabled while reading so the export doesn't read user and group
+// assignments through deprecated arguments.
+var exportSkippedAttributes = []string{"skip_users", "skip_groups"}
+
+// exportBuiltInApps are apps every org has that can't be managed by the
+// provider.
+var exportBuiltInApps = map[string]bool{
+	"saasure":             true,
+	"okta_enduser":        true,
+	"okta_browser_plugin": true,
+	"flow":                true,
+	"okta_flow_sso":       true,
+}
+
+var reExportName = regexp.MustCompile(`[^a-z0-9_]+`)
+
+type exportObject struct {
+	kind         string
+	resourceType string
+	name         string
+	id           string
+	data         *schema.ResourceData
+}
+
+type exporter struct {
+	config   *Config
+	provider *schema.Provider
+	objects  []*exportObject
+	names    map[string]bool
+	byID     map[string]*exportObject
+}
+
+// Export walks the org configured by the provider's environment variables,
+// e.g. OKTA_ORG_NAME and OKTA_API_TOKEN, and writes a .tf file per kind of
+// object to dir. Each file has a resource block per object plus a matching
+// import block. Arguments holding the ID of another exported object are
+// written as references to that object.
+func Export(ctx context.Context, dir string, kinds []string) error {
+	provider := Provider()
+	diags := provider.Configure(ctx, terraform.NewResourceConfigRaw(map[string]interface{}{}))
+	if diags.HasError() {
+		return fmt.Errorf("failed to configure provider: %s", diags[0].Summary)
+	}
+	files, err := exportOrg(ctx, provider, provider.Meta().(*Config), kinds)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportOrg returns the contents of the .tf files, keyed by file name, for the
+// given kinds of objects.
+func exportOrg(ctx context.Context, provider *schema.Provider, config *Config, kinds []string) (map[string][]byte, error) {
+	e := &exporter{
+		config:   config,
+		provider: provider,
+		names:    map[string]bool{},
+		byID:     map[string]*exportObject{},
+	}
+	selected := map[string]bool{}
+	for _, kind := range kinds {
+		if !contains(ExportKinds(), kind) {
+			return nil, fmt.Errorf("unknown kind %q, must be one of %s", kind, strings.Join(ExportKinds(), ", "))
+		}
+		selected[kind] = true
+	}
+	for _, kind := range exportKinds {
+		if !selected[kind.name] {
+			continue
+		}
+		if err := kind.list(ctx, e); err != nil {
+			return nil, fmt.Errorf("failed to list %s: %v", kind.name, err)
+		}
+	}
+
+	objects := make([]*exportObject, 0, len(e.objects))
+	for _, obj := range e.objects {
+		d, err := e.read(ctx, obj)
+		if err != nil {
+			config.logger.Warn(fmt.Sprintf("skipping %s %q: %v", obj.resourceType, obj.id, err))
+			continue
+		}
+		if d.Id() == "" {
+			continue
+		}
+		obj.data = d
+		objects = append(objects, obj)
+		e.byID[obj.id] = obj
+	}
+	return e.files(objects), nil
+}
+
+// files renders the objects into .tf files, one per kind of object.
+func (e *exporter) files(objects []*exportObject) map[string][]byte {
+	hclFiles := map[string]*hclwrite.File{}
+	for _, obj := range objects {
+		f, ok := hclFiles[obj.kind]
+		if !ok {
+			f = hclwrite.NewEmptyFile()
+			hclFiles[obj.kind] = f
+		} else {
+			f.Body().AppendNewline()
+		}
+		e.render(f.Body(), obj)
+	}
+	files := map[string][]byte{}
+	for kind, f := range hclFiles {
+		files[kind+".tf"] = hclwrite.Format(f.Bytes())
+	}
+	return files
+}
+
+// add queues an object for export under a unique resource name derived from
+// its label.
+func (e *exporter) add(kind, resourceType, label, id string) {
+	base := reExportName.ReplaceAllString(strings.ToLower(label), "_")
+	base = strings.Trim(base, "_")
+	if base == "" {
+		base = "unnamed"
+	}
+	if base[0] >= '0' && base[0] <= '9' {
+		base = "_" + base
+	}
+	name := base
+	for i := 2; e.names[resourceType+"."+name]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	e.names[resourceType+"."+name] = true
+	e.objects = append(e.objects, &exportObject{
+		kind:         kind,
+		resourceType: resourceType,
+		name:         name,
+		id:           id,
+	})
+}
+
+// read populates the object's resource data the same way "terraform import"
+// does, by running the resource's importer and then its read.
+func (e *exporter) read(ctx context.Context, obj *exportObject) (*schema.ResourceData, error) {
+	res, ok := e.provider.ResourcesMap[obj.resourceType]
+	if !ok || res.ReadContext == nil {
+		return nil, fmt.Errorf("resource %s can't be read", obj.resourceType)
+	}
+	d := res.Data(nil)
+	d.SetId(obj.id)
+	if res.Importer != nil && res.Importer.StateContext != nil {
+		imported, err := res.Importer.StateContext(ctx, d, e.config)
+		if err != nil {
+			return nil, err
+		}
+		if len(imported) == 0 {
+			return nil, fmt.Errorf("import returned no state")
+		}
+		d = imported[0]
+	}
+	for _, k := range exportSkippedAttributes {
+		if _, ok := res.Schema[k]; ok {
+			_ = d.Set(k, true)
+		}
+	}
+	if diags := res.ReadContext(ctx, d, e.config); diags.HasError() {
+		return nil, fmt.Errorf("%s", diags[0].Summary)
+	}
+	return d, nil
+}
+
+func (e *exporter) render(body *hclwrite.Body, obj *exportObject) {
+	importBody := body.AppendNewBlock("import", nil).Body()
+	importBody.SetAttributeTraversal("to", hcl.Traversal{
+		hcl.TraverseRoot{Name: obj.resourceType},
+		hcl.TraverseAttr{Name: obj.name},
+	})
+	importBody.SetAttributeValue("id", cty.StringVal(obj.id))
+	body.AppendNewline()
+
+	res := e.provider.ResourcesMap[obj.resourceType]
+	values := map[string]interface{}{}
+	for k := range res.Schema {
+		values[k] = obj.data.Get(k)
+	}
+	block := body.AppendNewBlock("resource", []string{obj.resourceType, obj.name})
+	e.renderBody(block.Body(), res.Schema, values, obj.id)
+}
+
+// renderBody writes the configurable arguments that don't hold their default
+// value. Deprecated and sensitive arguments are left out, as is any argument
+// that conflicts with one already written.
+func (e *exporter) renderBody(body *hclwrite.Body, s map[string]*schema.Schema, values map[string]interface{}, selfID string) {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	rendered := map[string]bool{}
+	for _, k := range keys {
+		sch := s[k]
+		v := values[k]
+		if !sch.Optional && !sch.Required || sch.Deprecated != "" || sch.Sensitive ||
+			contains(exportSkippedAttributes, k) || isExportDefault(sch, v) {
+			continue
+		}
+		conflicts := false
+		for _, c := range sch.ConflictsWith {
+			conflicts = conflicts || rendered[c]
+		}
+		if conflicts {
+			continue
+		}
+		rendered[k] = true
+		if elem, ok := sch.Elem.(*schema.Resource); ok && sch.Type != schema.TypeMap {
+			for _, item := range exportList(v) {
+				if m, ok := item.(map[string]interface{}); ok {
+					e.renderBody(body.AppendNewBlock(k, nil).Body(), elem.Schema, m, selfID)
+				}
+			}
+			continue
+		}
+		body.SetAttributeRaw(k, e.tokens(v, selfID))
+	}
+}
+
+// tokens returns the HCL for a value, replacing the ID of any other exported
+// object with a reference to it.
+func (e *exporter) tokens(v interface{}, selfID string) hclwrite.Tokens {
+	switch val := v.(type) {
+	case string:
+		if obj, ok := e.byID[val]; ok && val != selfID {
+			return hclwrite.TokensForTraversal(hcl.Traversal{
+				hcl.TraverseRoot{Name: obj.resourceType},
+				hcl.TraverseAttr{Name: obj.name},
+				hcl.TraverseAttr{Name: "id"},
+			})
+		}
+		return hclwrite.TokensForValue(cty.StringVal(val))
+	case int:
+		return hclwrite.TokensForValue(cty.NumberIntVal(int64(val)))
+	case float64:
+		return hclwrite.TokensForValue(cty.NumberFloatVal(val))
+	case bool:
+		return hclwrite.TokensForValue(cty.BoolVal(val))
+	case *schema.Set, []interface{}:
+		list := exportList(val)
+		elems := make([]hclwrite.Tokens, len(list))
+		for i := range list {
+			elems[i] = e.tokens(list[i], selfID)
+		}
+		return hclwrite.TokensForTuple(elems)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		attrs := make([]hclwrite.ObjectAttrTokens, len(keys))
+		for i, k := range keys {
+			attrs[i] = hclwrite.ObjectAttrTokens{
+				Name:  hclwrite.TokensForValue(cty.StringVal(k)),
+				Value: e.tokens(val[k], selfID),
+			}
+		}
+		return hclwrite.TokensForObject(attrs)
+	}
+	return hclwrite.TokensForValue(cty.NullVal(cty.DynamicPseudoType))
+}
+
+func exportList(v interface{}) []interface{} {
+	switch val := v.(type) {
+	case *schema.Set:
+		list := val.List()
+		sort.SliceStable(list, func(i, j int) bool {
+			return fmt.Sprint(list[i]) < fmt.Sprint(list[j])
+		})
+		return list
+	case []interface{}:
+		return val
+	}
+	return nil
+}
+
+// isExportDefault reports if the value is the argument's default, or its zero
+// value when it has no default.
+func isExportDefault(s *schema.Schema, v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	if s.Default != nil {
+		return reflect.DeepEqual(s.Default, v)
+	}
+	switch val := v.(type) {
+	case string:
+		return val == ""
+	case int:
+		return val == 0 && !s.Required
+	case float64:
+		return val == 0 && !s.Required
+	case bool:
+		return !val && !s.Required
+	case *schema.Set:
+		return val.Len() == 0
+	case []interface{}:
+		return len(val) == 0
+	case map[string]interface{}:
+		return len(val) == 0
+	}
+	return false
+}
+
+func exportGroups(ctx context.Context, e *exporter) error {
+	groups, err := listGroups(ctx, getOktaClientFromMetadata(e.config), &query.Params{
+		Filter: `type eq "OKTA_GROUP"`,
+		Limit:  maxGroupsPageSize,
+	})
+	if err != nil {
+		return err
+	}
+	for _, g := range groups {
+		e.add("groups", group, g.Profile.Name, g.Id)
+	}
+	return nil
+}
+
+func exportGroupRules(ctx context.Context, e *exporter) error {
+	rules, err := listGroupRules(ctx, getOktaClientFromMetadata(e.config), &query.Params{Limit: defaultPaginationLimit})
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		e.add("group_rules", groupRule, rule.Name, rule.Id)
+	}
+	return nil
+}
+
+func exportApps(ctx context.Context, e *exporter) error {
+	apps, err := listApps(ctx, getOktaClientFromMetadata(e.config), nil, maxAppsPageSize)
+	if err != nil {
+		return err
+	}
+	for _, app := range apps {
+		if exportBuiltInApps[app.Name] {
+			continue
+		}
+		resourceType := exportAppResourceType(app)
+		if resourceType == "" {
+			e.config.logger.Warn(fmt.Sprintf("skipping app %q, sign on mode %q is not supported", app.Id, app.SignOnMode))
+			continue
+		}
+		e.add("apps", resourceType, app.Label, app.Id)
+	}
+	return nil
+}
+
+func exportAppResourceType(app *sdk.Application) string {
+	switch app.SignOnMode {
+	case "SAML_2_0", "SAML_1_1":
+		return appSaml
+	case "OPENID_CONNECT":
+		return appOAuth
+	case "BOOKMARK":
+		return appBookmark
+	case "BASIC_AUTH":
+		return appBasicAuth
+	case "AUTO_LOGIN":
+		return appAutoLogin
+	case "SECURE_PASSWORD_STORE":
+		return appSecurePasswordStore
+	case "BROWSER_PLUGIN":
+		if app.Name == "template_swa3field" {
+			return appThreeField
+		}
+		return appSwa
+	}
+	return ""
+}
+
+// exportPolicies exports the policies that aren't the org's default policy,
+// default policies are managed with the *_default resources instead.
+func exportPolicies(ctx context.Context, e *exporter) error {
+	policyTypes := []struct {
+		policyType   string
+		resourceType string
+	}{
+		{sdk.SignOnPolicyType, policySignOn},
+		{sdk.PasswordPolicyType, policyPassword},
+		{sdk.MfaPolicyType, policyMfa},
+	}
+	for _, pt := range policyTypes {
+		policies, err := listPolicies(ctx, e.config, pt.policyType)
+		if err != nil {
+			return err
+		}
+		for _, p := range policies {
+			if p.System != nil && *p.System {
+				continue
+			}
+			e.add("policies", pt.resourceType, p.Name, p.Id)
+		}
+	}
+	return nil
+}
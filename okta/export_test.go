@@ -0,0 +1,191 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/okta/terraform-provider-okta/okta/internal/mockokta"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+func TestExportRendersReferencesAndImports(t *testing.T) {
+	e := &exporter{
+		provider: Provider(),
+		names:    map[string]bool{},
+		byID:     map[string]*exportObject{},
+	}
+	e.add("groups", group, "Engineering", "00g1")
+	e.add("groups", group, "engineering!", "00g2")
+	e.add("group_rules", groupRule, "Engineers", "0pr1")
+
+	values := []map[string]interface{}{
+		{"name": "Engineering", "description": "Uses ${var}"},
+		{"name": "engineering!"},
+		{
+			"name":              "Engineers",
+			"group_assignments": []interface{}{"00g2", "00g1", "00gUnexported"},
+			"expression_type":   "urn:okta:expression:1.0",
+			"expression_value":  `String.stringContains(user.department,"Eng")`,
+			"status":            statusActive,
+		},
+	}
+	for i, obj := range e.objects {
+		d := e.provider.ResourcesMap[obj.resourceType].Data(nil)
+		d.SetId(obj.id)
+		for k, v := range values[i] {
+			if err := d.Set(k, v); err != nil {
+				t.Fatalf("failed to set %q: %v", k, err)
+			}
+		}
+		obj.data = d
+		e.byID[obj.id] = obj
+	}
+
+	files := e.files(e.objects)
+	expected := map[string]string{
+		"groups.tf": `import {
+  to = okta_group.engineering
+  id = "00g1"
+}
+
+resource "okta_group" "engineering" {
+  description = "Uses $${var}"
+  name        = "Engineering"
+}
+
+import {
+  to = okta_group.engineering_2
+  id = "00g2"
+}
+
+resource "okta_group" "engineering_2" {
+  name = "engineering!"
+}
+`,
+		"group_rules.tf": `import {
+  to = okta_group_rule.engineers
+  id = "0pr1"
+}
+
+resource "okta_group_rule" "engineers" {
+  expression_value  = "String.stringContains(user.department,\"Eng\")"
+  group_assignments = [okta_group.engineering.id, okta_group.engineering_2.id, "00gUnexported"]
+  name              = "Engineers"
+}
+`,
+	}
+	if len(files) != len(expected) {
+		t.Fatalf("expected %d files, got %d", len(expected), len(files))
+	}
+	for name, content := range expected {
+		if string(files[name]) != content {
+			t.Errorf("unexpected %s, expected:\n%s\ngot:\n%s", name, content, files[name])
+		}
+	}
+}
+
+// checkExportedOrg checks every exported resource has a matching import block
+// and every exported group assigned by a group rule is referenced rather than
+// hard coded.
+func checkExportedOrg(t *testing.T, files map[string][]byte) {
+	if len(files["groups.tf"]) == 0 {
+		t.Fatalf("expected groups to be exported")
+	}
+
+	groupIDs := map[string]bool{}
+	for name, content := range files {
+		f, diags := hclwrite.ParseConfig(content, name, hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatalf("%s is not valid HCL: %s", name, diags.Error())
+		}
+		imports := map[string]bool{}
+		for _, block := range f.Body().Blocks() {
+			if block.Type() == "import" {
+				to := string(block.Body().GetAttribute("to").Expr().BuildTokens(nil).Bytes())
+				imports[strings.TrimSpace(to)] = true
+				if name == "groups.tf" {
+					id := string(block.Body().GetAttribute("id").Expr().BuildTokens(nil).Bytes())
+					groupIDs[strings.Trim(strings.TrimSpace(id), `"`)] = true
+				}
+			}
+		}
+		for _, block := range f.Body().Blocks() {
+			if block.Type() != "resource" {
+				continue
+			}
+			address := strings.Join(block.Labels(), ".")
+			if !imports[address] {
+				t.Errorf("%s has no import block for %s", name, address)
+			}
+		}
+	}
+	for id := range groupIDs {
+		if strings.Contains(string(files["group_rules.tf"]), fmt.Sprintf("%q", id)) {
+			t.Errorf("group rules assign exported group %q by ID instead of by reference", id)
+		}
+	}
+}
+
+// TestExportMockOrg exports a mock Okta org with groups, a group rule, an app
+// and a policy, the org stands in for a live one as no VCR cassette of an
+// export has been recorded.
+func TestExportMockOrg(t *testing.T) {
+	server := mockokta.NewServer()
+	t.Cleanup(server.Close)
+	m := newTestConfig(t, server)
+	ctx := context.Background()
+	client := getOktaClientFromMetadata(m)
+
+	var groupIDs []string
+	for _, name := range []string{"Engineering", "Sales"} {
+		g, _, err := client.Group.CreateGroup(ctx, sdk.Group{Profile: &sdk.GroupProfile{Name: name}})
+		if err != nil {
+			t.Fatalf("failed to create group: %v", err)
+		}
+		groupIDs = append(groupIDs, g.Id)
+	}
+	rule := sdk.GroupRule{
+		Name: "Engineers",
+		Type: "group_rule",
+		Conditions: &sdk.GroupRuleConditions{
+			Expression: &sdk.GroupRuleExpression{Type: "urn:okta:expression:1.0", Value: `String.stringContains(user.department,"Eng")`},
+		},
+		Actions: &sdk.GroupRuleAction{AssignUserToGroups: &sdk.GroupRuleGroupAssignment{GroupIds: groupIDs}},
+	}
+	if _, _, err := client.Group.CreateGroupRule(ctx, rule); err != nil {
+		t.Fatalf("failed to create group rule: %v", err)
+	}
+	app := sdk.NewBookmarkApplication()
+	app.Label = "Wiki"
+	app.Settings = &sdk.BookmarkApplicationSettings{
+		App: &sdk.BookmarkApplicationSettingsApplication{Url: "https://wiki.example.com"},
+	}
+	if _, _, err := client.Application.CreateApplication(ctx, app, nil); err != nil {
+		t.Fatalf("failed to create app: %v", err)
+	}
+	policy := sdk.SignOnPolicy()
+	policy.Name = "Contractors"
+	if _, _, err := client.Policy.CreatePolicy(ctx, &policy, nil); err != nil {
+		t.Fatalf("failed to create policy: %v", err)
+	}
+
+	files, err := exportOrg(ctx, Provider(), m, ExportKinds())
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	checkExportedOrg(t, files)
+	for name, resource := range map[string]string{
+		"groups.tf":      `resource "okta_group" "sales"`,
+		"group_rules.tf": `group_assignments = [okta_group.engineering.id, okta_group.sales.id]`,
+		"apps.tf":        `resource "okta_app_bookmark" "wiki"`,
+		"policies.tf":    `resource "okta_policy_signon" "contractors"`,
+	} {
+		if !strings.Contains(string(files[name]), resource) {
+			t.Errorf("expected %s to hold %s, got:\n%s", name, resource, files[name])
+		}
+	}
+}
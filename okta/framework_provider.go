@@ -7,12 +7,15 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/okta/terraform-provider-okta/okta/internal/transport"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -35,23 +38,27 @@ type FrameworkProvider struct {
 }
 
 type FrameworkProviderData struct {
-	OrgName        types.String `tfsdk:"org_name"`
-	AccessToken    types.String `tfsdk:"access_token"`
-	APIToken       types.String `tfsdk:"api_token"`
-	ClientID       types.String `tfsdk:"client_id"`
-	Scopes         types.Set    `tfsdk:"scopes"`
-	PrivateKey     types.String `tfsdk:"private_key"`
-	PrivateKeyID   types.String `tfsdk:"private_key_id"`
-	BaseURL        types.String `tfsdk:"base_url"`
-	HTTPProxy      types.String `tfsdk:"http_proxy"`
-	Backoff        types.Bool   `tfsdk:"backoff"`
-	MinWaitSeconds types.Int64  `tfsdk:"min_wait_seconds"`
-	MaxWaitSeconds types.Int64  `tfsdk:"max_wait_seconds"`
-	MaxRetries     types.Int64  `tfsdk:"max_retries"`
-	Parallelism    types.Int64  `tfsdk:"parallelism"`
-	LogLevel       types.Int64  `tfsdk:"log_level"`
-	MaxAPICapacity types.Int64  `tfsdk:"max_api_capacity"`
-	RequestTimeout types.Int64  `tfsdk:"request_timeout"`
+	OrgName               types.String `tfsdk:"org_name"`
+	AccessToken           types.String `tfsdk:"access_token"`
+	APIToken              types.String `tfsdk:"api_token"`
+	ClientID              types.String `tfsdk:"client_id"`
+	Scopes                types.Set    `tfsdk:"scopes"`
+	PrivateKey            types.String `tfsdk:"private_key"`
+	PrivateKeyID          types.String `tfsdk:"private_key_id"`
+	BaseURL               types.String `tfsdk:"base_url"`
+	HTTPProxy             types.String `tfsdk:"http_proxy"`
+	Backoff               types.Bool   `tfsdk:"backoff"`
+	MinWaitSeconds        types.Int64  `tfsdk:"min_wait_seconds"`
+	MaxWaitSeconds        types.Int64  `tfsdk:"max_wait_seconds"`
+	MaxRetries            types.Int64  `tfsdk:"max_retries"`
+	Parallelism           types.Int64  `tfsdk:"parallelism"`
+	LogLevel              types.Int64  `tfsdk:"log_level"`
+	MaxAPICapacity        types.Int64  `tfsdk:"max_api_capacity"`
+	RequestTimeout        types.Int64  `tfsdk:"request_timeout"`
+	RequestCacheTTL       types.Int64  `tfsdk:"request_cache_ttl"`
+	APITokenRole          types.String `tfsdk:"api_token_role"`
+	ReadOnly              types.Bool   `tfsdk:"read_only"`
+	DetectUpdateConflicts types.Bool   `tfsdk:"detect_update_conflicts"`
 }
 
 // Metadata returns the provider type name.
@@ -189,6 +196,32 @@ func (p *FrameworkProvider) Schema(_ context.Context, _ provider.SchemaRequest,
 					int64validator.AtMost(300),
 				},
 			},
+			"api_token_role": schema.StringAttribute{
+				Optional: true,
+				Description: "The admin role of the admin who minted the API token, e.g. `SUPER_ADMIN` or `ORG_ADMIN`. When the role is not " +
+					"`SUPER_ADMIN`, 401 and 403 errors reading known permission gated endpoints (e.g. admin roles) are reported " +
+					"as warnings and the prior state is kept rather than failing the run. When not set, the role is detected with GET /api/v1/users/me/roles.",
+			},
+			"request_cache_ttl": schema.Int64Attribute{
+				Optional: true,
+				Description: "Time (in seconds) successful GET responses are cached for the duration of a single terraform command. " +
+					"A POST, PUT or DELETE to a path invalidates its cached responses and those of its parent paths, child paths and of paths holding its object IDs. The default is `0` (caching is disabled).",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+					int64validator.AtMost(3600),
+				},
+			},
+			"read_only": schema.BoolAttribute{
+				Optional: true,
+				Description: "Refuse any request to the Okta API that could change the org, i.e. anything other than a GET. " +
+					"A create, update or delete fails with an error naming the resource and the requests it would have made. The default is `false`.",
+			},
+			"detect_update_conflicts": schema.BoolAttribute{
+				Optional: true,
+				Description: "Refuse to update an object that was changed outside of Terraform since it was last read. Before an update the object " +
+					"is read again and the update fails naming the attributes that differ from the state, a PUT is also refused if the " +
+					"object's lastUpdated moved since the update read it. The default is `false`.",
+			},
 		},
 	}
 }
@@ -222,6 +255,10 @@ func (p *FrameworkProvider) Configure(ctx context.Context, req provider.Configur
 	p.parallelism = int(data.Parallelism.ValueInt64())
 	p.logLevel = int(data.LogLevel.ValueInt64())
 	p.requestTimeout = int(data.RequestTimeout.ValueInt64())
+	p.requestCacheTTL = int(data.RequestCacheTTL.ValueInt64())
+	p.apiTokenRole = data.APITokenRole.ValueString()
+	p.readOnly = data.ReadOnly.ValueBool()
+	p.detectUpdateConflicts = data.DetectUpdateConflicts.ValueBool()
 	for _, val := range data.Scopes.Elements() {
 		p.scopes = append(p.scopes, val.String())
 	}
@@ -234,6 +271,9 @@ func (p *FrameworkProvider) Configure(ctx context.Context, req provider.Configur
 		resp.Diagnostics.AddError("failed to load default value to provider", err.Error())
 		return
 	}
+	if p.apiToken != "" && p.apiTokenRole == "" {
+		p.detectAPITokenRole(ctx)
+	}
 	p.SetTimeOperations(NewProductionTimeOperations())
 
 	resp.DataSourceData = &p.Config
@@ -247,11 +287,63 @@ func (p *FrameworkProvider) DataSources(_ context.Context) []func() datasource.D
 
 // DataSources defines the data sources implemented in the provider.
 func (p *FrameworkProvider) Resources(_ context.Context) []func() resource.Resource {
-	return []func() resource.Resource{
+	resources := []func() resource.Resource{
 		NewPolicyDeviceAssuranceAndroidResource,
 		NewPolicyDeviceAssuranceIOSResource,
 		NewPolicyDeviceAssuranceChromeOSResource,
 		NewPolicyDeviceAssuranceMacOSResource,
 		NewPolicyDeviceAssuranceWindowsResource,
 	}
+	for i := range resources {
+		resources[i] = withFrameworkPermissionWarnings(withFrameworkUpdateConflictDetection(resources[i]))
+	}
+	return resources
+}
+
+// permissionWarningResource surfaces permission errors on known endpoints
+// recorded by the http transport during a read as warning diagnostics and
+// keeps the prior state, like readWithPermissionWarnings of the SDK resources.
+type permissionWarningResource struct {
+	resource.Resource
+}
+
+func withFrameworkPermissionWarnings(newResource func() resource.Resource) func() resource.Resource {
+	return func() resource.Resource {
+		return &permissionWarningResource{Resource: newResource()}
+	}
+}
+
+func (r *permissionWarningResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if configurable, ok := r.Resource.(resource.ResourceWithConfigure); ok {
+		configurable.Configure(ctx, req, resp)
+	}
+}
+
+func (r *permissionWarningResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importable, ok := r.Resource.(resource.ResourceWithImportState)
+	if !ok {
+		resp.Diagnostics.AddError("Resource Import Not Implemented", "This resource does not support import.")
+		return
+	}
+	importable.ImportState(ctx, req, resp)
+}
+
+func (r *permissionWarningResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, warnings := transport.WithPermissionWarnings(ctx)
+	r.Resource.Read(ctx, req, resp)
+	messages := warnings.Messages()
+	if len(messages) == 0 {
+		return
+	}
+	resp.State = tfsdk.State{Schema: req.State.Schema, Raw: req.State.Raw.Copy()}
+	var diags diag.Diagnostics
+	for _, diagnostic := range resp.Diagnostics {
+		if diagnostic.Severity() != diag.SeverityError {
+			diags.Append(diagnostic)
+		}
+	}
+	for _, msg := range messages {
+		diags.AddWarning(msg, "The prior state of the attributes read from this endpoint is kept. Set the provider's api_token_role to SUPER_ADMIN to fail on permission errors instead.")
+	}
+	resp.Diagnostics = diags
 }
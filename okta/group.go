@@ -9,25 +9,9 @@ import (
 )
 
 func listGroupUsers(ctx context.Context, m interface{}, id string) ([]*sdk.User, error) {
-	var resUsers []*sdk.User
-	users, resp, err := getOktaClientFromMetadata(m).Group.ListGroupUsers(ctx, id, &query.Params{Limit: defaultPaginationLimit})
-	if err != nil {
-		return nil, err
-	}
-	for {
-		resUsers = append(resUsers, users...)
-		if resp.HasNextPage() {
-			users = []*sdk.User{}
-			resp, err = resp.Next(ctx, &users)
-			if err != nil {
-				return nil, err
-			}
-			continue
-		} else {
-			break
-		}
-	}
-	return resUsers, nil
+	return collectPages(ctx, func() ([]*sdk.User, *sdk.Response, error) {
+		return getOktaClientFromMetadata(m).Group.ListGroupUsers(ctx, id, &query.Params{Limit: maxGroupUsersPageSize})
+	})
 }
 
 func listGroupUserIDs(ctx context.Context, m interface{}, id string) ([]string, error) {
@@ -43,23 +27,15 @@ func listGroupUserIDs(ctx context.Context, m interface{}, id string) ([]string,
 }
 
 func listGroups(ctx context.Context, client *sdk.Client, qp *query.Params) ([]*sdk.Group, error) {
-	groups, resp, err := client.Group.ListGroups(ctx, qp)
-	if err != nil {
-		return nil, err
-	}
-	for {
-		if resp.HasNextPage() {
-			var nextGroups []*sdk.Group
-			resp, err = resp.Next(ctx, &nextGroups)
-			if err != nil {
-				return nil, err
-			}
-			groups = append(groups, nextGroups...)
-		} else {
-			break
-		}
-	}
-	return groups, nil
+	return collectPages(ctx, func() ([]*sdk.Group, *sdk.Response, error) {
+		return client.Group.ListGroups(ctx, qp)
+	})
+}
+
+func listGroupRules(ctx context.Context, client *sdk.Client, qp *query.Params) ([]*sdk.GroupRule, error) {
+	return collectPages(ctx, func() ([]*sdk.GroupRule, *sdk.Response, error) {
+		return client.Group.ListGroupRules(ctx, qp)
+	})
 }
 
 // Group Primary Key Operations (Use when # groups < # users in operations)
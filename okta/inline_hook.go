@@ -0,0 +1,230 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// inlineHookContract is what Okta accepts for an inline hook type: the
+// versions of the hook and of its channel known to be accepted, the reference
+// of the type, and a sample of the requests Okta sends to the hook, used to
+// preview it.
+//
+// Versions are deliberately only warned about, at apply: Okta adds versions
+// over time, so failing the plan on an other version would block hooks the
+// org accepts, and the SDK can't warn from CustomizeDiff at plan.
+type inlineHookContract struct {
+	versions        []string
+	channelVersions []string
+	reference       string
+	samplePayload   string
+}
+
+// inlineHookContracts are the contracts of the inline hook types, see
+// https://developer.okta.com/docs/reference/api/inline-hooks/#supported-inline-hook-types.
+var inlineHookContracts = map[string]inlineHookContract{
+	"com.okta.oauth2.tokens.transform": {
+		reference:       "https://developer.okta.com/docs/reference/token-hook/",
+		versions:        []string{"1.0.0", "1.0.1"},
+		channelVersions: []string{"1.0.0"},
+		samplePayload: `{
+  "eventType": "com.okta.oauth2.tokens.transform",
+  "eventTypeVersion": "1.0",
+  "cloudEventVersion": "0.1",
+  "contentType": "application/json",
+  "source": "https://example.okta.com/oauth2/default/v1/authorize",
+  "data": {
+    "context": {
+      "protocol": {
+        "type": "OAUTH2.0",
+        "request": {"scope": "openid profile", "response_type": "code", "grant_type": "authorization_code"},
+        "issuer": {"uri": "https://example.okta.com/oauth2/default"},
+        "client": {"id": "0oa1sample", "name": "Sample App", "type": "PUBLIC"}
+      },
+      "user": {
+        "id": "00u1sample",
+        "profile": {"login": "jane.doe@example.com", "firstName": "Jane", "lastName": "Doe", "locale": "en", "timeZone": "America/Los_Angeles"}
+      }
+    },
+    "identity": {
+      "claims": {"sub": "00u1sample", "ver": 1, "iss": "https://example.okta.com/oauth2/default", "aud": "0oa1sample", "preferred_username": "jane.doe@example.com"},
+      "token": {"lifetime": {"expiration": 3600}}
+    },
+    "access": {
+      "claims": {"ver": 1, "iss": "https://example.okta.com/oauth2/default", "aud": "api://default", "cid": "0oa1sample", "uid": "00u1sample", "sub": "jane.doe@example.com"},
+      "token": {"lifetime": {"expiration": 3600}},
+      "scopes": {"openid": {"id": "scp1sample", "action": "GRANT"}, "profile": {"id": "scp2sample", "action": "GRANT"}}
+    }
+  }
+}`,
+	},
+	"com.okta.saml.tokens.transform": {
+		reference:       "https://developer.okta.com/docs/reference/saml-hook/",
+		versions:        []string{"1.0.0", "1.0.2"},
+		channelVersions: []string{"1.0.0"},
+		samplePayload: `{
+  "eventType": "com.okta.saml.tokens.transform",
+  "eventTypeVersion": "1.0",
+  "cloudEventVersion": "0.1",
+  "contentType": "application/json",
+  "source": "https://example.okta.com/app/saml20app/exk1sample/sso/saml",
+  "data": {
+    "context": {
+      "protocol": {
+        "type": "SAML2.0",
+        "issuer": {"id": "0oa1sample", "name": "Sample SAML App", "uri": "http://www.okta.com/exk1sample"}
+      },
+      "user": {
+        "id": "00u1sample",
+        "profile": {"login": "jane.doe@example.com", "firstName": "Jane", "lastName": "Doe", "locale": "en", "timeZone": "America/Los_Angeles"}
+      }
+    },
+    "assertion": {
+      "subject": {"nameId": "jane.doe@example.com", "nameFormat": "urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified"},
+      "authentication": {"sessionIndex": "id1sample", "authnContext": {"authnContextClassRef": "urn:oasis:names:tc:SAML:2.0:ac:classes:PasswordProtectedTransport"}},
+      "conditions": {"audienceRestriction": ["https://sp.example.com/saml"]},
+      "claims": {}
+    }
+  }
+}`,
+	},
+	"com.okta.user.pre-registration": {
+		reference:       "https://developer.okta.com/docs/reference/registration-hook/",
+		versions:        []string{"1.0.0", "1.0.2", "1.0.3"},
+		channelVersions: []string{"1.0.0"},
+		samplePayload: `{
+  "eventType": "com.okta.user.pre-registration",
+  "eventTypeVersion": "1.0",
+  "cloudEventVersion": "0.1",
+  "contentType": "application/json",
+  "source": "https://example.okta.com/api/v1/inlineHooks/cal1sample",
+  "data": {
+    "context": {
+      "request": {"method": "POST", "url": {"value": "/idp/idx/enroll/new"}, "ipAddress": "127.0.0.1"}
+    },
+    "userProfile": {"login": "jane.doe@example.com", "email": "jane.doe@example.com", "firstName": "Jane", "lastName": "Doe"},
+    "action": "ALLOW"
+  }
+}`,
+	},
+	"com.okta.user.credential.password.import": {
+		reference:       "https://developer.okta.com/docs/reference/password-hook/",
+		versions:        []string{"1.0.0"},
+		channelVersions: []string{"1.0.0"},
+		samplePayload: `{
+  "eventType": "com.okta.user.credential.password.import",
+  "eventTypeVersion": "1.0",
+  "cloudEventVersion": "0.1",
+  "contentType": "application/json",
+  "source": "https://example.okta.com/api/v1/inlineHooks/cal1sample",
+  "data": {
+    "context": {
+      "request": {"method": "POST", "url": {"value": "/api/v1/authn"}, "ipAddress": "127.0.0.1"},
+      "credential": {"username": "jane.doe@example.com", "password": "SamplePassw0rd!"}
+    },
+    "action": {"credential": "UNVERIFIED"}
+  }
+}`,
+	},
+	"com.okta.import.transform": {
+		reference:       "https://developer.okta.com/docs/reference/import-hook/",
+		versions:        []string{"1.0.0", "1.0.2"},
+		channelVersions: []string{"1.0.0"},
+		samplePayload: `{
+  "eventType": "com.okta.import.transform",
+  "eventTypeVersion": "1.0",
+  "cloudEventVersion": "0.1",
+  "contentType": "application/json",
+  "source": "cal1sample",
+  "data": {
+    "context": {
+      "conflicts": ["login"],
+      "application": {"name": "test_app", "id": "0oa1sample", "label": "app7", "status": "ACTIVE"},
+      "job": {"id": "ij01sample", "type": "import:users"},
+      "matches": [],
+      "policy": ["EMAIL", "FIRST_AND_LAST_NAME"]
+    },
+    "action": {"result": "CREATE_USER"},
+    "appUser": {"profile": {"firstName": "Jane", "lastName": "Doe", "email": "jane.doe@example.com", "userName": "jane.doe"}},
+    "user": {"profile": {"login": "jane.doe@example.com", "firstName": "Jane", "lastName": "Doe", "email": "jane.doe@example.com"}}
+  }
+}`,
+	},
+	"com.okta.telephony.provider": {
+		reference:       "https://developer.okta.com/docs/reference/telephony-hook/",
+		versions:        []string{"1.0.0"},
+		channelVersions: []string{"1.0.0"},
+		samplePayload: `{
+  "eventType": "com.okta.telephony.provider",
+  "eventTypeVersion": "1.0",
+  "cloudEventVersion": "0.1",
+  "contentType": "application/json",
+  "source": "https://example.okta.com/api/v1/inlineHooks/cal1sample",
+  "data": {
+    "context": {"request": {"method": "POST", "url": {"value": "/idp/idx/challenge"}, "ipAddress": "127.0.0.1"}},
+    "userProfile": {"firstName": "Jane", "lastName": "Doe", "login": "jane.doe@example.com", "userId": "00u1sample"},
+    "messageProfile": {"msgTemplate": "Your code is 123456", "phoneNumber": "+15555550100", "otpExpires": "2024-01-01T00:05:00.000Z", "deliveryChannel": "SMS", "otpCode": "123456", "locale": "EN-US"}
+  }
+}`,
+	},
+}
+
+func inlineHookTypes() []string {
+	types := make([]string, 0, len(inlineHookContracts))
+	for t := range inlineHookContracts {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// validateInlineHookContract checks the type of the hook is an inline hook
+// type and the method of its channel is POST, the only method Okta calls
+// inline hooks with.
+func validateInlineHookContract(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown("type") {
+		return nil
+	}
+	hookType := d.Get("type").(string)
+	if _, ok := inlineHookContracts[hookType]; !ok {
+		return fmt.Errorf("'%s' is not an inline hook type, types are: %s", hookType, strings.Join(inlineHookTypes(), ", "))
+	}
+	if d.NewValueKnown("channel.method") {
+		if method, _ := d.Get("channel").(map[string]interface{})["method"].(string); method != "" && method != http.MethodPost {
+			return fmt.Errorf("channel method '%s' is not supported by inline hooks, the method is POST", method)
+		}
+	}
+	return nil
+}
+
+// inlineHookVersionWarnings warns about a version of the hook or of its
+// channel that isn't known to be accepted by its type.
+func inlineHookVersionWarnings(d *schema.ResourceData) diag.Diagnostics {
+	hookType := d.Get("type").(string)
+	contract, ok := inlineHookContracts[hookType]
+	if !ok {
+		return nil
+	}
+	var diags diag.Diagnostics
+	if version := d.Get("version").(string); !contains(contract.versions, version) {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Version '%s' is not a known version of inline hooks of type '%s'", version, hookType),
+			Detail:   fmt.Sprintf("The known versions are %s, see %s", strings.Join(contract.versions, ", "), contract.reference),
+		})
+	}
+	if version, _ := d.Get("channel").(map[string]interface{})["version"].(string); !contains(contract.channelVersions, version) {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Channel version '%s' is not a known version of the channel of inline hooks of type '%s'", version, hookType),
+			Detail:   fmt.Sprintf("The known versions are %s, see %s", strings.Join(contract.channelVersions, ", "), contract.reference),
+		})
+	}
+	return diags
+}
@@ -62,6 +62,12 @@ func (m *APIMutex) HasCapacity(method, endPoint string) bool {
 	return utilization <= float32(m.capacity)
 }
 
+// Capacity returns the percentage of the rate limit the api mutex is allowed
+// to consume.
+func (m *APIMutex) Capacity() int {
+	return m.capacity
+}
+
 // Update updates the known status for the given API endpoint. It is synchronous
 // and intelligently accounts for new values regardless of parallelism.
 func (m *APIMutex) Update(method, endPoint string, limit, remaining int, reset int64) {
@@ -88,9 +94,14 @@ func (m *APIMutex) Update(method, endPoint string, limit, remaining int, reset i
 	}
 }
 
-// Status Returns the APIStatus for the given method + endpoint combination.
+// Status Returns a copy of the APIStatus for the given method + endpoint
+// combination.
 func (m *APIMutex) Status(method, endPoint string) *APIStatus {
-	return m.get(method, endPoint)
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	status := *m.get(method, endPoint)
+	return &status
 }
 
 // Class Returns the api endpoint class.
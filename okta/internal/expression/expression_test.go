@@ -0,0 +1,118 @@
+package expression
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		expression string
+		err        string
+	}{
+		{expression: `String.stringContains(user.department, "Eng")`},
+		{expression: `user.department == "Engineering" AND isMemberOfGroupName("Admins")`},
+		{expression: `Arrays.contains(user.costCenters, 'R&D') or !isMemberOfAnyGroup("00g1", "00g2")`},
+		{expression: `user.nickName ?: String.substring(user.firstName, 0, 1)`},
+		{expression: `user.getLinkedObject("manager").login.toLowerCase() eq 'it''s'`},
+		{expression: `user.emails[0] != null ? user.emails[0] : Arrays.get({"a", "b"}, 0)`},
+		{expression: `Convert.toInt(user.level) >= 3 && user.email matches ".*@example\\.com"`},
+		{expression: `user.isMemberOf({'group.id': {"00g1","00g2"}})`},
+		{expression: `user.isMemberOf({'group.profile.name': 'Eng', 'operator': 'STARTS_WITH'})`},
+		{expression: `Arrays.isEmpty(user.getGroups({'group.profile.name': 'Eng'}, {'group.source.id': '0oa1'}, 100))`},
+		{expression: `{"a", user.isManager ? "b" : "c"}[0] == "a"`},
+		{
+			expression: `user.isMemberOf({'group.id': usr.groupId})`,
+			err:        `column 30: unknown identifier "usr", expected one of "user"`,
+		},
+		{
+			expression: `user.isMemberOf({'group.id': '00g1', group: '00g2'})`,
+			err:        `column 38: unexpected "group"`,
+		},
+		{
+			expression: `user.isMemberOf({'group.id' '00g1'})`,
+			err:        `column 29: unexpected "00g1"`,
+		},
+		{
+			expression: `String.stringContains(user.department "Eng")`,
+			err:        `column 39: unexpected "Eng"`,
+		},
+		{
+			expression: `user.department == "Eng`,
+			err:        "column 20: unterminated string",
+		},
+		{
+			expression: `user.department ==`,
+			err:        "column 19: unexpected end of expression",
+		},
+		{
+			expression: ``,
+			err:        "column 1: expression is empty",
+		},
+		{
+			expression: `String.stringContain(user.department, "Eng")`,
+			err:        `column 8: unknown function "String.stringContain"`,
+		},
+		{
+			expression: `isMemberOfGroupNames("Admins")`,
+			err:        `column 1: unknown function "isMemberOfGroupNames"`,
+		},
+		{
+			expression: `Arrays.contains(user.costCenters)`,
+			err:        "column 16: Arrays.contains expects 2 arguments, got 1",
+		},
+		{
+			expression: `isMemberOfAnyGroup()`,
+			err:        "column 19: isMemberOfAnyGroup expects at least 1 arguments, got 0",
+		},
+		{
+			expression: `usr.department == "Engineering"`,
+			err:        `column 1: unknown identifier "usr", expected one of "user"`,
+		},
+		{
+			expression: `user.getManager()`,
+			err:        `column 6: unknown method "getManager" of "user"`,
+		},
+		{
+			expression: `String.len == 3`,
+			err:        "column 1: String.len must be called as a function",
+		},
+	}
+	for _, test := range tests {
+		_, err := Validate(test.expression, "user")
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%s: unexpected error %v", test.expression, err)
+		case test.err != "" && err == nil:
+			t.Errorf("%s: expected error %q", test.expression, test.err)
+		case test.err != "" && err.Error() != test.err:
+			t.Errorf("%s: expected error %q, got %q", test.expression, test.err, err.Error())
+		}
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	n, err := Validate(`String.stringContains(user.department, "Eng") && user.getLinkedObject("manager").department == user.department || Arrays.contains(user.costCenters, source.costCenter)`, "user", "source")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	expected := []string{"department", "costCenters"}
+	if attrs := Attributes(n, "user"); !reflect.DeepEqual(attrs, expected) {
+		t.Errorf("expected %v, got %v", expected, attrs)
+	}
+}
+
+func TestAttributesMap(t *testing.T) {
+	n, err := Validate(`user.isMemberOf({'group.profile.name': user.department, 'operator': 'EXACT'})`, "user")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	m := n.(*Call).Args[0].(*Map)
+	if !reflect.DeepEqual(m.Keys, []string{"group.profile.name", "operator"}) {
+		t.Errorf("expected the keys of the map, got %v", m.Keys)
+	}
+	expected := []string{"department"}
+	if attrs := Attributes(n, "user"); !reflect.DeepEqual(attrs, expected) {
+		t.Errorf("expected %v, got %v", expected, attrs)
+	}
+}
@@ -0,0 +1,483 @@
+// Package expression parses the subset of the Okta Expression Language used
+// by group rules and profile mappings, see:
+// https://developer.okta.com/docs/reference/okta-expression-language/
+package expression
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Node is a node of a parsed expression.
+type Node interface {
+	// Pos is the byte offset of the node in the expression.
+	Pos() int
+}
+
+type (
+	// Literal is a string, number, boolean or null literal.
+	Literal struct {
+		Offset int
+		Value  string
+	}
+
+	// Ident is a bare identifier, e.g. user or String.
+	Ident struct {
+		Offset int
+		Name   string
+	}
+
+	// Member is a property access, e.g. user.department.
+	Member struct {
+		Offset int
+		X      Node
+		Name   string
+	}
+
+	// Index is an indexed access, e.g. user.emails[0].
+	Index struct {
+		Offset int
+		X      Node
+		Index  Node
+	}
+
+	// Call is a function or method call, e.g. String.len(user.login).
+	Call struct {
+		Offset int
+		Fun    Node
+		Args   []Node
+	}
+
+	// Unary is a negation, e.g. !isMemberOfGroupName("Admins").
+	Unary struct {
+		Offset int
+		Op     string
+		X      Node
+	}
+
+	// Binary is a binary operation, e.g. user.department == "Engineering".
+	Binary struct {
+		Offset int
+		Op     string
+		X      Node
+		Y      Node
+	}
+
+	// Ternary is a conditional expression, Else is the Then value when Then
+	// is nil for the elvis operator, e.g. user.nickName ?: user.firstName.
+	Ternary struct {
+		Offset int
+		Cond   Node
+		Then   Node
+		Else   Node
+	}
+
+	// List is an inline list, e.g. {"a", "b"}.
+	List struct {
+		Offset int
+		Elems  []Node
+	}
+
+	// Map is an inline map of quoted keys, e.g. the group filter
+	// {'group.profile.name': 'Eng', 'operator': 'STARTS_WITH'} of
+	// user.isMemberOf.
+	Map struct {
+		Offset int
+		Keys   []string
+		Values []Node
+	}
+)
+
+func (n *Literal) Pos() int { return n.Offset }
+func (n *Ident) Pos() int   { return n.Offset }
+func (n *Member) Pos() int  { return n.Offset }
+func (n *Index) Pos() int   { return n.Offset }
+func (n *Call) Pos() int    { return n.Offset }
+func (n *Unary) Pos() int   { return n.Offset }
+func (n *Binary) Pos() int  { return n.Offset }
+func (n *Ternary) Pos() int { return n.Offset }
+func (n *List) Pos() int    { return n.Offset }
+func (n *Map) Pos() int     { return n.Offset }
+
+// Error is a syntax or validation error at a position in the expression.
+type Error struct {
+	Offset int
+	Msg    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Offset+1, e.Msg)
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenOp
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	offset int
+}
+
+// textual operators are case insensitive, e.g. AND and and
+var textOps = map[string]string{
+	"and":     "&&",
+	"or":      "||",
+	"not":     "!",
+	"eq":      "==",
+	"ne":      "!=",
+	"lt":      "<",
+	"le":      "<=",
+	"gt":      ">",
+	"ge":      ">=",
+	"matches": "matches",
+}
+
+var symbolOps = []string{
+	"?.", "?:", "==", "!=", "<=", ">=", "&&", "||",
+	".", ",", "(", ")", "[", "]", "{", "}", "?", ":", "!", "<", ">", "+", "-", "*", "/", "%",
+}
+
+func lex(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '_' || c == '$' || unicode.IsLetter(c):
+			start := i
+			for i < len(src) && (src[i] == '_' || src[i] == '$' || unicode.IsLetter(rune(src[i])) || unicode.IsDigit(rune(src[i]))) {
+				i++
+			}
+			text := src[start:i]
+			if op, ok := textOps[strings.ToLower(text)]; ok {
+				tokens = append(tokens, token{tokenOp, op, start})
+			} else {
+				tokens = append(tokens, token{tokenIdent, text, start})
+			}
+		case unicode.IsDigit(c):
+			start := i
+			for i < len(src) && (unicode.IsDigit(rune(src[i])) || src[i] == '.' && i+1 < len(src) && unicode.IsDigit(rune(src[i+1]))) {
+				i++
+			}
+			tokens = append(tokens, token{tokenNumber, src[start:i], start})
+		case c == '"' || c == '\'':
+			start := i
+			value, end, ok := lexString(src, i)
+			if !ok {
+				return nil, &Error{start, "unterminated string"}
+			}
+			tokens = append(tokens, token{tokenString, value, start})
+			i = end
+		default:
+			matched := false
+			for _, op := range symbolOps {
+				if strings.HasPrefix(src[i:], op) {
+					tokens = append(tokens, token{tokenOp, op, i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, &Error{i, fmt.Sprintf("unexpected character %q", c)}
+			}
+		}
+	}
+	return append(tokens, token{tokenEOF, "", len(src)}), nil
+}
+
+// lexString reads a quoted string starting at i. Quotes are escaped either
+// with a backslash or by doubling them.
+func lexString(src string, i int) (string, int, bool) {
+	quote := src[i]
+	var b strings.Builder
+	for i++; i < len(src); i++ {
+		switch {
+		case src[i] == '\\' && i+1 < len(src):
+			i++
+			b.WriteByte(src[i])
+		case src[i] == quote && i+1 < len(src) && src[i+1] == quote:
+			i++
+			b.WriteByte(quote)
+		case src[i] == quote:
+			return b.String(), i + 1, true
+		default:
+			b.WriteByte(src[i])
+		}
+	}
+	return "", i, false
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// Parse parses an expression.
+func Parse(src string) (Node, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, &Error{0, "expression is empty"}
+	}
+	n, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.unexpected(t)
+	}
+	return n, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isOp(ops ...string) bool {
+	t := p.peek()
+	if t.kind != tokenOp {
+		return false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) expect(op string) error {
+	if !p.isOp(op) {
+		return p.unexpected(p.peek())
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) unexpected(t token) error {
+	if t.kind == tokenEOF {
+		return &Error{t.offset, "unexpected end of expression"}
+	}
+	return &Error{t.offset, fmt.Sprintf("unexpected %q", t.text)}
+}
+
+func (p *parser) expr() (Node, error) {
+	cond, err := p.binary(0)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case p.isOp("?:"):
+		t := p.next()
+		alt, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		return &Ternary{Offset: t.offset, Cond: cond, Else: alt}, nil
+	case p.isOp("?"):
+		t := p.next()
+		then, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		alt, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		return &Ternary{Offset: t.offset, Cond: cond, Then: then, Else: alt}, nil
+	}
+	return cond, nil
+}
+
+// binaryOps are the binary operators from lowest to highest precedence.
+var binaryOps = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", "<=", ">", ">=", "matches"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *parser) binary(level int) (Node, error) {
+	if level == len(binaryOps) {
+		return p.unary()
+	}
+	x, err := p.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp(binaryOps[level]...) {
+		t := p.next()
+		y, err := p.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		x = &Binary{Offset: t.offset, Op: t.text, X: x, Y: y}
+	}
+	return x, nil
+}
+
+func (p *parser) unary() (Node, error) {
+	if p.isOp("!", "-") {
+		t := p.next()
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &Unary{Offset: t.offset, Op: t.text, X: x}, nil
+	}
+	return p.postfix()
+}
+
+func (p *parser) postfix() (Node, error) {
+	x, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.isOp(".", "?."):
+			p.next()
+			t := p.next()
+			if t.kind != tokenIdent {
+				return nil, p.unexpected(t)
+			}
+			x = &Member{Offset: t.offset, X: x, Name: t.text}
+		case p.isOp("("):
+			t := p.next()
+			args, err := p.list(")")
+			if err != nil {
+				return nil, err
+			}
+			x = &Call{Offset: t.offset, Fun: x, Args: args}
+		case p.isOp("["):
+			t := p.next()
+			index, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			x = &Index{Offset: t.offset, X: x, Index: index}
+		default:
+			return x, nil
+		}
+	}
+}
+
+func (p *parser) primary() (Node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber, tokenString:
+		return &Literal{Offset: t.offset, Value: t.text}, nil
+	case tokenIdent:
+		switch t.text {
+		case "true", "false", "null":
+			return &Literal{Offset: t.offset, Value: t.text}, nil
+		}
+		return &Ident{Offset: t.offset, Name: t.text}, nil
+	case tokenOp:
+		switch t.text {
+		case "(":
+			x, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		case "{":
+			if p.isMapKey() {
+				return p.mapEntries(t.offset)
+			}
+			elems, err := p.list("}")
+			if err != nil {
+				return nil, err
+			}
+			return &List{Offset: t.offset, Elems: elems}, nil
+		}
+	}
+	return nil, p.unexpected(t)
+}
+
+// list parses comma separated expressions up to and including the closing
+// operator.
+func (p *parser) list(closing string) ([]Node, error) {
+	var elems []Node
+	if p.isOp(closing) {
+		p.next()
+		return elems, nil
+	}
+	for {
+		elem, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, elem)
+		if p.isOp(",") {
+			p.next()
+			continue
+		}
+		return elems, p.expect(closing)
+	}
+}
+
+// isMapKey reports whether the next tokens are a quoted key followed by a
+// colon, i.e. the brace just read opens a map rather than a list.
+func (p *parser) isMapKey() bool {
+	return p.peek().kind == tokenString && p.pos+1 < len(p.tokens) &&
+		p.tokens[p.pos+1].kind == tokenOp && p.tokens[p.pos+1].text == ":"
+}
+
+// mapEntries parses the comma separated entries of a map up to and including
+// the closing brace.
+func (p *parser) mapEntries(offset int) (Node, error) {
+	m := &Map{Offset: offset}
+	for {
+		key := p.next()
+		if key.kind != tokenString {
+			return nil, p.unexpected(key)
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		value, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		m.Keys = append(m.Keys, key.text)
+		m.Values = append(m.Values, value)
+		if p.isOp(",") {
+			p.next()
+			continue
+		}
+		return m, p.expect("}")
+	}
+}
@@ -0,0 +1,289 @@
+package expression
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// variadic marks a function with no maximum number of arguments.
+const variadic = -1
+
+type arity struct {
+	min int
+	max int
+}
+
+// namespaces are the function namespaces of the expression language, e.g.
+// String as in String.len(user.login).
+var namespaces = map[string]map[string]arity{
+	"String": {
+		"append":          {2, 2},
+		"endsWith":        {2, 2},
+		"join":            {2, variadic},
+		"len":             {1, 1},
+		"removeSpaces":    {1, 1},
+		"replace":         {3, 3},
+		"replaceFirst":    {3, 3},
+		"startsWith":      {2, 2},
+		"stringContains":  {2, 2},
+		"stringSwitch":    {3, variadic},
+		"substring":       {3, 3},
+		"substringAfter":  {2, 2},
+		"substringBefore": {2, 2},
+		"toLowerCase":     {1, 1},
+		"toUpperCase":     {1, 1},
+	},
+	"Arrays": {
+		"add":         {2, 2},
+		"clear":       {1, 1},
+		"contains":    {2, 2},
+		"flatten":     {1, variadic},
+		"get":         {2, 2},
+		"isEmpty":     {1, 1},
+		"remove":      {2, 2},
+		"size":        {1, 1},
+		"toCsvString": {1, 1},
+	},
+	"Convert": {
+		"toInt": {1, 1},
+		"toNum": {1, 1},
+	},
+	"Iso3166Convert": {
+		"toAlpha2":  {1, 1},
+		"toAlpha3":  {1, 1},
+		"toName":    {1, 1},
+		"toNumeric": {1, 1},
+	},
+	"Groups": {
+		"contains":   {3, 3},
+		"endsWith":   {3, 3},
+		"startsWith": {3, 3},
+	},
+	"Time": {
+		"fromIso8601ToString":  {2, 2},
+		"fromIso8601ToUnix":    {1, 1},
+		"fromIso8601ToWindows": {1, 1},
+		"fromStringToIso8601":  {2, 2},
+		"fromUnixToIso8601":    {1, 1},
+		"fromWindowsToIso8601": {1, 1},
+		"now":                  {0, 1},
+	},
+}
+
+// functions are the functions called without a namespace.
+var functions = map[string]arity{
+	"findDirectoryUser":             {0, 0},
+	"findWorkdayUser":               {0, 0},
+	"getAssistantAppUser":           {2, 2},
+	"getAssistantUser":              {1, 1},
+	"getFilteredGroups":             {3, 3},
+	"getManagerAppUser":             {2, 2},
+	"getManagerUser":                {1, 1},
+	"hasDirectoryUser":              {0, 0},
+	"hasWorkdayUser":                {0, 0},
+	"isMemberOfAnyGroup":            {1, variadic},
+	"isMemberOfGroup":               {1, 1},
+	"isMemberOfGroupName":           {1, 1},
+	"isMemberOfGroupNameContains":   {1, 1},
+	"isMemberOfGroupNameRegex":      {1, 1},
+	"isMemberOfGroupNameStartsWith": {1, 1},
+}
+
+// methods are the methods that can be called on a root, e.g.
+// user.getLinkedObject("manager").
+var methods = map[string]arity{
+	"getGroups":           {0, variadic},
+	"getInternalProperty": {1, 1},
+	"getLinkedObject":     {1, 1},
+	"isMemberOf":          {1, variadic},
+}
+
+// Validate parses the expression and checks it only calls known functions
+// with the number of arguments they accept, and that every other identifier
+// is one of roots, e.g. "user" for group rules.
+func Validate(src string, roots ...string) (Node, error) {
+	n, err := Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	v := &validator{roots: map[string]bool{}}
+	for _, root := range roots {
+		v.roots[root] = true
+	}
+	if err := v.validate(n); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+type validator struct {
+	roots map[string]bool
+}
+
+func (v *validator) validate(n Node) error {
+	switch n := n.(type) {
+	case *Ident:
+		if !v.roots[n.Name] {
+			return &Error{n.Offset, fmt.Sprintf("unknown identifier %q, expected one of %s", n.Name, v.rootNames())}
+		}
+	case *Member:
+		if ident, ok := n.X.(*Ident); ok && namespaces[ident.Name] != nil {
+			return &Error{ident.Offset, fmt.Sprintf("%s.%s must be called as a function", ident.Name, n.Name)}
+		}
+		return v.validate(n.X)
+	case *Index:
+		if err := v.validate(n.X); err != nil {
+			return err
+		}
+		return v.validate(n.Index)
+	case *Call:
+		if err := v.validateCallee(n); err != nil {
+			return err
+		}
+		for _, arg := range n.Args {
+			if err := v.validate(arg); err != nil {
+				return err
+			}
+		}
+	case *Unary:
+		return v.validate(n.X)
+	case *Binary:
+		if err := v.validate(n.X); err != nil {
+			return err
+		}
+		return v.validate(n.Y)
+	case *Ternary:
+		for _, x := range []Node{n.Cond, n.Then, n.Else} {
+			if x == nil {
+				continue
+			}
+			if err := v.validate(x); err != nil {
+				return err
+			}
+		}
+	case *List:
+		for _, elem := range n.Elems {
+			if err := v.validate(elem); err != nil {
+				return err
+			}
+		}
+	case *Map:
+		for _, value := range n.Values {
+			if err := v.validate(value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (v *validator) validateCallee(call *Call) error {
+	switch fun := call.Fun.(type) {
+	case *Ident:
+		a, ok := functions[fun.Name]
+		if !ok {
+			return &Error{fun.Offset, fmt.Sprintf("unknown function %q", fun.Name)}
+		}
+		return checkArity(call, fun.Name, a)
+	case *Member:
+		ident, ok := fun.X.(*Ident)
+		if !ok {
+			// a method of a value, e.g. user.login.toLowerCase()
+			return v.validate(fun.X)
+		}
+		if fns, ok := namespaces[ident.Name]; ok {
+			name := ident.Name + "." + fun.Name
+			a, ok := fns[fun.Name]
+			if !ok {
+				return &Error{fun.Offset, fmt.Sprintf("unknown function %q", name)}
+			}
+			return checkArity(call, name, a)
+		}
+		if err := v.validate(ident); err != nil {
+			return err
+		}
+		a, ok := methods[fun.Name]
+		if !ok {
+			return &Error{fun.Offset, fmt.Sprintf("unknown method %q of %q", fun.Name, ident.Name)}
+		}
+		return checkArity(call, ident.Name+"."+fun.Name, a)
+	}
+	return v.validate(call.Fun)
+}
+
+func checkArity(call *Call, name string, a arity) error {
+	n := len(call.Args)
+	if n >= a.min && (a.max == variadic || n <= a.max) {
+		return nil
+	}
+	expected := fmt.Sprintf("%d to %d", a.min, a.max)
+	switch {
+	case a.max == variadic:
+		expected = fmt.Sprintf("at least %d", a.min)
+	case a.min == a.max:
+		expected = fmt.Sprintf("%d", a.min)
+	}
+	return &Error{call.Offset, fmt.Sprintf("%s expects %s arguments, got %d", name, expected, n)}
+}
+
+func (v *validator) rootNames() string {
+	names := make([]string, 0, len(v.roots))
+	for name := range v.roots {
+		names = append(names, fmt.Sprintf("%q", name))
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// Attributes returns the names of the attributes the expression reads from
+// root, e.g. "department" for user.department. Methods called on root, e.g.
+// user.getLinkedObject("manager"), aren't attributes.
+func Attributes(n Node, root string) []string {
+	seen := map[string]bool{}
+	var attrs []string
+	var walk func(n Node)
+	walk = func(n Node) {
+		switch n := n.(type) {
+		case *Member:
+			if ident, ok := n.X.(*Ident); ok && ident.Name == root && !seen[n.Name] {
+				seen[n.Name] = true
+				attrs = append(attrs, n.Name)
+			}
+			walk(n.X)
+		case *Index:
+			walk(n.X)
+			walk(n.Index)
+		case *Call:
+			if fun, ok := n.Fun.(*Member); ok {
+				walk(fun.X)
+			} else {
+				walk(n.Fun)
+			}
+			for _, arg := range n.Args {
+				walk(arg)
+			}
+		case *Unary:
+			walk(n.X)
+		case *Binary:
+			walk(n.X)
+			walk(n.Y)
+		case *Ternary:
+			walk(n.Cond)
+			if n.Then != nil {
+				walk(n.Then)
+			}
+			walk(n.Else)
+		case *List:
+			for _, elem := range n.Elems {
+				walk(elem)
+			}
+		case *Map:
+			for _, value := range n.Values {
+				walk(value)
+			}
+		}
+	}
+	walk(n)
+	return attrs
+}
@@ -0,0 +1,158 @@
+// Package hookreceiver is a local endpoint for Okta event and inline hooks, for
+// tests of hooks that can't rely on an internet-reachable endpoint. It answers
+// the one-time verification request of event hooks, records the events and
+// payloads delivered to it and answers inline hooks with the configured
+// response, refusing requests without the auth header and custom headers the
+// hook is configured with.
+package hookreceiver
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// VerificationHeader is the header of the one-time verification request
+// holding the challenge the endpoint echoes back.
+const VerificationHeader = "X-Okta-Verification-Challenge"
+
+// Config is the auth and headers of the event hook the receiver expects, the
+// auth and headers blocks of okta_event_hook.
+type Config struct {
+	// AuthKey is the name of the auth header, e.g. Authorization, no auth
+	// header is required when it's empty.
+	AuthKey string
+	// AuthValue is the secret the auth header must hold.
+	AuthValue string
+	// Headers are the custom headers every request must carry.
+	Headers map[string]string
+	// Response is the JSON response to POST requests, e.g. the commands of
+	// an inline hook, the response has no body when it's nil.
+	Response interface{}
+}
+
+// Target is an entity the event is about, like the user created.
+type Target struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	AlternateID string `json:"alternateId"`
+	DisplayName string `json:"displayName"`
+}
+
+// Event is an event delivered to the hook.
+type Event struct {
+	UUID           string   `json:"uuid"`
+	Published      string   `json:"published"`
+	EventType      string   `json:"eventType"`
+	DisplayMessage string   `json:"displayMessage"`
+	Target         []Target `json:"target"`
+}
+
+// Receiver is a hook endpoint served over http.
+type Receiver struct {
+	*httptest.Server
+
+	config   Config
+	mu       sync.Mutex
+	verified bool
+	events   []Event
+	payloads []map[string]interface{}
+	refused  int
+}
+
+// New starts a receiver expecting the auth and headers of the config.
+func New(config Config) *Receiver {
+	r := &Receiver{config: config}
+	r.Server = httptest.NewServer(r)
+	return r
+}
+
+// ServeHTTP answers the verification challenge of GET requests and records
+// the payloads and events of POST requests.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.authorized(req) {
+		r.refused++
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch req.Method {
+	case http.MethodGet:
+		challenge := req.Header.Get(VerificationHeader)
+		if challenge == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.verified = true
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"verification": challenge})
+	case http.MethodPost:
+		body, err := io.ReadAll(req.Body)
+		var payload map[string]interface{}
+		var delivery struct {
+			Data struct {
+				Events []Event `json:"events"`
+			} `json:"data"`
+		}
+		if err != nil || json.Unmarshal(body, &payload) != nil || json.Unmarshal(body, &delivery) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.payloads = append(r.payloads, payload)
+		r.events = append(r.events, delivery.Data.Events...)
+		if r.config.Response == nil {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(r.config.Response)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (r *Receiver) authorized(req *http.Request) bool {
+	if r.config.AuthKey != "" && req.Header.Get(r.config.AuthKey) != r.config.AuthValue {
+		return false
+	}
+	for k, v := range r.config.Headers {
+		if req.Header.Get(k) != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Verified reports whether the receiver answered a verification request.
+func (r *Receiver) Verified() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.verified
+}
+
+// Events returns the events delivered so far, in delivery order.
+func (r *Receiver) Events() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Event(nil), r.events...)
+}
+
+// Payloads returns the payloads of the POST requests so far, like the
+// requests of an inline hook, in delivery order.
+func (r *Receiver) Payloads() []map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]map[string]interface{}(nil), r.payloads...)
+}
+
+// Refused returns the number of requests refused for a missing or wrong auth
+// header or custom header.
+func (r *Receiver) Refused() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.refused
+}
@@ -0,0 +1,65 @@
+package hookreceiver
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestReceiver(t *testing.T) {
+	r := New(Config{AuthKey: "Authorization", AuthValue: "secret", Headers: map[string]string{"x-env": "test"}})
+	t.Cleanup(r.Close)
+	do := func(method, body string, headers map[string]string) *http.Response {
+		req, _ := http.NewRequest(method, r.URL, bytes.NewBufferString(body))
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { res.Body.Close() })
+		return res
+	}
+	headers := map[string]string{"Authorization": "secret", "x-env": "test", VerificationHeader: "abc"}
+
+	res := do(http.MethodGet, "", map[string]string{"Authorization": "wrong", "x-env": "test", VerificationHeader: "abc"})
+	if res.StatusCode != http.StatusUnauthorized || r.Verified() || r.Refused() != 1 {
+		t.Errorf("expected the verification with the wrong secret to be refused, got %d", res.StatusCode)
+	}
+	res = do(http.MethodGet, "", map[string]string{"Authorization": "secret", VerificationHeader: "abc"})
+	if res.StatusCode != http.StatusUnauthorized || r.Refused() != 2 {
+		t.Errorf("expected the verification without the custom header to be refused, got %d", res.StatusCode)
+	}
+
+	res = do(http.MethodGet, "", headers)
+	var verification map[string]string
+	if err := json.NewDecoder(res.Body).Decode(&verification); err != nil || verification["verification"] != "abc" || !r.Verified() {
+		t.Errorf("expected the challenge to be echoed back, got %v: %v", verification, err)
+	}
+
+	res = do(http.MethodPost, `{"eventType":"com.okta.event_hook","data":{"events":[{"uuid":"1","eventType":"user.lifecycle.create","target":[{"id":"00u1","type":"User"}]}]}}`, headers)
+	events := r.Events()
+	if res.StatusCode != http.StatusOK || len(events) != 1 || events[0].EventType != "user.lifecycle.create" || events[0].Target[0].ID != "00u1" {
+		t.Errorf("expected the event to be recorded, got %d %v", res.StatusCode, events)
+	}
+}
+
+func TestReceiverInlineHook(t *testing.T) {
+	r := New(Config{Response: map[string]interface{}{"commands": []interface{}{}}})
+	t.Cleanup(r.Close)
+	res, err := http.Post(r.URL, "application/json", bytes.NewBufferString(`{"eventType":"com.okta.user.pre-registration","data":{"action":"ALLOW"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	var response map[string]interface{}
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil || response["commands"] == nil {
+		t.Errorf("expected the configured response, got %v: %v", response, err)
+	}
+	payloads := r.Payloads()
+	if len(payloads) != 1 || payloads[0]["eventType"] != "com.okta.user.pre-registration" || len(r.Events()) != 0 {
+		t.Errorf("expected the payload to be recorded without events, got %v", payloads)
+	}
+}
@@ -0,0 +1,381 @@
+package mockokta
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+var nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+
+func (s *Server) appRoutes() {
+	s.handle(http.MethodGet, "/api/v1/apps", s.listApps)
+	s.handle(http.MethodPost, "/api/v1/apps", s.createApp)
+	s.handle(http.MethodGet, "/api/v1/apps/{}", s.getApp)
+	s.handle(http.MethodPut, "/api/v1/apps/{}", s.replaceApp)
+	s.handle(http.MethodDelete, "/api/v1/apps/{}", s.deleteApp)
+	s.handle(http.MethodPost, "/api/v1/apps/{}/lifecycle/{}", s.changeAppLifecycle)
+	s.handle(http.MethodPut, "/api/v1/apps/{}/policies/{}", s.setAppAccessPolicy)
+	s.handle(http.MethodGet, "/api/v1/apps/{}/users", s.listAppUsers)
+	s.handle(http.MethodPost, "/api/v1/apps/{}/users", s.assignAppUser)
+	s.handle(http.MethodGet, "/api/v1/apps/{}/users/{}", s.getAppUser)
+	s.handle(http.MethodPost, "/api/v1/apps/{}/users/{}", s.updateAppUser)
+	s.handle(http.MethodDelete, "/api/v1/apps/{}/users/{}", s.unassignAppUser)
+	s.handle(http.MethodGet, "/api/v1/apps/{}/groups", s.listAppGroups)
+	s.handle(http.MethodGet, "/api/v1/apps/{}/groups/{}", s.getAppGroup)
+	s.handle(http.MethodPut, "/api/v1/apps/{}/groups/{}", s.assignAppGroup)
+	s.handle(http.MethodDelete, "/api/v1/apps/{}/groups/{}", s.unassignAppGroup)
+}
+
+func (s *Server) listApps(w http.ResponseWriter, r *http.Request, _ []string) {
+	filter := listFilter(r, objectResolver)
+	if expression := r.URL.Query().Get("filter"); strings.HasPrefix(expression, "user.id eq ") || strings.HasPrefix(expression, "group.id eq ") {
+		// an app is listed when any of its users or groups matches
+		kind, id := strings.SplitN(expression, ".", 2)[0], strings.Trim(strings.SplitN(expression, " eq ", 2)[1], `"`)
+		filter = func(app object) bool {
+			assignments := s.appUsers[app["id"].(string)]
+			if kind == "group" {
+				assignments = s.appGroups[app["id"].(string)]
+			}
+			_, ok := assignments.get(id)
+			return ok
+		}
+	}
+	apps := s.apps.list(allOf(filter, queryPrefix(r, "label", "name")))
+	s.writeList(w, r, apps, 20, 200)
+}
+
+func (s *Server) createApp(w http.ResponseWriter, r *http.Request, _ []string) {
+	app, ok := s.decode(w, r)
+	if !ok {
+		return
+	}
+	label, _ := app["label"].(string)
+	if label == "" {
+		s.writeValidationError(w, "label", "The field cannot be left blank")
+		return
+	}
+	signOnMode, _ := app["signOnMode"].(string)
+	if name, _ := app["name"].(string); name == "" {
+		switch signOnMode {
+		case "OPENID_CONNECT":
+			app["name"] = "oidc_client"
+		default:
+			app["name"] = fmt.Sprintf("mockorg_%s_1", nonAlphanumeric.ReplaceAllString(strings.ToLower(label), ""))
+		}
+	}
+	id := s.newID("0oa")
+	now := s.now()
+	app["id"] = id
+	app["created"] = now
+	app["lastUpdated"] = now
+	app["status"] = "ACTIVE"
+	if !queryBool(r, "activate", true) {
+		app["status"] = "INACTIVE"
+	}
+	if _, ok := app["visibility"]; !ok {
+		app["visibility"] = object{"autoSubmitToolbar": false, "hide": object{"iOS": false, "web": false}}
+	}
+	if _, ok := app["features"]; !ok {
+		app["features"] = []interface{}{}
+	}
+	credentials, _ := app["credentials"].(map[string]interface{})
+	if credentials == nil {
+		credentials = object{}
+		app["credentials"] = credentials
+	}
+	credentials["signing"] = object{"kid": s.newID("kid")}
+	if signOnMode == "OPENID_CONNECT" {
+		oauthClient, _ := credentials["oauthClient"].(map[string]interface{})
+		if oauthClient == nil {
+			oauthClient = object{}
+			credentials["oauthClient"] = oauthClient
+		}
+		if _, ok := oauthClient["client_id"]; !ok {
+			oauthClient["client_id"] = id
+		}
+		method, _ := oauthClient["token_endpoint_auth_method"].(string)
+		if method == "" {
+			method = "client_secret_basic"
+			oauthClient["token_endpoint_auth_method"] = method
+		}
+		if _, ok := oauthClient["client_secret"]; !ok && strings.HasPrefix(method, "client_secret") {
+			oauthClient["client_secret"] = s.newID("secret")
+		}
+	}
+	app["_links"] = s.links("/api/v1/apps/" + id)
+	if s.pipeline == PipelineIdx {
+		// apps of an Identity Engine org start with the default
+		// authentication policy
+		for _, policy := range s.policiesOfType("ACCESS_POLICY") {
+			if policy["system"] == true {
+				s.setAccessPolicyLink(app, policy["id"].(string))
+			}
+		}
+	}
+	s.apps.add(id, app)
+	s.appUsers[id] = newCollection()
+	s.appGroups[id] = newCollection()
+	s.writeJSON(w, http.StatusOK, app)
+}
+
+func (s *Server) getApp(w http.ResponseWriter, r *http.Request, params []string) {
+	app, ok := s.apps.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "AppInstance")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, app)
+}
+
+// replaceApp replaces an app, keeping its generated fields.
+func (s *Server) replaceApp(w http.ResponseWriter, r *http.Request, params []string) {
+	app, ok := s.apps.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "AppInstance")
+		return
+	}
+	body, ok := s.decode(w, r)
+	if !ok {
+		return
+	}
+	if label, _ := body["label"].(string); label == "" {
+		s.writeValidationError(w, "label", "The field cannot be left blank")
+		return
+	}
+	credentials, _ := app["credentials"].(map[string]interface{})
+	if update, ok := body["credentials"].(map[string]interface{}); ok {
+		merge(credentials, update)
+	}
+	for _, key := range []string{"id", "name", "created", "status", "_links"} {
+		body[key] = app[key]
+	}
+	body["credentials"] = credentials
+	body["lastUpdated"] = s.now()
+	s.apps.add(params[0], body)
+	s.writeJSON(w, http.StatusOK, body)
+}
+
+// deleteApp deletes an app, which must be deactivated first.
+func (s *Server) deleteApp(w http.ResponseWriter, r *http.Request, params []string) {
+	app, ok := s.apps.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "AppInstance")
+		return
+	}
+	if app["status"] == "ACTIVE" {
+		s.writeError(w, http.StatusForbidden, "E0000056", "Delete application forbidden.", "The application must be deactivated before it is deleted.")
+		return
+	}
+	s.apps.remove(params[0])
+	delete(s.appUsers, params[0])
+	delete(s.appGroups, params[0])
+	s.writeNoContent(w)
+}
+
+func (s *Server) changeAppLifecycle(w http.ResponseWriter, r *http.Request, params []string) {
+	app, ok := s.apps.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "AppInstance")
+		return
+	}
+	switch params[1] {
+	case "activate":
+		app["status"] = "ACTIVE"
+	case "deactivate":
+		app["status"] = "INACTIVE"
+	default:
+		s.writeNotFound(w, params[1], "Lifecycle operation")
+		return
+	}
+	app["lastUpdated"] = s.now()
+	s.writeJSON(w, http.StatusOK, object{})
+}
+
+// setAppAccessPolicy sets the authentication policy of an app.
+func (s *Server) setAppAccessPolicy(w http.ResponseWriter, r *http.Request, params []string) {
+	app, ok := s.apps.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "AppInstance")
+		return
+	}
+	policy, ok := s.policies.get(params[1])
+	if !ok || policy["type"] != "ACCESS_POLICY" {
+		s.writeNotFound(w, params[1], "Policy")
+		return
+	}
+	s.setAccessPolicyLink(app, params[1])
+	app["lastUpdated"] = s.now()
+	s.writeNoContent(w)
+}
+
+func (s *Server) setAccessPolicyLink(app object, policyID string) {
+	app["_links"].(map[string]interface{})["accessPolicy"] = object{"href": s.URL + "/api/v1/policies/" + policyID}
+}
+
+func (s *Server) listAppUsers(w http.ResponseWriter, r *http.Request, params []string) {
+	if _, ok := s.apps.get(params[0]); !ok {
+		s.writeNotFound(w, params[0], "AppInstance")
+		return
+	}
+	s.writeList(w, r, s.appUsers[params[0]].list(queryPrefix(r, "credentials.userName")), 50, 500)
+}
+
+func (s *Server) assignAppUser(w http.ResponseWriter, r *http.Request, params []string) {
+	if _, ok := s.apps.get(params[0]); !ok {
+		s.writeNotFound(w, params[0], "AppInstance")
+		return
+	}
+	appUser, ok := s.decode(w, r)
+	if !ok {
+		return
+	}
+	userID, _ := appUser["id"].(string)
+	user, ok := s.findUser(userID)
+	if !ok {
+		s.writeNotFound(w, userID, "User")
+		return
+	}
+	userID = user["id"].(string)
+	now := s.now()
+	credentials, _ := appUser["credentials"].(map[string]interface{})
+	if credentials == nil {
+		credentials = object{}
+	}
+	if _, ok := credentials["userName"]; !ok {
+		credentials["userName"] = stringField(user, "profile.login")
+	}
+	delete(credentials, "password")
+	if _, ok := appUser["profile"]; !ok {
+		appUser["profile"] = object{}
+	}
+	if _, ok := appUser["scope"]; !ok {
+		appUser["scope"] = "USER"
+	}
+	appUser["id"] = userID
+	appUser["credentials"] = credentials
+	appUser["status"] = "PROVISIONED"
+	appUser["syncState"] = "DISABLED"
+	appUser["created"] = now
+	appUser["lastUpdated"] = now
+	appUser["statusChanged"] = now
+	appUser["_links"] = s.links(fmt.Sprintf("/api/v1/apps/%s/users/%s", params[0], userID))
+	s.appUsers[params[0]].add(userID, appUser)
+	s.writeJSON(w, http.StatusOK, appUser)
+}
+
+func (s *Server) getAppUser(w http.ResponseWriter, r *http.Request, params []string) {
+	if _, ok := s.apps.get(params[0]); !ok {
+		s.writeNotFound(w, params[0], "AppInstance")
+		return
+	}
+	appUser, ok := s.appUsers[params[0]].get(params[1])
+	if !ok {
+		s.writeNotFound(w, params[1], "AppUser")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, appUser)
+}
+
+func (s *Server) updateAppUser(w http.ResponseWriter, r *http.Request, params []string) {
+	if _, ok := s.apps.get(params[0]); !ok {
+		s.writeNotFound(w, params[0], "AppInstance")
+		return
+	}
+	appUser, ok := s.appUsers[params[0]].get(params[1])
+	if !ok {
+		s.writeNotFound(w, params[1], "AppUser")
+		return
+	}
+	body, ok := s.decode(w, r)
+	if !ok {
+		return
+	}
+	if credentials, ok := body["credentials"].(map[string]interface{}); ok {
+		delete(credentials, "password")
+		merge(appUser["credentials"].(map[string]interface{}), credentials)
+	}
+	if profile, ok := body["profile"].(map[string]interface{}); ok {
+		appUser["profile"] = profile
+	}
+	appUser["lastUpdated"] = s.now()
+	s.writeJSON(w, http.StatusOK, appUser)
+}
+
+func (s *Server) unassignAppUser(w http.ResponseWriter, r *http.Request, params []string) {
+	if _, ok := s.apps.get(params[0]); !ok {
+		s.writeNotFound(w, params[0], "AppInstance")
+		return
+	}
+	if _, ok := s.appUsers[params[0]].get(params[1]); !ok {
+		s.writeNotFound(w, params[1], "AppUser")
+		return
+	}
+	s.appUsers[params[0]].remove(params[1])
+	s.writeNoContent(w)
+}
+
+func (s *Server) listAppGroups(w http.ResponseWriter, r *http.Request, params []string) {
+	if _, ok := s.apps.get(params[0]); !ok {
+		s.writeNotFound(w, params[0], "AppInstance")
+		return
+	}
+	s.writeList(w, r, s.appGroups[params[0]].list(nil), 20, 200)
+}
+
+func (s *Server) getAppGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	if _, ok := s.apps.get(params[0]); !ok {
+		s.writeNotFound(w, params[0], "AppInstance")
+		return
+	}
+	appGroup, ok := s.appGroups[params[0]].get(params[1])
+	if !ok {
+		s.writeNotFound(w, params[1], "ApplicationGroupAssignment")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, appGroup)
+}
+
+func (s *Server) assignAppGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	if _, ok := s.apps.get(params[0]); !ok {
+		s.writeNotFound(w, params[0], "AppInstance")
+		return
+	}
+	if _, ok := s.groups.get(params[1]); !ok {
+		s.writeNotFound(w, params[1], "UserGroup")
+		return
+	}
+	appGroup, ok := s.decode(w, r)
+	if !ok {
+		return
+	}
+	appGroups := s.appGroups[params[0]]
+	if _, ok := appGroup["priority"]; !ok {
+		appGroup["priority"] = len(appGroups.ids)
+		if existing, ok := appGroups.get(params[1]); ok {
+			appGroup["priority"] = existing["priority"]
+		}
+	}
+	if _, ok := appGroup["profile"]; !ok {
+		appGroup["profile"] = object{}
+	}
+	appGroup["id"] = params[1]
+	appGroup["lastUpdated"] = s.now()
+	appGroup["_links"] = s.links(fmt.Sprintf("/api/v1/apps/%s/groups/%s", params[0], params[1]))
+	appGroups.add(params[1], appGroup)
+	s.writeJSON(w, http.StatusOK, appGroup)
+}
+
+func (s *Server) unassignAppGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	if _, ok := s.apps.get(params[0]); !ok {
+		s.writeNotFound(w, params[0], "AppInstance")
+		return
+	}
+	if _, ok := s.appGroups[params[0]].get(params[1]); !ok {
+		s.writeNotFound(w, params[1], "ApplicationGroupAssignment")
+		return
+	}
+	s.appGroups[params[0]].remove(params[1])
+	s.writeNoContent(w)
+}
@@ -0,0 +1,582 @@
+package mockokta
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+func (s *Server) authServerRoutes() {
+	s.handle(http.MethodGet, "/api/v1/authorizationServers", s.listAuthServers)
+	s.handle(http.MethodPost, "/api/v1/authorizationServers", s.createAuthServer)
+	s.handle(http.MethodGet, "/api/v1/authorizationServers/{}", s.getAuthServer)
+	s.handle(http.MethodPut, "/api/v1/authorizationServers/{}", s.replaceAuthServer)
+	s.handle(http.MethodDelete, "/api/v1/authorizationServers/{}", s.deleteAuthServer)
+	s.handle(http.MethodPost, "/api/v1/authorizationServers/{}/lifecycle/{}", s.changeAuthServerLifecycle)
+	s.handle(http.MethodGet, "/api/v1/authorizationServers/{}/credentials/keys", s.listAuthServerKeys)
+	s.handle(http.MethodPost, "/api/v1/authorizationServers/{}/credentials/lifecycle/keyRotate", s.rotateAuthServerKeys)
+	s.handle(http.MethodPost, "/api/v1/authorizationServers/{}/clients/{}/tokens/preview", s.previewAuthServerTokens)
+	for _, kind := range []string{"scopes", "claims"} {
+		kind := kind
+		s.handle(http.MethodGet, "/api/v1/authorizationServers/{}/"+kind, func(w http.ResponseWriter, r *http.Request, params []string) {
+			s.listAuthServerChildren(w, r, params, kind)
+		})
+		s.handle(http.MethodPost, "/api/v1/authorizationServers/{}/"+kind, func(w http.ResponseWriter, r *http.Request, params []string) {
+			s.createAuthServerChild(w, r, params, kind)
+		})
+		s.handle(http.MethodGet, "/api/v1/authorizationServers/{}/"+kind+"/{}", func(w http.ResponseWriter, r *http.Request, params []string) {
+			s.getAuthServerChild(w, r, params, kind)
+		})
+		s.handle(http.MethodPut, "/api/v1/authorizationServers/{}/"+kind+"/{}", func(w http.ResponseWriter, r *http.Request, params []string) {
+			s.replaceAuthServerChild(w, r, params, kind)
+		})
+		s.handle(http.MethodDelete, "/api/v1/authorizationServers/{}/"+kind+"/{}", func(w http.ResponseWriter, r *http.Request, params []string) {
+			s.deleteAuthServerChild(w, r, params, kind)
+		})
+	}
+	s.handle(http.MethodGet, "/api/v1/authorizationServers/{}/policies", s.listAuthServerPolicies)
+	s.handle(http.MethodPost, "/api/v1/authorizationServers/{}/policies", s.createAuthServerPolicy)
+	s.handle(http.MethodGet, "/api/v1/authorizationServers/{}/policies/{}", s.getAuthServerPolicy)
+	s.handle(http.MethodPut, "/api/v1/authorizationServers/{}/policies/{}", s.replaceAuthServerPolicy)
+	s.handle(http.MethodDelete, "/api/v1/authorizationServers/{}/policies/{}", s.deleteAuthServerPolicy)
+	s.handle(http.MethodPost, "/api/v1/authorizationServers/{}/policies/{}/lifecycle/{}", s.changeAuthServerPolicyLifecycle)
+	s.handle(http.MethodGet, "/api/v1/authorizationServers/{}/policies/{}/rules", s.listAuthServerPolicyRules)
+	s.handle(http.MethodPost, "/api/v1/authorizationServers/{}/policies/{}/rules", s.createAuthServerPolicyRule)
+	s.handle(http.MethodGet, "/api/v1/authorizationServers/{}/policies/{}/rules/{}", s.getAuthServerPolicyRule)
+	s.handle(http.MethodPut, "/api/v1/authorizationServers/{}/policies/{}/rules/{}", s.replaceAuthServerPolicyRule)
+	s.handle(http.MethodDelete, "/api/v1/authorizationServers/{}/policies/{}/rules/{}", s.deleteAuthServerPolicyRule)
+	s.handle(http.MethodPost, "/api/v1/authorizationServers/{}/policies/{}/rules/{}/lifecycle/{}", s.changeAuthServerPolicyRuleLifecycle)
+}
+
+// seedAuthServer adds an authorization server with the system scopes and
+// claims of a new one.
+func (s *Server) seedAuthServer(id string, authServer object) object {
+	now := s.now()
+	authServer["id"] = id
+	authServer["issuer"] = s.URL + "/oauth2/" + id
+	authServer["issuerMode"] = "ORG_URL"
+	authServer["status"] = "ACTIVE"
+	authServer["created"] = now
+	authServer["lastUpdated"] = now
+	s.asKeys[id] = []object{s.newKey(id, "ACTIVE"), s.newKey(id, "NEXT")}
+	authServer["credentials"] = object{"signing": object{
+		"rotationMode": "AUTO",
+		"kid":          s.asKeys[id][0]["kid"],
+		"use":          "sig",
+		"lastRotated":  now,
+		"nextRotation": s.nextRotation(),
+	}}
+	authServer["_links"] = s.links("/api/v1/authorizationServers/" + id)
+	s.authServers.add(id, authServer)
+
+	s.scopes[id] = newCollection()
+	for _, name := range []string{"openid", "profile", "email", "address", "phone", "offline_access"} {
+		scopeID := s.newID("scp")
+		s.scopes[id].add(scopeID, object{
+			"id":              scopeID,
+			"name":            name,
+			"displayName":     name,
+			"description":     fmt.Sprintf("Requests access to the %s scope.", name),
+			"system":          true,
+			"default":         false,
+			"consent":         "IMPLICIT",
+			"metadataPublish": "ALL_CLIENTS",
+		})
+	}
+	s.claims[id] = newCollection()
+	for _, name := range []string{"sub", "name", "email", "email_verified", "preferred_username", "given_name", "family_name"} {
+		claimID := s.newID("ocl")
+		s.claims[id].add(claimID, object{
+			"id":                   claimID,
+			"name":                 name,
+			"status":               "ACTIVE",
+			"claimType":            "IDENTITY",
+			"valueType":            "SYSTEM",
+			"value":                "",
+			"system":               true,
+			"alwaysIncludeInToken": true,
+			"conditions":           object{"scopes": []interface{}{"profile"}},
+		})
+	}
+	s.asPolicies[id] = newCollection()
+	return authServer
+}
+
+func (s *Server) listAuthServers(w http.ResponseWriter, r *http.Request, _ []string) {
+	authServers := s.authServers.list(queryPrefix(r, "name"))
+	s.writeList(w, r, authServers, 200, 200)
+}
+
+func (s *Server) createAuthServer(w http.ResponseWriter, r *http.Request, _ []string) {
+	authServer, ok := s.decode(w, r)
+	if !ok || !s.validAuthServer(w, authServer) {
+		return
+	}
+	s.seedAuthServer(s.newID("aus"), authServer)
+	s.writeJSON(w, http.StatusOK, authServer)
+}
+
+func (s *Server) validAuthServer(w http.ResponseWriter, authServer object) bool {
+	if name, _ := authServer["name"].(string); name == "" {
+		s.writeValidationError(w, "name", "The field cannot be left blank")
+		return false
+	}
+	if audiences, _ := authServer["audiences"].([]interface{}); len(audiences) == 0 {
+		s.writeValidationError(w, "audiences", "The field cannot be left blank")
+		return false
+	}
+	return true
+}
+
+func (s *Server) authServer(w http.ResponseWriter, id string) (object, bool) {
+	authServer, ok := s.authServers.get(id)
+	if !ok {
+		s.writeNotFound(w, id, "AuthorizationServer")
+	}
+	return authServer, ok
+}
+
+func (s *Server) getAuthServer(w http.ResponseWriter, r *http.Request, params []string) {
+	authServer, ok := s.authServer(w, params[0])
+	if !ok {
+		return
+	}
+	s.writeJSON(w, http.StatusOK, authServer)
+}
+
+func (s *Server) replaceAuthServer(w http.ResponseWriter, r *http.Request, params []string) {
+	authServer, ok := s.authServer(w, params[0])
+	if !ok {
+		return
+	}
+	body, ok := s.decode(w, r)
+	if !ok || !s.validAuthServer(w, body) {
+		return
+	}
+	signing := authServer["credentials"].(map[string]interface{})["signing"].(map[string]interface{})
+	if mode := stringField(body, "credentials.signing.rotationMode"); mode != "" {
+		signing["rotationMode"] = mode
+	}
+	for _, key := range []string{"id", "issuer", "status", "created", "credentials", "_links"} {
+		body[key] = authServer[key]
+	}
+	if _, ok := body["issuerMode"]; !ok {
+		body["issuerMode"] = authServer["issuerMode"]
+	}
+	body["lastUpdated"] = s.now()
+	s.authServers.add(params[0], body)
+	s.writeJSON(w, http.StatusOK, body)
+}
+
+func (s *Server) deleteAuthServer(w http.ResponseWriter, r *http.Request, params []string) {
+	authServer, ok := s.authServer(w, params[0])
+	if !ok {
+		return
+	}
+	if authServer["status"] == "ACTIVE" {
+		s.writeError(w, http.StatusBadRequest, "E0000001", "Api validation failed: status", "status: The authorization server must be deactivated before it is deleted")
+		return
+	}
+	s.authServers.remove(params[0])
+	for _, children := range []map[string]*collection{s.scopes, s.claims, s.asPolicies} {
+		delete(children, params[0])
+	}
+	delete(s.asKeys, params[0])
+	s.writeNoContent(w)
+}
+
+func (s *Server) changeAuthServerLifecycle(w http.ResponseWriter, r *http.Request, params []string) {
+	authServer, ok := s.authServer(w, params[0])
+	if !ok || !s.changeStatus(w, authServer, params[1]) {
+		return
+	}
+	s.writeNoContent(w)
+}
+
+// newKey returns a signing key of an authorization server with the status.
+func (s *Server) newKey(authServerID, status string) object {
+	kid := s.newID("kid")
+	now := s.now()
+	return object{
+		"kid":         kid,
+		"kty":         "RSA",
+		"alg":         "RS256",
+		"use":         "sig",
+		"e":           "AQAB",
+		"n":           "mock-modulus-" + kid,
+		"x5c":         []interface{}{"mock-certificate-" + kid},
+		"status":      status,
+		"created":     now,
+		"lastUpdated": now,
+		"_links":      s.links(fmt.Sprintf("/api/v1/authorizationServers/%s/credentials/keys/%s", authServerID, kid)),
+	}
+}
+
+// nextRotation returns when keys in AUTO mode are next rotated, which Okta
+// does every 90 days.
+func (s *Server) nextRotation() string {
+	return time.Now().UTC().Add(90 * 24 * time.Hour).Truncate(time.Millisecond).Format(timeFormat)
+}
+
+// listAuthServerKeys lists the ACTIVE, NEXT and EXPIRED signing keys of the
+// authorization server.
+func (s *Server) listAuthServerKeys(w http.ResponseWriter, r *http.Request, params []string) {
+	if _, ok := s.authServer(w, params[0]); !ok {
+		return
+	}
+	s.writeJSON(w, http.StatusOK, s.asKeys[params[0]])
+}
+
+// rotateAuthServerKeys promotes the NEXT key to ACTIVE, expires the ACTIVE
+// key, dropping the previously expired one, and generates a new NEXT key.
+func (s *Server) rotateAuthServerKeys(w http.ResponseWriter, r *http.Request, params []string) {
+	authServer, ok := s.authServer(w, params[0])
+	if !ok {
+		return
+	}
+	body, ok := s.decode(w, r)
+	if !ok {
+		return
+	}
+	if use, _ := body["use"].(string); use != "sig" {
+		s.writeValidationError(w, "use", "The only supported key use is sig")
+		return
+	}
+	now := s.now()
+	var keys []object
+	for _, key := range s.asKeys[params[0]] {
+		switch key["status"] {
+		case "ACTIVE":
+			key["status"] = "EXPIRED"
+			key["expiresAt"] = now
+		case "NEXT":
+			key["status"] = "ACTIVE"
+		default:
+			continue
+		}
+		key["lastUpdated"] = now
+		keys = append(keys, key)
+	}
+	keys = append(keys, s.newKey(params[0], "NEXT"))
+	s.asKeys[params[0]] = keys
+	signing := authServer["credentials"].(map[string]interface{})["signing"].(map[string]interface{})
+	for _, key := range keys {
+		if key["status"] == "ACTIVE" {
+			signing["kid"] = key["kid"]
+		}
+	}
+	signing["lastRotated"] = now
+	signing["nextRotation"] = s.nextRotation()
+	authServer["lastUpdated"] = now
+	s.writeJSON(w, http.StatusOK, keys)
+}
+
+// authServerChildren returns the scopes or claims of an authorization server.
+func (s *Server) authServerChildren(w http.ResponseWriter, id, kind string) (*collection, bool) {
+	if _, ok := s.authServer(w, id); !ok {
+		return nil, false
+	}
+	if kind == "scopes" {
+		return s.scopes[id], true
+	}
+	return s.claims[id], true
+}
+
+func (s *Server) listAuthServerChildren(w http.ResponseWriter, r *http.Request, params []string, kind string) {
+	children, ok := s.authServerChildren(w, params[0], kind)
+	if !ok {
+		return
+	}
+	s.writeList(w, r, children.list(queryPrefix(r, "name")), 200, 200)
+}
+
+func (s *Server) createAuthServerChild(w http.ResponseWriter, r *http.Request, params []string, kind string) {
+	children, ok := s.authServerChildren(w, params[0], kind)
+	if !ok {
+		return
+	}
+	child, ok := s.decode(w, r)
+	if !ok {
+		return
+	}
+	name, _ := child["name"].(string)
+	if name == "" {
+		s.writeValidationError(w, "name", "The field cannot be left blank")
+		return
+	}
+	if kind == "scopes" && len(children.list(func(scope object) bool { return scope["name"] == name })) > 0 {
+		s.writeValidationError(w, "name", "A scope with the name already exists")
+		return
+	}
+	prefix := "scp"
+	if kind == "claims" {
+		prefix = "ocl"
+		if _, ok := child["status"]; !ok {
+			child["status"] = "ACTIVE"
+		}
+	}
+	id := s.newID(prefix)
+	child["id"] = id
+	child["system"] = false
+	children.add(id, child)
+	s.writeJSON(w, http.StatusOK, child)
+}
+
+func (s *Server) getAuthServerChild(w http.ResponseWriter, r *http.Request, params []string, kind string) {
+	children, ok := s.authServerChildren(w, params[0], kind)
+	if !ok {
+		return
+	}
+	child, ok := children.get(params[1])
+	if !ok {
+		s.writeNotFound(w, params[1], kind)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, child)
+}
+
+func (s *Server) replaceAuthServerChild(w http.ResponseWriter, r *http.Request, params []string, kind string) {
+	children, ok := s.authServerChildren(w, params[0], kind)
+	if !ok {
+		return
+	}
+	child, ok := children.get(params[1])
+	if !ok {
+		s.writeNotFound(w, params[1], kind)
+		return
+	}
+	body, ok := s.decode(w, r)
+	if !ok {
+		return
+	}
+	if name, _ := body["name"].(string); name == "" {
+		s.writeValidationError(w, "name", "The field cannot be left blank")
+		return
+	}
+	body["id"] = child["id"]
+	body["system"] = child["system"]
+	children.add(params[1], body)
+	s.writeJSON(w, http.StatusOK, body)
+}
+
+func (s *Server) deleteAuthServerChild(w http.ResponseWriter, r *http.Request, params []string, kind string) {
+	children, ok := s.authServerChildren(w, params[0], kind)
+	if !ok {
+		return
+	}
+	child, ok := children.get(params[1])
+	if !ok {
+		s.writeNotFound(w, params[1], kind)
+		return
+	}
+	if child["system"] == true {
+		s.writeForbidden(w)
+		return
+	}
+	children.remove(params[1])
+	s.writeNoContent(w)
+}
+
+func (s *Server) listAuthServerPolicies(w http.ResponseWriter, r *http.Request, params []string) {
+	if _, ok := s.authServer(w, params[0]); !ok {
+		return
+	}
+	policies := s.asPolicies[params[0]].list(nil)
+	sortByPriority(policies)
+	s.writeList(w, r, policies, 200, 200)
+}
+
+func (s *Server) createAuthServerPolicy(w http.ResponseWriter, r *http.Request, params []string) {
+	if _, ok := s.authServer(w, params[0]); !ok {
+		return
+	}
+	policy, ok := s.decode(w, r)
+	if !ok {
+		return
+	}
+	if name, _ := policy["name"].(string); name == "" {
+		s.writeValidationError(w, "name", "The field cannot be left blank")
+		return
+	}
+	id := s.newID("00p")
+	now := s.now()
+	policy["id"] = id
+	policy["type"] = "OAUTH_AUTHORIZATION_POLICY"
+	policy["system"] = false
+	policy["created"] = now
+	policy["lastUpdated"] = now
+	policy["_links"] = s.links(fmt.Sprintf("/api/v1/authorizationServers/%s/policies/%s", params[0], id))
+	if _, ok := policy["status"]; !ok {
+		policy["status"] = "ACTIVE"
+	}
+	policies := s.asPolicies[params[0]]
+	policies.add(id, policy)
+	s.asRules[id] = newCollection()
+	prioritize(policies.list(nil), policy)
+	s.writeJSON(w, http.StatusOK, policy)
+}
+
+func (s *Server) authServerPolicy(w http.ResponseWriter, params []string) (object, bool) {
+	if _, ok := s.authServer(w, params[0]); !ok {
+		return nil, false
+	}
+	policy, ok := s.asPolicies[params[0]].get(params[1])
+	if !ok {
+		s.writeNotFound(w, params[1], "AuthorizationServerPolicy")
+	}
+	return policy, ok
+}
+
+func (s *Server) getAuthServerPolicy(w http.ResponseWriter, r *http.Request, params []string) {
+	policy, ok := s.authServerPolicy(w, params)
+	if !ok {
+		return
+	}
+	s.writeJSON(w, http.StatusOK, policy)
+}
+
+func (s *Server) replaceAuthServerPolicy(w http.ResponseWriter, r *http.Request, params []string) {
+	policy, ok := s.authServerPolicy(w, params)
+	if !ok {
+		return
+	}
+	body, ok := s.decode(w, r)
+	if !ok {
+		return
+	}
+	if name, _ := body["name"].(string); name == "" {
+		s.writeValidationError(w, "name", "The field cannot be left blank")
+		return
+	}
+	for _, key := range []string{"id", "type", "system", "created", "_links"} {
+		body[key] = policy[key]
+	}
+	if _, ok := body["status"]; !ok {
+		body["status"] = policy["status"]
+	}
+	body["lastUpdated"] = s.now()
+	policies := s.asPolicies[params[0]]
+	policies.add(params[1], body)
+	prioritize(policies.list(nil), body)
+	s.writeJSON(w, http.StatusOK, body)
+}
+
+func (s *Server) deleteAuthServerPolicy(w http.ResponseWriter, r *http.Request, params []string) {
+	if _, ok := s.authServerPolicy(w, params); !ok {
+		return
+	}
+	policies := s.asPolicies[params[0]]
+	policies.remove(params[1])
+	delete(s.asRules, params[1])
+	prioritize(policies.list(nil), nil)
+	s.writeNoContent(w)
+}
+
+func (s *Server) changeAuthServerPolicyLifecycle(w http.ResponseWriter, r *http.Request, params []string) {
+	policy, ok := s.authServerPolicy(w, params)
+	if !ok || !s.changeStatus(w, policy, params[2]) {
+		return
+	}
+	s.writeNoContent(w)
+}
+
+func (s *Server) listAuthServerPolicyRules(w http.ResponseWriter, r *http.Request, params []string) {
+	if _, ok := s.authServerPolicy(w, params); !ok {
+		return
+	}
+	rules := s.asRules[params[1]].list(nil)
+	sortByPriority(rules)
+	s.writeList(w, r, rules, 200, 200)
+}
+
+func (s *Server) createAuthServerPolicyRule(w http.ResponseWriter, r *http.Request, params []string) {
+	if _, ok := s.authServerPolicy(w, params); !ok {
+		return
+	}
+	rule, ok := s.decode(w, r)
+	if !ok {
+		return
+	}
+	if name, _ := rule["name"].(string); name == "" {
+		s.writeValidationError(w, "name", "The field cannot be left blank")
+		return
+	}
+	id := s.newID("0pr")
+	now := s.now()
+	rule["id"] = id
+	rule["type"] = "RESOURCE_ACCESS"
+	rule["system"] = false
+	rule["created"] = now
+	rule["lastUpdated"] = now
+	rule["_links"] = s.links(fmt.Sprintf("/api/v1/authorizationServers/%s/policies/%s/rules/%s", params[0], params[1], id))
+	if _, ok := rule["status"]; !ok {
+		rule["status"] = "ACTIVE"
+	}
+	rules := s.asRules[params[1]]
+	rules.add(id, rule)
+	prioritize(rules.list(nil), rule)
+	s.writeJSON(w, http.StatusOK, rule)
+}
+
+func (s *Server) authServerPolicyRule(w http.ResponseWriter, params []string) (object, bool) {
+	if _, ok := s.authServerPolicy(w, params); !ok {
+		return nil, false
+	}
+	rule, ok := s.asRules[params[1]].get(params[2])
+	if !ok {
+		s.writeNotFound(w, params[2], "AuthorizationServerPolicyRule")
+	}
+	return rule, ok
+}
+
+func (s *Server) getAuthServerPolicyRule(w http.ResponseWriter, r *http.Request, params []string) {
+	rule, ok := s.authServerPolicyRule(w, params)
+	if !ok {
+		return
+	}
+	s.writeJSON(w, http.StatusOK, rule)
+}
+
+func (s *Server) replaceAuthServerPolicyRule(w http.ResponseWriter, r *http.Request, params []string) {
+	rule, ok := s.authServerPolicyRule(w, params)
+	if !ok {
+		return
+	}
+	body, ok := s.decode(w, r)
+	if !ok {
+		return
+	}
+	if name, _ := body["name"].(string); name == "" {
+		s.writeValidationError(w, "name", "The field cannot be left blank")
+		return
+	}
+	for _, key := range []string{"id", "type", "system", "created", "_links"} {
+		body[key] = rule[key]
+	}
+	if _, ok := body["status"]; !ok {
+		body["status"] = rule["status"]
+	}
+	body["lastUpdated"] = s.now()
+	rules := s.asRules[params[1]]
+	rules.add(params[2], body)
+	prioritize(rules.list(nil), body)
+	s.writeJSON(w, http.StatusOK, body)
+}
+
+func (s *Server) deleteAuthServerPolicyRule(w http.ResponseWriter, r *http.Request, params []string) {
+	if _, ok := s.authServerPolicyRule(w, params); !ok {
+		return
+	}
+	rules := s.asRules[params[1]]
+	rules.remove(params[2])
+	prioritize(rules.list(nil), nil)
+	s.writeNoContent(w)
+}
+
+func (s *Server) changeAuthServerPolicyRuleLifecycle(w http.ResponseWriter, r *http.Request, params []string) {
+	rule, ok := s.authServerPolicyRule(w, params)
+	if !ok || !s.changeStatus(w, rule, params[3]) {
+		return
+	}
+	s.writeNoContent(w)
+}
@@ -0,0 +1,259 @@
+package mockokta
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// hookClient calls the endpoints of the event and inline hooks.
+var hookClient = &http.Client{Timeout: 5 * time.Second}
+
+func (s *Server) eventHookRoutes() {
+	s.handle(http.MethodGet, "/api/v1/eventHooks", s.listEventHooks)
+	s.handle(http.MethodPost, "/api/v1/eventHooks", s.createEventHook)
+	s.handle(http.MethodGet, "/api/v1/eventHooks/{}", s.getEventHook)
+	s.handle(http.MethodPut, "/api/v1/eventHooks/{}", s.replaceEventHook)
+	s.handle(http.MethodDelete, "/api/v1/eventHooks/{}", s.deleteEventHook)
+	s.handle(http.MethodPost, "/api/v1/eventHooks/{}/lifecycle/{}", s.changeEventHookLifecycle)
+}
+
+func (s *Server) listEventHooks(w http.ResponseWriter, r *http.Request, _ []string) {
+	s.writeList(w, r, s.eventHooks.list(nil), 200, 200)
+}
+
+// createEventHook creates an ACTIVE and UNVERIFIED event hook. Unlike Okta,
+// the URI of the channel may use http, so that hooks can target a local
+// endpoint like hookreceiver.
+func (s *Server) createEventHook(w http.ResponseWriter, r *http.Request, _ []string) {
+	hook, ok := s.decode(w, r)
+	if !ok || !s.validEventHook(w, hook) {
+		return
+	}
+	id := s.newID("who")
+	now := s.now()
+	hook["id"] = id
+	hook["status"] = "ACTIVE"
+	hook["verificationStatus"] = "UNVERIFIED"
+	hook["created"] = now
+	hook["createdBy"] = s.adminID
+	hook["lastUpdated"] = now
+	hook["_links"] = s.links("/api/v1/eventHooks/" + id)
+	s.keepHookSecret(id, hook)
+	s.eventHooks.add(id, hook)
+	s.writeJSON(w, http.StatusOK, hook)
+}
+
+func (s *Server) validEventHook(w http.ResponseWriter, hook object) bool {
+	if name, _ := hook["name"].(string); name == "" {
+		s.writeValidationError(w, "name", "The field cannot be left blank")
+		return false
+	}
+	items, _ := field(hook, "events.items")
+	if values, _ := items.([]interface{}); len(values) == 0 {
+		s.writeValidationError(w, "events", "The field cannot be left blank")
+		return false
+	}
+	if stringField(hook, "channel.config.uri") == "" {
+		s.writeValidationError(w, "channel.config.uri", "The field cannot be left blank")
+		return false
+	}
+	return true
+}
+
+// keepHookSecret moves the secret of the auth scheme of the event or inline
+// hook out of it, Okta never returns it.
+func (s *Server) keepHookSecret(id string, hook object) {
+	authScheme, _ := field(hook, "channel.config.authScheme")
+	if auth, ok := authScheme.(map[string]interface{}); ok {
+		s.hookSecrets[id], _ = auth["value"].(string)
+		delete(auth, "value")
+		return
+	}
+	delete(s.hookSecrets, id)
+}
+
+func (s *Server) getEventHook(w http.ResponseWriter, r *http.Request, params []string) {
+	hook, ok := s.eventHooks.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "EventHook")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, hook)
+}
+
+// replaceEventHook replaces the name, events and channel of a hook, a new
+// channel has to be verified again.
+func (s *Server) replaceEventHook(w http.ResponseWriter, r *http.Request, params []string) {
+	hook, ok := s.eventHooks.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "EventHook")
+		return
+	}
+	body, ok := s.decode(w, r)
+	if !ok || !s.validEventHook(w, body) {
+		return
+	}
+	if stringField(body, "channel.config.uri") != stringField(hook, "channel.config.uri") {
+		hook["verificationStatus"] = "UNVERIFIED"
+	}
+	s.keepHookSecret(params[0], body)
+	hook["name"] = body["name"]
+	hook["events"] = body["events"]
+	hook["channel"] = body["channel"]
+	hook["lastUpdated"] = s.now()
+	s.writeJSON(w, http.StatusOK, hook)
+}
+
+// deleteEventHook deletes a hook, which must be deactivated first.
+func (s *Server) deleteEventHook(w http.ResponseWriter, r *http.Request, params []string) {
+	hook, ok := s.eventHooks.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "EventHook")
+		return
+	}
+	if hook["status"] == "ACTIVE" {
+		s.writeError(w, http.StatusBadRequest, "E0000001", "Api validation failed: status", "status: The event hook must be deactivated before it is deleted.")
+		return
+	}
+	s.eventHooks.remove(params[0])
+	delete(s.hookSecrets, params[0])
+	s.writeNoContent(w)
+}
+
+func (s *Server) changeEventHookLifecycle(w http.ResponseWriter, r *http.Request, params []string) {
+	hook, ok := s.eventHooks.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "EventHook")
+		return
+	}
+	if params[1] == "verify" {
+		if !s.verifyEventHook(w, hook) {
+			return
+		}
+	} else if !s.changeStatus(w, hook, params[1]) {
+		return
+	}
+	s.writeJSON(w, http.StatusOK, hook)
+}
+
+// verifyEventHook sends the one-time verification request to the endpoint
+// of the hook, which must echo the challenge back.
+func (s *Server) verifyEventHook(w http.ResponseWriter, hook object) bool {
+	challenge := s.newID("chl")
+	req, err := s.hookRequest(hook, http.MethodGet, nil)
+	if err != nil {
+		s.writeValidationError(w, "channel.config.uri", err.Error())
+		return false
+	}
+	req.Header.Set("X-Okta-Verification-Challenge", challenge)
+	var verification struct {
+		Verification string `json:"verification"`
+	}
+	res, err := hookClient.Do(req)
+	if err == nil {
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			err = fmt.Errorf("the endpoint responded with %s", res.Status)
+		} else if err = json.NewDecoder(res.Body).Decode(&verification); err == nil && verification.Verification != challenge {
+			err = fmt.Errorf("the endpoint didn't echo the verification challenge back")
+		}
+	}
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "E0000001", "Api validation failed: verification", "verification: Event hook verification failed: "+err.Error())
+		return false
+	}
+	hook["verificationStatus"] = "VERIFIED"
+	hook["lastUpdated"] = s.now()
+	return true
+}
+
+// hookRequest returns a request to the endpoint of the event or inline hook
+// carrying its auth header and custom headers.
+func (s *Server) hookRequest(hook object, method string, body []byte) (*http.Request, error) {
+	req, err := http.NewRequest(method, stringField(hook, "channel.config.uri"), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	headers, _ := field(hook, "channel.config.headers")
+	values, _ := headers.([]interface{})
+	for _, v := range values {
+		if header, ok := v.(map[string]interface{}); ok {
+			req.Header.Set(fmt.Sprint(header["key"]), fmt.Sprint(header["value"]))
+		}
+	}
+	if key := stringField(hook, "channel.config.authScheme.key"); key != "" {
+		req.Header.Set(key, s.hookSecrets[hook["id"].(string)])
+	}
+	return req, nil
+}
+
+// publish delivers an event about the targets to the ACTIVE and VERIFIED
+// hooks subscribed to its type. Like Okta, failed deliveries are dropped.
+func (s *Server) publish(eventType, displayMessage string, targets ...object) {
+	for _, hook := range s.eventHooks.list(nil) {
+		if hook["status"] != "ACTIVE" || hook["verificationStatus"] != "VERIFIED" || !subscribed(hook, eventType) {
+			continue
+		}
+		now := s.now()
+		payload, _ := json.Marshal(object{
+			"eventType":          "com.okta.event_hook",
+			"eventTypeVersion":   "1.0",
+			"cloudEventsVersion": "0.1",
+			"source":             s.URL + "/api/v1/eventHooks/" + hook["id"].(string),
+			"eventId":            s.newID("evt"),
+			"eventTime":          now,
+			"contentType":        "application/json",
+			"data": object{"events": []interface{}{object{
+				"uuid":           s.newID("evt"),
+				"published":      now,
+				"eventType":      eventType,
+				"version":        "0",
+				"displayMessage": displayMessage,
+				"severity":       "INFO",
+				"actor":          object{"id": s.adminID, "type": "User", "alternateId": "admin@example.com", "displayName": "Org Admin"},
+				"outcome":        object{"result": "SUCCESS"},
+				"target":         targets,
+			}}},
+		})
+		req, err := s.hookRequest(hook, http.MethodPost, payload)
+		if err != nil {
+			continue
+		}
+		if res, err := hookClient.Do(req); err == nil {
+			res.Body.Close()
+		}
+	}
+}
+
+func subscribed(hook object, eventType string) bool {
+	items, _ := field(hook, "events.items")
+	values, _ := items.([]interface{})
+	for _, v := range values {
+		if v == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func userTarget(user object) object {
+	return object{
+		"id":          user["id"],
+		"type":        "User",
+		"alternateId": stringField(user, "profile.login"),
+		"displayName": stringField(user, "profile.firstName") + " " + stringField(user, "profile.lastName"),
+	}
+}
+
+func groupTarget(group object) object {
+	return object{
+		"id":          group["id"],
+		"type":        "UserGroup",
+		"alternateId": "unknown",
+		"displayName": stringField(group, "profile.name"),
+	}
+}
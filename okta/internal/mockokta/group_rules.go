@@ -0,0 +1,121 @@
+package mockokta
+
+import (
+	"net/http"
+)
+
+// groupRuleRoutes are registered before the group routes, GET
+// /api/v1/groups/rules would otherwise be served as the group "rules".
+func (s *Server) groupRuleRoutes() {
+	s.handle(http.MethodGet, "/api/v1/groups/rules", s.listGroupRules)
+	s.handle(http.MethodPost, "/api/v1/groups/rules", s.createGroupRule)
+	s.handle(http.MethodGet, "/api/v1/groups/rules/{}", s.getGroupRule)
+	s.handle(http.MethodPut, "/api/v1/groups/rules/{}", s.replaceGroupRule)
+	s.handle(http.MethodDelete, "/api/v1/groups/rules/{}", s.deleteGroupRule)
+	s.handle(http.MethodPost, "/api/v1/groups/rules/{}/lifecycle/{}", s.changeGroupRuleLifecycle)
+}
+
+func (s *Server) listGroupRules(w http.ResponseWriter, r *http.Request, _ []string) {
+	s.writeList(w, r, s.groupRules.list(nil), 50, 200)
+}
+
+// createGroupRule creates an INACTIVE group rule, the rule is activated with
+// its lifecycle operation. Rules don't assign users in the fake org.
+func (s *Server) createGroupRule(w http.ResponseWriter, r *http.Request, _ []string) {
+	rule, ok := s.decode(w, r)
+	if !ok || !s.validGroupRule(w, rule) {
+		return
+	}
+	id := s.newID("0pr")
+	now := s.now()
+	rule["id"] = id
+	rule["type"] = "group_rule"
+	rule["status"] = "INACTIVE"
+	rule["created"] = now
+	rule["lastUpdated"] = now
+	s.groupRules.add(id, rule)
+	s.writeJSON(w, http.StatusOK, rule)
+}
+
+// validGroupRule checks the rule has a name and an expression and that the
+// groups it assigns exist.
+func (s *Server) validGroupRule(w http.ResponseWriter, rule object) bool {
+	for _, name := range []string{"name", "conditions.expression.value"} {
+		if stringField(rule, name) == "" {
+			s.writeValidationError(w, name, "The field cannot be left blank")
+			return false
+		}
+	}
+	groupIDs, _ := field(rule, "actions.assignUserToGroups.groupIds")
+	ids, _ := groupIDs.([]interface{})
+	if len(ids) == 0 {
+		s.writeValidationError(w, "actions.assignUserToGroups.groupIds", "The field cannot be left blank")
+		return false
+	}
+	for _, id := range ids {
+		if _, ok := s.groups.get(id.(string)); !ok {
+			s.writeValidationError(w, "actions.assignUserToGroups.groupIds", "Invalid group id: "+id.(string))
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) getGroupRule(w http.ResponseWriter, r *http.Request, params []string) {
+	rule, ok := s.groupRules.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "GroupRule")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, rule)
+}
+
+// replaceGroupRule replaces the name, conditions and actions of a rule, which
+// must be deactivated first.
+func (s *Server) replaceGroupRule(w http.ResponseWriter, r *http.Request, params []string) {
+	rule, ok := s.groupRules.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "GroupRule")
+		return
+	}
+	if rule["status"] == "ACTIVE" {
+		s.writeError(w, http.StatusBadRequest, "E0000001", "Api validation failed: status", "status: Cannot update rule in ACTIVE state.")
+		return
+	}
+	body, ok := s.decode(w, r)
+	if !ok || !s.validGroupRule(w, body) {
+		return
+	}
+	rule["name"] = body["name"]
+	rule["conditions"] = body["conditions"]
+	rule["actions"] = body["actions"]
+	rule["lastUpdated"] = s.now()
+	s.writeJSON(w, http.StatusOK, rule)
+}
+
+// deleteGroupRule deletes a rule, which must be deactivated first.
+func (s *Server) deleteGroupRule(w http.ResponseWriter, r *http.Request, params []string) {
+	rule, ok := s.groupRules.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "GroupRule")
+		return
+	}
+	if rule["status"] == "ACTIVE" {
+		s.writeError(w, http.StatusBadRequest, "E0000001", "Api validation failed: status", "status: Cannot delete rule in ACTIVE state.")
+		return
+	}
+	s.groupRules.remove(params[0])
+	s.writeNoContent(w)
+}
+
+func (s *Server) changeGroupRuleLifecycle(w http.ResponseWriter, r *http.Request, params []string) {
+	rule, ok := s.groupRules.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "GroupRule")
+		return
+	}
+	if !s.changeStatus(w, rule, params[1]) {
+		return
+	}
+	s.writeNoContent(w)
+}
@@ -0,0 +1,185 @@
+package mockokta
+
+import (
+	"net/http"
+	"strings"
+)
+
+func (s *Server) groupRoutes() {
+	s.handle(http.MethodGet, "/api/v1/groups", s.listGroups)
+	s.handle(http.MethodPost, "/api/v1/groups", s.createGroup)
+	s.handle(http.MethodGet, "/api/v1/groups/{}", s.getGroup)
+	s.handle(http.MethodPut, "/api/v1/groups/{}", s.replaceGroup)
+	s.handle(http.MethodDelete, "/api/v1/groups/{}", s.deleteGroup)
+	s.handle(http.MethodGet, "/api/v1/groups/{}/users", s.listGroupUsers)
+	s.handle(http.MethodPut, "/api/v1/groups/{}/users/{}", s.addGroupUser)
+	s.handle(http.MethodDelete, "/api/v1/groups/{}/users/{}", s.removeGroupUser)
+	s.handle(http.MethodGet, "/api/v1/groups/{}/roles", s.listGroupRoles)
+}
+
+func (s *Server) listGroups(w http.ResponseWriter, r *http.Request, _ []string) {
+	groups := s.groups.list(allOf(listFilter(r, objectResolver), queryPrefix(r, "profile.name")))
+	s.writeList(w, r, groups, 10000, 10000)
+}
+
+func (s *Server) createGroup(w http.ResponseWriter, r *http.Request, _ []string) {
+	group, ok := s.decode(w, r)
+	if !ok {
+		return
+	}
+	name := stringField(group, "profile.name")
+	if name == "" {
+		s.writeValidationError(w, "name", "The field cannot be left blank")
+		return
+	}
+	if s.groupNameTaken(name, "") {
+		s.writeValidationError(w, "name", "An object with this field already exists in the current organization")
+		return
+	}
+	id := s.newID("00g")
+	now := s.now()
+	group["id"] = id
+	group["type"] = "OKTA_GROUP"
+	group["created"] = now
+	group["lastUpdated"] = now
+	group["lastMembershipUpdated"] = now
+	group["objectClass"] = []interface{}{"okta:user_group"}
+	group["_links"] = s.links("/api/v1/groups/" + id)
+	s.groups.add(id, group)
+	s.writeJSON(w, http.StatusOK, group)
+}
+
+func (s *Server) groupNameTaken(name, exceptID string) bool {
+	for _, group := range s.groups.list(nil) {
+		if group["id"] != exceptID && group["type"] == "OKTA_GROUP" && strings.EqualFold(stringField(group, "profile.name"), name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) getGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	group, ok := s.groups.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "UserGroup")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, group)
+}
+
+func (s *Server) replaceGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	group, ok := s.groups.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "UserGroup")
+		return
+	}
+	if group["type"] != "OKTA_GROUP" {
+		s.writeForbidden(w)
+		return
+	}
+	body, ok := s.decode(w, r)
+	if !ok {
+		return
+	}
+	name := stringField(body, "profile.name")
+	if name == "" {
+		s.writeValidationError(w, "name", "The field cannot be left blank")
+		return
+	}
+	if s.groupNameTaken(name, params[0]) {
+		s.writeValidationError(w, "name", "An object with this field already exists in the current organization")
+		return
+	}
+	group["profile"] = body["profile"]
+	group["lastUpdated"] = s.now()
+	s.writeJSON(w, http.StatusOK, group)
+}
+
+func (s *Server) deleteGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	group, ok := s.groups.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "UserGroup")
+		return
+	}
+	if group["type"] != "OKTA_GROUP" {
+		s.writeForbidden(w)
+		return
+	}
+	s.groups.remove(params[0])
+	delete(s.groupMembers, params[0])
+	for _, appGroups := range s.appGroups {
+		appGroups.remove(params[0])
+	}
+	s.writeNoContent(w)
+}
+
+// listGroupUsers lists the members of a group, every user not deprovisioned
+// is a member of Everyone.
+func (s *Server) listGroupUsers(w http.ResponseWriter, r *http.Request, params []string) {
+	if _, ok := s.groups.get(params[0]); !ok {
+		s.writeNotFound(w, params[0], "UserGroup")
+		return
+	}
+	users := s.users.list(func(user object) bool {
+		if params[0] == s.everyoneID {
+			return user["status"] != "DEPROVISIONED"
+		}
+		return contains(s.groupMembers[params[0]], user["id"].(string))
+	})
+	s.writeList(w, r, users, 1000, 1000)
+}
+
+func (s *Server) addGroupUser(w http.ResponseWriter, r *http.Request, params []string) {
+	group, user, ok := s.groupAndUser(w, params)
+	if !ok {
+		return
+	}
+	if group["type"] != "OKTA_GROUP" {
+		s.writeForbidden(w)
+		return
+	}
+	userID := user["id"].(string)
+	if !contains(s.groupMembers[params[0]], userID) {
+		s.groupMembers[params[0]] = append(s.groupMembers[params[0]], userID)
+		group["lastMembershipUpdated"] = s.now()
+		s.publish("group.user_membership.add", "Add user to group membership", userTarget(user), groupTarget(group))
+	}
+	s.writeNoContent(w)
+}
+
+func (s *Server) removeGroupUser(w http.ResponseWriter, r *http.Request, params []string) {
+	group, user, ok := s.groupAndUser(w, params)
+	if !ok {
+		return
+	}
+	if group["type"] != "OKTA_GROUP" {
+		s.writeForbidden(w)
+		return
+	}
+	s.groupMembers[params[0]] = without(s.groupMembers[params[0]], user["id"].(string))
+	group["lastMembershipUpdated"] = s.now()
+	s.publish("group.user_membership.remove", "Remove user from group membership", userTarget(user), groupTarget(group))
+	s.writeNoContent(w)
+}
+
+func (s *Server) groupAndUser(w http.ResponseWriter, params []string) (object, object, bool) {
+	group, ok := s.groups.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "UserGroup")
+		return nil, nil, false
+	}
+	user, ok := s.findUser(params[1])
+	if !ok {
+		s.writeNotFound(w, params[1], "User")
+		return nil, nil, false
+	}
+	return group, user, true
+}
+
+func (s *Server) listGroupRoles(w http.ResponseWriter, r *http.Request, params []string) {
+	if _, ok := s.groups.get(params[0]); !ok {
+		s.writeNotFound(w, params[0], "UserGroup")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, []object{})
+}
@@ -0,0 +1,155 @@
+package mockokta
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+func (s *Server) inlineHookRoutes() {
+	s.handle(http.MethodGet, "/api/v1/inlineHooks", s.listInlineHooks)
+	s.handle(http.MethodPost, "/api/v1/inlineHooks", s.createInlineHook)
+	s.handle(http.MethodGet, "/api/v1/inlineHooks/{}", s.getInlineHook)
+	s.handle(http.MethodPut, "/api/v1/inlineHooks/{}", s.replaceInlineHook)
+	s.handle(http.MethodDelete, "/api/v1/inlineHooks/{}", s.deleteInlineHook)
+	s.handle(http.MethodPost, "/api/v1/inlineHooks/{}/execute", s.executeInlineHook)
+	s.handle(http.MethodPost, "/api/v1/inlineHooks/{}/lifecycle/{}", s.changeInlineHookLifecycle)
+}
+
+func (s *Server) listInlineHooks(w http.ResponseWriter, r *http.Request, _ []string) {
+	hooks := s.inlineHooks.list(func(hook object) bool {
+		t := r.URL.Query().Get("type")
+		return t == "" || hook["type"] == t
+	})
+	s.writeList(w, r, hooks, 200, 200)
+}
+
+// createInlineHook creates an ACTIVE inline hook. Like event hooks, the URI
+// of the channel may use http.
+func (s *Server) createInlineHook(w http.ResponseWriter, r *http.Request, _ []string) {
+	hook, ok := s.decode(w, r)
+	if !ok || !s.validInlineHook(w, hook) {
+		return
+	}
+	id := s.newID("cal")
+	now := s.now()
+	hook["id"] = id
+	hook["status"] = "ACTIVE"
+	hook["created"] = now
+	hook["lastUpdated"] = now
+	hook["_links"] = s.links("/api/v1/inlineHooks/" + id)
+	s.keepHookSecret(id, hook)
+	s.inlineHooks.add(id, hook)
+	s.writeJSON(w, http.StatusOK, hook)
+}
+
+func (s *Server) validInlineHook(w http.ResponseWriter, hook object) bool {
+	for _, name := range []string{"name", "type", "version", "channel.config.uri"} {
+		if stringField(hook, name) == "" {
+			s.writeValidationError(w, name, "The field cannot be left blank")
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) getInlineHook(w http.ResponseWriter, r *http.Request, params []string) {
+	hook, ok := s.inlineHooks.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "InlineHook")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, hook)
+}
+
+// replaceInlineHook replaces the name, version and channel of a hook, its
+// type can't change.
+func (s *Server) replaceInlineHook(w http.ResponseWriter, r *http.Request, params []string) {
+	hook, ok := s.inlineHooks.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "InlineHook")
+		return
+	}
+	body, ok := s.decode(w, r)
+	if !ok || !s.validInlineHook(w, body) {
+		return
+	}
+	if body["type"] != hook["type"] {
+		s.writeValidationError(w, "type", "The type of an inline hook cannot be changed")
+		return
+	}
+	s.keepHookSecret(params[0], body)
+	hook["name"] = body["name"]
+	hook["version"] = body["version"]
+	hook["channel"] = body["channel"]
+	hook["lastUpdated"] = s.now()
+	s.writeJSON(w, http.StatusOK, hook)
+}
+
+// deleteInlineHook deletes a hook, which must be deactivated first.
+func (s *Server) deleteInlineHook(w http.ResponseWriter, r *http.Request, params []string) {
+	hook, ok := s.inlineHooks.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "InlineHook")
+		return
+	}
+	if hook["status"] == "ACTIVE" {
+		s.writeError(w, http.StatusBadRequest, "E0000001", "Api validation failed: status", "status: The inline hook must be deactivated before it is deleted.")
+		return
+	}
+	s.inlineHooks.remove(params[0])
+	delete(s.hookSecrets, params[0])
+	s.writeNoContent(w)
+}
+
+func (s *Server) changeInlineHookLifecycle(w http.ResponseWriter, r *http.Request, params []string) {
+	hook, ok := s.inlineHooks.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "InlineHook")
+		return
+	}
+	if !s.changeStatus(w, hook, params[1]) {
+		return
+	}
+	s.writeJSON(w, http.StatusOK, hook)
+}
+
+// executeInlineHook sends the payload of the request to the endpoint of the
+// hook and responds with the response of the endpoint.
+func (s *Server) executeInlineHook(w http.ResponseWriter, r *http.Request, params []string) {
+	hook, ok := s.inlineHooks.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "InlineHook")
+		return
+	}
+	payload, err := io.ReadAll(r.Body)
+	if err != nil || !json.Valid(payload) {
+		s.writeError(w, http.StatusBadRequest, "E0000003", "The request body was not well-formed.")
+		return
+	}
+	method := stringField(hook, "channel.config.method")
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := s.hookRequest(hook, method, payload)
+	if err != nil {
+		s.writeValidationError(w, "channel.config.uri", err.Error())
+		return
+	}
+	var response interface{}
+	res, err := hookClient.Do(req)
+	if err == nil {
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			err = fmt.Errorf("the endpoint responded with %s", res.Status)
+		} else {
+			err = json.NewDecoder(res.Body).Decode(&response)
+		}
+	}
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "E0000001", "Api validation failed: execute", "execute: Inline hook execution failed: "+err.Error())
+		return
+	}
+	s.writeJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,328 @@
+package mockokta
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ruleTypes are the types of the rules of each policy type.
+var ruleTypes = map[string]string{
+	"OKTA_SIGN_ON":       "SIGN_ON",
+	"PASSWORD":           "PASSWORD",
+	"MFA_ENROLL":         "MFA_ENROLL",
+	"IDP_DISCOVERY":      "IDP_DISCOVERY",
+	"ACCESS_POLICY":      "ACCESS_POLICY",
+	"PROFILE_ENROLLMENT": "PROFILE_ENROLLMENT",
+}
+
+func (s *Server) policyRoutes() {
+	s.handle(http.MethodGet, "/api/v1/policies", s.listPolicies)
+	s.handle(http.MethodPost, "/api/v1/policies", s.createPolicy)
+	s.handle(http.MethodGet, "/api/v1/policies/{}", s.getPolicy)
+	s.handle(http.MethodPut, "/api/v1/policies/{}", s.replacePolicy)
+	s.handle(http.MethodDelete, "/api/v1/policies/{}", s.deletePolicy)
+	s.handle(http.MethodPost, "/api/v1/policies/{}/lifecycle/{}", s.changePolicyLifecycle)
+	s.handle(http.MethodGet, "/api/v1/policies/{}/rules", s.listPolicyRules)
+	s.handle(http.MethodPost, "/api/v1/policies/{}/rules", s.createPolicyRule)
+	s.handle(http.MethodGet, "/api/v1/policies/{}/rules/{}", s.getPolicyRule)
+	s.handle(http.MethodPut, "/api/v1/policies/{}/rules/{}", s.replacePolicyRule)
+	s.handle(http.MethodDelete, "/api/v1/policies/{}/rules/{}", s.deletePolicyRule)
+	s.handle(http.MethodPost, "/api/v1/policies/{}/rules/{}/lifecycle/{}", s.changePolicyRuleLifecycle)
+}
+
+// seedPolicies adds the default policy of each policy type with its default
+// rule, the way they are in a new org.
+func (s *Server) seedPolicies() {
+	for _, policyType := range []string{"OKTA_SIGN_ON", "PASSWORD", "MFA_ENROLL", "IDP_DISCOVERY", "ACCESS_POLICY", "PROFILE_ENROLLMENT"} {
+		name := "Default Policy"
+		if policyType == "IDP_DISCOVERY" {
+			name = "Idp Discovery Policy"
+		}
+		id := s.newID("00p")
+		now := s.now()
+		s.policies.add(id, object{
+			"id":          id,
+			"type":        policyType,
+			"name":        name,
+			"description": "The default policy applies in all situations if no other policy applies.",
+			"status":      "ACTIVE",
+			"priority":    1,
+			"system":      true,
+			"conditions":  object{},
+			"created":     now,
+			"lastUpdated": now,
+			"_links":      s.links("/api/v1/policies/" + id),
+		})
+		ruleID := s.newID("0pr")
+		s.policyRules[id] = newCollection()
+		s.policyRules[id].add(ruleID, object{
+			"id":          ruleID,
+			"type":        ruleTypes[policyType],
+			"name":        "Default Rule",
+			"status":      "ACTIVE",
+			"priority":    1,
+			"system":      true,
+			"conditions":  object{},
+			"actions":     object{},
+			"created":     now,
+			"lastUpdated": now,
+			"_links":      s.links(fmt.Sprintf("/api/v1/policies/%s/rules/%s", id, ruleID)),
+		})
+	}
+}
+
+// listPolicies lists the policies of the type query parameter by priority.
+func (s *Server) listPolicies(w http.ResponseWriter, r *http.Request, _ []string) {
+	policyType := r.URL.Query().Get("type")
+	if policyType == "" {
+		s.writeValidationError(w, "type", "The field cannot be left blank")
+		return
+	}
+	status := r.URL.Query().Get("status")
+	policies := s.policies.list(func(policy object) bool {
+		return policy["type"] == policyType && (status == "" || policy["status"] == status)
+	})
+	sortByPriority(policies)
+	s.writeList(w, r, policies, 200, 200)
+}
+
+func (s *Server) createPolicy(w http.ResponseWriter, r *http.Request, _ []string) {
+	policy, ok := s.decode(w, r)
+	if !ok {
+		return
+	}
+	policyType, _ := policy["type"].(string)
+	if _, ok := ruleTypes[policyType]; !ok {
+		s.writeValidationError(w, "type", fmt.Sprintf("Invalid policy type %q", policyType))
+		return
+	}
+	if name, _ := policy["name"].(string); name == "" {
+		s.writeValidationError(w, "name", "The field cannot be left blank")
+		return
+	}
+	id := s.newID("00p")
+	now := s.now()
+	policy["id"] = id
+	policy["system"] = false
+	policy["created"] = now
+	policy["lastUpdated"] = now
+	policy["_links"] = s.links("/api/v1/policies/" + id)
+	if _, ok := policy["status"]; !ok {
+		policy["status"] = "ACTIVE"
+	}
+	if !queryBool(r, "activate", true) {
+		policy["status"] = "INACTIVE"
+	}
+	s.policies.add(id, policy)
+	s.policyRules[id] = newCollection()
+	prioritize(s.policiesOfType(policyType), policy)
+	s.writeJSON(w, http.StatusOK, policy)
+}
+
+func (s *Server) policiesOfType(policyType interface{}) []object {
+	return s.policies.list(func(policy object) bool {
+		return policy["type"] == policyType
+	})
+}
+
+func (s *Server) getPolicy(w http.ResponseWriter, r *http.Request, params []string) {
+	policy, ok := s.policies.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "Policy")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, policy)
+}
+
+// replacePolicy replaces a policy, keeping its type, and moves it to its new
+// priority.
+func (s *Server) replacePolicy(w http.ResponseWriter, r *http.Request, params []string) {
+	policy, ok := s.policies.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "Policy")
+		return
+	}
+	body, ok := s.decode(w, r)
+	if !ok {
+		return
+	}
+	if name, _ := body["name"].(string); name == "" {
+		s.writeValidationError(w, "name", "The field cannot be left blank")
+		return
+	}
+	for _, key := range []string{"id", "type", "system", "created", "_links"} {
+		body[key] = policy[key]
+	}
+	if _, ok := body["status"]; !ok {
+		body["status"] = policy["status"]
+	}
+	body["lastUpdated"] = s.now()
+	s.policies.add(params[0], body)
+	prioritize(s.policiesOfType(body["type"]), body)
+	s.writeJSON(w, http.StatusOK, body)
+}
+
+func (s *Server) deletePolicy(w http.ResponseWriter, r *http.Request, params []string) {
+	policy, ok := s.policies.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "Policy")
+		return
+	}
+	if policy["system"] == true {
+		s.writeError(w, http.StatusBadRequest, "E0000001", "Api validation failed: policy", "policy: The default policy cannot be deleted")
+		return
+	}
+	s.policies.remove(params[0])
+	delete(s.policyRules, params[0])
+	prioritize(s.policiesOfType(policy["type"]), nil)
+	s.writeNoContent(w)
+}
+
+func (s *Server) changePolicyLifecycle(w http.ResponseWriter, r *http.Request, params []string) {
+	policy, ok := s.policies.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "Policy")
+		return
+	}
+	if !s.changeStatus(w, policy, params[1]) {
+		return
+	}
+	s.writeNoContent(w)
+}
+
+// changeStatus applies the activate or deactivate lifecycle operation to the
+// object.
+func (s *Server) changeStatus(w http.ResponseWriter, obj object, operation string) bool {
+	switch operation {
+	case "activate":
+		obj["status"] = "ACTIVE"
+	case "deactivate":
+		if obj["system"] == true {
+			s.writeError(w, http.StatusBadRequest, "E0000001", "Api validation failed: status", "status: A default object cannot be deactivated")
+			return false
+		}
+		obj["status"] = "INACTIVE"
+	default:
+		s.writeNotFound(w, operation, "Lifecycle operation")
+		return false
+	}
+	obj["lastUpdated"] = s.now()
+	return true
+}
+
+func (s *Server) listPolicyRules(w http.ResponseWriter, r *http.Request, params []string) {
+	if _, ok := s.policies.get(params[0]); !ok {
+		s.writeNotFound(w, params[0], "Policy")
+		return
+	}
+	rules := s.policyRules[params[0]].list(nil)
+	sortByPriority(rules)
+	s.writeList(w, r, rules, 200, 200)
+}
+
+func (s *Server) createPolicyRule(w http.ResponseWriter, r *http.Request, params []string) {
+	policy, ok := s.policies.get(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "Policy")
+		return
+	}
+	rule, ok := s.decode(w, r)
+	if !ok {
+		return
+	}
+	if name, _ := rule["name"].(string); name == "" {
+		s.writeValidationError(w, "name", "The field cannot be left blank")
+		return
+	}
+	id := s.newID("0pr")
+	now := s.now()
+	rule["id"] = id
+	rule["type"] = ruleTypes[policy["type"].(string)]
+	rule["system"] = false
+	rule["created"] = now
+	rule["lastUpdated"] = now
+	rule["_links"] = s.links(fmt.Sprintf("/api/v1/policies/%s/rules/%s", params[0], id))
+	if _, ok := rule["status"]; !ok {
+		rule["status"] = "ACTIVE"
+	}
+	if !queryBool(r, "activate", true) {
+		rule["status"] = "INACTIVE"
+	}
+	rules := s.policyRules[params[0]]
+	rules.add(id, rule)
+	prioritize(rules.list(nil), rule)
+	s.writeJSON(w, http.StatusOK, rule)
+}
+
+func (s *Server) policyRule(w http.ResponseWriter, params []string) (object, bool) {
+	if _, ok := s.policies.get(params[0]); !ok {
+		s.writeNotFound(w, params[0], "Policy")
+		return nil, false
+	}
+	rule, ok := s.policyRules[params[0]].get(params[1])
+	if !ok {
+		s.writeNotFound(w, params[1], "PolicyRule")
+		return nil, false
+	}
+	return rule, true
+}
+
+func (s *Server) getPolicyRule(w http.ResponseWriter, r *http.Request, params []string) {
+	rule, ok := s.policyRule(w, params)
+	if !ok {
+		return
+	}
+	s.writeJSON(w, http.StatusOK, rule)
+}
+
+func (s *Server) replacePolicyRule(w http.ResponseWriter, r *http.Request, params []string) {
+	rule, ok := s.policyRule(w, params)
+	if !ok {
+		return
+	}
+	body, ok := s.decode(w, r)
+	if !ok {
+		return
+	}
+	if name, _ := body["name"].(string); name == "" {
+		s.writeValidationError(w, "name", "The field cannot be left blank")
+		return
+	}
+	for _, key := range []string{"id", "type", "system", "created", "_links"} {
+		body[key] = rule[key]
+	}
+	if _, ok := body["status"]; !ok {
+		body["status"] = rule["status"]
+	}
+	body["lastUpdated"] = s.now()
+	rules := s.policyRules[params[0]]
+	rules.add(params[1], body)
+	prioritize(rules.list(nil), body)
+	s.writeJSON(w, http.StatusOK, body)
+}
+
+func (s *Server) deletePolicyRule(w http.ResponseWriter, r *http.Request, params []string) {
+	rule, ok := s.policyRule(w, params)
+	if !ok {
+		return
+	}
+	if rule["system"] == true {
+		s.writeError(w, http.StatusBadRequest, "E0000001", "Api validation failed: rule", "rule: The default rule cannot be deleted")
+		return
+	}
+	rules := s.policyRules[params[0]]
+	rules.remove(params[1])
+	prioritize(rules.list(nil), nil)
+	s.writeNoContent(w)
+}
+
+func (s *Server) changePolicyRuleLifecycle(w http.ResponseWriter, r *http.Request, params []string) {
+	rule, ok := s.policyRule(w, params)
+	if !ok {
+		return
+	}
+	if !s.changeStatus(w, rule, params[2]) {
+		return
+	}
+	s.writeNoContent(w)
+}
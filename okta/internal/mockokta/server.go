@@ -0,0 +1,663 @@
+// Package mockokta is an in-process fake of the Okta management API, covering
+// users and the default user schema, groups and group rules, apps, policies
+// and their rules, authorization servers, and event and inline hooks, for
+// running acceptance tests without an org or a VCR cassette. Verified event
+// hooks are delivered the events of users and group memberships. Lists are
+// paginated with Link headers, every response carries rate limit headers and
+// errors have the shape of the Okta API errors.
+package mockokta
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Pipeline of an Okta Identity Engine org.
+	PipelineIdx = "idx"
+	// Pipeline of an Okta Classic Engine org.
+	PipelineV1 = "v1"
+
+	defaultRateLimit = 600
+	timeFormat       = "2006-01-02T15:04:05.000Z"
+)
+
+type object = map[string]interface{}
+
+type handlerFunc func(w http.ResponseWriter, r *http.Request, params []string)
+
+type route struct {
+	method   string
+	segments []string
+	handler  handlerFunc
+}
+
+// Server is a fake Okta org served over http.
+type Server struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	routes      []route
+	pipeline    string
+	rateLimit   int
+	rateLimits  map[string]*rateLimitWindow
+	seq         int
+	lastUpdated time.Time
+
+	adminID      string
+	everyoneID   string
+	users        *collection
+	groups       *collection
+	groupMembers map[string][]string
+	groupRules   *collection
+	apps         *collection
+	appUsers     map[string]*collection
+	appGroups    map[string]*collection
+	policies     *collection
+	policyRules  map[string]*collection
+	authServers  *collection
+	scopes       map[string]*collection
+	claims       map[string]*collection
+	asPolicies   map[string]*collection
+	asRules      map[string]*collection
+	asKeys       map[string][]object
+
+	eventHooks  *collection
+	inlineHooks *collection
+	hookSecrets map[string]string
+	userSchemas map[string]object
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithPipeline sets the pipeline of the org, PipelineIdx by default.
+func WithPipeline(pipeline string) Option {
+	return func(s *Server) {
+		s.pipeline = pipeline
+	}
+}
+
+// WithRateLimit sets the number of requests served per minute for each API
+// endpoint before responding with 429 Too Many Requests.
+func WithRateLimit(limit int) Option {
+	return func(s *Server) {
+		s.rateLimit = limit
+	}
+}
+
+// NewServer starts a fake Okta org with an admin user, the Everyone group,
+// the default policies and the default authorization server. The API token of
+// any request is accepted, requests without one are refused.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		pipeline:     PipelineIdx,
+		rateLimit:    defaultRateLimit,
+		rateLimits:   map[string]*rateLimitWindow{},
+		users:        newCollection(),
+		groups:       newCollection(),
+		groupMembers: map[string][]string{},
+		groupRules:   newCollection(),
+		apps:         newCollection(),
+		appUsers:     map[string]*collection{},
+		appGroups:    map[string]*collection{},
+		policies:     newCollection(),
+		policyRules:  map[string]*collection{},
+		authServers:  newCollection(),
+		scopes:       map[string]*collection{},
+		claims:       map[string]*collection{},
+		asPolicies:   map[string]*collection{},
+		asRules:      map[string]*collection{},
+		asKeys:       map[string][]object{},
+
+		eventHooks:  newCollection(),
+		inlineHooks: newCollection(),
+		hookSecrets: map[string]string{},
+		userSchemas: map[string]object{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.Server = httptest.NewServer(s)
+	s.handle(http.MethodGet, "/.well-known/okta-organization", s.getOrganization)
+	s.userRoutes()
+	s.groupRuleRoutes()
+	s.groupRoutes()
+	s.appRoutes()
+	s.policyRoutes()
+	s.authServerRoutes()
+	s.eventHookRoutes()
+	s.inlineHookRoutes()
+	s.userSchemaRoutes()
+	s.seed()
+	return s
+}
+
+func (s *Server) seed() {
+	s.adminID = s.newID("00u")
+	now := s.now()
+	s.users.add(s.adminID, object{
+		"id":              s.adminID,
+		"status":          "ACTIVE",
+		"created":         now,
+		"activated":       now,
+		"statusChanged":   now,
+		"lastUpdated":     now,
+		"passwordChanged": now,
+		"profile": object{
+			"login":     "admin@example.com",
+			"email":     "admin@example.com",
+			"firstName": "Org",
+			"lastName":  "Admin",
+		},
+		"credentials": object{"password": object{}, "provider": object{"type": "OKTA", "name": "OKTA"}},
+		"_links":      s.links("/api/v1/users/" + s.adminID),
+	})
+	s.everyoneID = s.newID("00g")
+	s.groups.add(s.everyoneID, object{
+		"id":                    s.everyoneID,
+		"type":                  "BUILT_IN",
+		"created":               now,
+		"lastUpdated":           now,
+		"lastMembershipUpdated": now,
+		"objectClass":           []interface{}{"okta:user_group"},
+		"profile":               object{"name": "Everyone", "description": "All users in your organization"},
+		"_links":                s.links("/api/v1/groups/" + s.everyoneID),
+	})
+	s.seedUserSchema()
+	s.seedPolicies()
+	s.seedAuthServer("default", object{
+		"name":        "default",
+		"description": "Default Authorization Server",
+		"audiences":   []interface{}{"api://default"},
+	})
+}
+
+// ServeHTTP authenticates the request, applies the rate limit of the endpoint
+// and serves the request with the matching route.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w.Header().Set("X-Okta-Request-Id", s.newID("mock"))
+	if !s.allow(w, r) {
+		s.writeError(w, http.StatusTooManyRequests, "E0000047", "API call exceeded rate limit due to too many requests.")
+		return
+	}
+	if !strings.HasPrefix(r.URL.Path, "/.well-known/") {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "SSWS ") && !strings.HasPrefix(auth, "Bearer ") {
+			s.writeError(w, http.StatusUnauthorized, "E0000011", "Invalid token provided")
+			return
+		}
+	}
+
+	segments := splitPath(r.URL.Path)
+	methodAllowed := false
+	for _, route := range s.routes {
+		params, ok := route.match(segments)
+		if !ok {
+			continue
+		}
+		if route.method != r.Method {
+			methodAllowed = true
+			continue
+		}
+		route.handler(w, r, params)
+		return
+	}
+	if methodAllowed {
+		s.writeError(w, http.StatusMethodNotAllowed, "E0000022", "The endpoint does not support the provided HTTP method")
+		return
+	}
+	s.writeError(w, http.StatusNotImplemented, "E0000060", fmt.Sprintf("Unsupported operation: the mock Okta server doesn't implement %s %s", r.Method, r.URL.Path))
+}
+
+func (s *Server) handle(method, pattern string, handler handlerFunc) {
+	s.routes = append(s.routes, route{method: method, segments: splitPath(pattern), handler: handler})
+}
+
+// match returns the values of the {} segments of the route when the path
+// matches it.
+func (r route) match(segments []string) ([]string, bool) {
+	if len(segments) != len(r.segments) {
+		return nil, false
+	}
+	var params []string
+	for i, segment := range r.segments {
+		switch {
+		case segment == "{}":
+			params = append(params, segments[i])
+		case segment != segments[i]:
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+func (s *Server) getOrganization(w http.ResponseWriter, r *http.Request, _ []string) {
+	s.writeJSON(w, http.StatusOK, object{
+		"id":       "00o1mock",
+		"pipeline": s.pipeline,
+		"_links":   object{"organization": object{"href": s.URL}},
+	})
+}
+
+type rateLimitWindow struct {
+	reset time.Time
+	count int
+}
+
+// allow counts the request against the rate limit of its endpoint, e.g.
+// /api/v1/users, and sets the rate limit headers.
+func (s *Server) allow(w http.ResponseWriter, r *http.Request) bool {
+	segments := splitPath(r.URL.Path)
+	if len(segments) > 3 {
+		segments = segments[:3]
+	}
+	bucket := strings.Join(segments, "/")
+	now := time.Now()
+	window, ok := s.rateLimits[bucket]
+	if !ok || !now.Before(window.reset) {
+		window = &rateLimitWindow{reset: now.Truncate(time.Minute).Add(time.Minute)}
+		s.rateLimits[bucket] = window
+	}
+	window.count++
+	remaining := s.rateLimit - window.count
+	if remaining < 0 {
+		remaining = 0
+	}
+	w.Header().Set("X-Rate-Limit-Limit", strconv.Itoa(s.rateLimit))
+	w.Header().Set("X-Rate-Limit-Remaining", strconv.Itoa(remaining))
+	w.Header().Set("X-Rate-Limit-Reset", strconv.FormatInt(window.reset.Unix(), 10))
+	return window.count <= s.rateLimit
+}
+
+// newID returns a unique ID of 20 characters starting with the prefix, like
+// 00u for users.
+func (s *Server) newID(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s%0*d", prefix, 20-len(prefix), s.seq)
+}
+
+// now returns the current time, strictly after the previous one so that
+// every change moves the lastUpdated of the object.
+func (s *Server) now() string {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	if !now.After(s.lastUpdated) {
+		now = s.lastUpdated.Add(time.Millisecond)
+	}
+	s.lastUpdated = now
+	return now.Format(timeFormat)
+}
+
+func (s *Server) links(path string) object {
+	return object{"self": object{"href": s.URL + path}}
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (s *Server) writeNoContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeError writes an error with the shape of the Okta API errors, the
+// causes are the summaries of the error causes.
+func (s *Server) writeError(w http.ResponseWriter, status int, code, summary string, causes ...string) {
+	errorCauses := []interface{}{}
+	for _, cause := range causes {
+		errorCauses = append(errorCauses, object{"errorSummary": cause})
+	}
+	s.writeJSON(w, status, object{
+		"errorCode":    code,
+		"errorSummary": summary,
+		"errorLink":    code,
+		"errorId":      s.newID("oae"),
+		"errorCauses":  errorCauses,
+	})
+}
+
+func (s *Server) writeNotFound(w http.ResponseWriter, id, kind string) {
+	s.writeError(w, http.StatusNotFound, "E0000007", fmt.Sprintf("Not found: Resource not found: %s (%s)", id, kind))
+}
+
+func (s *Server) writeValidationError(w http.ResponseWriter, field, cause string) {
+	s.writeError(w, http.StatusBadRequest, "E0000001", fmt.Sprintf("Api validation failed: %s", field), fmt.Sprintf("%s: %s", field, cause))
+}
+
+func (s *Server) writeForbidden(w http.ResponseWriter) {
+	s.writeError(w, http.StatusForbidden, "E0000006", "You do not have permission to perform the requested action")
+}
+
+// writeList writes a page of the items, from the item after the one with the
+// ID of the after query parameter, with a Link header to the next page when
+// there are more items.
+func (s *Server) writeList(w http.ResponseWriter, r *http.Request, items []object, defaultLimit, maxLimit int) {
+	limit := defaultLimit
+	if v, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && v > 0 {
+		limit = v
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	start := 0
+	if after := r.URL.Query().Get("after"); after != "" {
+		for i, item := range items {
+			if item["id"] == after {
+				start = i + 1
+				break
+			}
+		}
+	}
+	end := start + limit
+	if end > len(items) {
+		end = len(items)
+	}
+	page := items[start:end]
+	links := []string{fmt.Sprintf(`<%s>; rel="self"`, s.pageURL(r, "", limit))}
+	if end < len(items) {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, s.pageURL(r, page[len(page)-1]["id"].(string), limit)))
+	}
+	w.Header()["Link"] = links
+	if page == nil {
+		page = []object{}
+	}
+	s.writeJSON(w, http.StatusOK, page)
+}
+
+func (s *Server) pageURL(r *http.Request, after string, limit int) string {
+	query := url.Values{}
+	for k, v := range r.URL.Query() {
+		query[k] = v
+	}
+	query.Set("limit", strconv.Itoa(limit))
+	if after != "" {
+		query.Set("after", after)
+	} else {
+		query.Del("after")
+	}
+	return s.URL + r.URL.Path + "?" + query.Encode()
+}
+
+// decode reads the JSON object of the request body.
+func (s *Server) decode(w http.ResponseWriter, r *http.Request) (object, bool) {
+	body := object{}
+	if r.Body == nil || r.ContentLength == 0 {
+		return body, true
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		s.writeError(w, http.StatusBadRequest, "E0000003", "The request body was not well-formed.", err.Error())
+		return nil, false
+	}
+	return body, true
+}
+
+// collection holds objects in insertion order.
+type collection struct {
+	ids   []string
+	items map[string]object
+}
+
+func newCollection() *collection {
+	return &collection{items: map[string]object{}}
+}
+
+func (c *collection) add(id string, obj object) {
+	if _, ok := c.items[id]; !ok {
+		c.ids = append(c.ids, id)
+	}
+	c.items[id] = obj
+}
+
+func (c *collection) get(id string) (object, bool) {
+	obj, ok := c.items[id]
+	return obj, ok
+}
+
+func (c *collection) remove(id string) {
+	if _, ok := c.items[id]; !ok {
+		return
+	}
+	delete(c.items, id)
+	for i := range c.ids {
+		if c.ids[i] == id {
+			c.ids = append(c.ids[:i], c.ids[i+1:]...)
+			break
+		}
+	}
+}
+
+// list returns the objects matching the filter, all of them if it's nil.
+func (c *collection) list(filter func(object) bool) []object {
+	var items []object
+	for _, id := range c.ids {
+		if obj := c.items[id]; filter == nil || filter(obj) {
+			items = append(items, obj)
+		}
+	}
+	return items
+}
+
+// sortByPriority sorts objects by their priority, the system ones, like the
+// default policy, last.
+func sortByPriority(items []object) {
+	sort.SliceStable(items, func(i, j int) bool {
+		si, sj := items[i]["system"] == true, items[j]["system"] == true
+		if si != sj {
+			return sj
+		}
+		return toInt(items[i]["priority"]) < toInt(items[j]["priority"])
+	})
+}
+
+// prioritize inserts the object at its priority among the items, or last when
+// it has none, and renumbers the priorities from 1. A nil object only
+// renumbers the items.
+func prioritize(items []object, obj object) {
+	var others []object
+	for _, item := range items {
+		if item["id"] != obj["id"] && item["system"] != true {
+			others = append(others, item)
+		}
+	}
+	sortByPriority(others)
+	position := len(others)
+	if p := toInt(obj["priority"]); p > 0 && p-1 < position {
+		position = p - 1
+	}
+	if obj != nil && obj["system"] != true {
+		others = append(others[:position], append([]object{obj}, others[position:]...)...)
+	}
+	for i, item := range others {
+		item["priority"] = i + 1
+	}
+	for _, item := range items {
+		if item["system"] == true {
+			item["priority"] = len(others) + 1
+		}
+	}
+}
+
+func toInt(v interface{}) int {
+	switch v := v.(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	case json.Number:
+		i, _ := v.Int64()
+		return int(i)
+	}
+	return 0
+}
+
+// field returns the value at the dotted path of the object, e.g.
+// profile.login.
+func field(obj object, path string) (interface{}, bool) {
+	var v interface{} = obj
+	for _, key := range strings.Split(path, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+func stringField(obj object, path string) string {
+	v, _ := field(obj, path)
+	s, _ := v.(string)
+	return s
+}
+
+// merge copies the fields of src to dst, merging nested objects.
+func merge(dst, src object) {
+	for k, v := range src {
+		if m, ok := v.(map[string]interface{}); ok {
+			if d, ok := dst[k].(map[string]interface{}); ok {
+				merge(d, m)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+}
+
+// matchFilter reports whether the object matches a filter or search
+// expression of eq, ne, sw and co comparisons joined by and or or, e.g.
+// status eq "ACTIVE" and profile.login sw "john".
+func matchFilter(expression string, resolve func(path string) (interface{}, bool)) bool {
+	expression = strings.NewReplacer("(", "", ")", "").Replace(expression)
+	for _, alternative := range splitKeyword(expression, "or") {
+		matched := true
+		for _, clause := range splitKeyword(alternative, "and") {
+			if !matchClause(clause, resolve) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func splitKeyword(expression, keyword string) []string {
+	var parts []string
+	var current []string
+	for _, token := range strings.Fields(expression) {
+		if strings.EqualFold(token, keyword) {
+			parts = append(parts, strings.Join(current, " "))
+			current = nil
+			continue
+		}
+		current = append(current, token)
+	}
+	return append(parts, strings.Join(current, " "))
+}
+
+func matchClause(clause string, resolve func(path string) (interface{}, bool)) bool {
+	parts := strings.SplitN(strings.TrimSpace(clause), " ", 3)
+	if len(parts) == 2 && strings.EqualFold(parts[1], "pr") {
+		v, ok := resolve(parts[0])
+		return ok && v != nil && v != ""
+	}
+	if len(parts) != 3 {
+		return false
+	}
+	want := strings.Trim(parts[2], `"`)
+	v, ok := resolve(parts[0])
+	got := fmt.Sprint(v)
+	if !ok || v == nil {
+		got = ""
+	}
+	switch strings.ToLower(parts[1]) {
+	case "eq":
+		return strings.EqualFold(got, want)
+	case "ne":
+		return !strings.EqualFold(got, want)
+	case "sw":
+		return strings.HasPrefix(strings.ToLower(got), strings.ToLower(want))
+	case "co":
+		return strings.Contains(strings.ToLower(got), strings.ToLower(want))
+	}
+	return false
+}
+
+// listFilter returns the filter of the filter or search query parameter of
+// the request, nil when there is none.
+func listFilter(r *http.Request, resolve func(obj object) func(path string) (interface{}, bool)) func(object) bool {
+	expression := r.URL.Query().Get("search")
+	if expression == "" {
+		expression = r.URL.Query().Get("filter")
+	}
+	if expression == "" {
+		return nil
+	}
+	return func(obj object) bool {
+		return matchFilter(expression, resolve(obj))
+	}
+}
+
+func objectResolver(obj object) func(path string) (interface{}, bool) {
+	return func(path string) (interface{}, bool) {
+		return field(obj, path)
+	}
+}
+
+// queryPrefix returns a filter matching objects with one of the fields
+// starting with the q query parameter, nil when there is none.
+func queryPrefix(r *http.Request, paths ...string) func(object) bool {
+	q := strings.ToLower(r.URL.Query().Get("q"))
+	if q == "" {
+		return nil
+	}
+	return func(obj object) bool {
+		for _, path := range paths {
+			if strings.HasPrefix(strings.ToLower(stringField(obj, path)), q) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// allOf returns a filter matching objects matching every non nil filter.
+func allOf(filters ...func(object) bool) func(object) bool {
+	return func(obj object) bool {
+		for _, filter := range filters {
+			if filter != nil && !filter(obj) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func queryBool(r *http.Request, name string, defaultValue bool) bool {
+	v, err := strconv.ParseBool(r.URL.Query().Get(name))
+	if err != nil {
+		return defaultValue
+	}
+	return v
+}
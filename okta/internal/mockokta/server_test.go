@@ -0,0 +1,169 @@
+package mockokta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/okta/terraform-provider-okta/sdk/query"
+)
+
+func newTestClient(t *testing.T, s *Server) *sdk.Client {
+	_, client, err := sdk.NewClient(context.Background(),
+		sdk.WithOrgUrl(s.URL),
+		sdk.WithToken("token"),
+		sdk.WithCache(false),
+		sdk.WithTestingDisableHttpsCheck(true),
+		sdk.WithRateLimitMaxRetries(0),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+func TestServerPagination(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	client := newTestClient(t, s)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		_, _, err := client.Group.CreateGroup(ctx, sdk.Group{Profile: &sdk.GroupProfile{Name: fmt.Sprintf("group-%d", i)}})
+		if err != nil {
+			t.Fatalf("failed to create group: %v", err)
+		}
+	}
+	groups, resp, err := client.Group.ListGroups(ctx, &query.Params{Limit: 2})
+	if err != nil {
+		t.Fatalf("failed to list groups: %v", err)
+	}
+	pages := 1
+	for resp.HasNextPage() {
+		var page []*sdk.Group
+		if resp, err = resp.Next(ctx, &page); err != nil {
+			t.Fatalf("failed to list the next page of groups: %v", err)
+		}
+		groups = append(groups, page...)
+		pages++
+	}
+	// the Everyone group and the created ones
+	if len(groups) != 6 || pages != 3 {
+		t.Errorf("expected 6 groups in 3 pages, got %d groups in %d pages", len(groups), pages)
+	}
+	if groups[0].Profile.Name != "Everyone" || groups[5].Profile.Name != "group-4" {
+		t.Errorf("expected the groups in creation order, got %q first and %q last", groups[0].Profile.Name, groups[5].Profile.Name)
+	}
+
+	groups, _, err = client.Group.ListGroups(ctx, &query.Params{Q: "group-3"})
+	if err != nil || len(groups) != 1 {
+		t.Errorf("expected a group to be found, got %d: %v", len(groups), err)
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	client := newTestClient(t, s)
+	ctx := context.Background()
+
+	_, resp, err := client.Group.GetGroup(ctx, "00gdoesnotexist")
+	var oktaErr *sdk.Error
+	if resp == nil || resp.StatusCode != http.StatusNotFound || !errors.As(err, &oktaErr) || oktaErr.ErrorCode != "E0000007" {
+		t.Errorf("expected a E0000007 not found error, got %v", err)
+	}
+
+	_, _, err = client.Group.CreateGroup(ctx, sdk.Group{Profile: &sdk.GroupProfile{Name: "Everyone"}})
+	if err != nil {
+		t.Fatalf("expected a group named like the built in one to be created, got %v", err)
+	}
+	_, resp, err = client.Group.CreateGroup(ctx, sdk.Group{Profile: &sdk.GroupProfile{Name: "Everyone"}})
+	if resp == nil || resp.StatusCode != http.StatusBadRequest || !errors.As(err, &oktaErr) || oktaErr.ErrorCode != "E0000001" || len(oktaErr.ErrorCauses) != 1 {
+		t.Errorf("expected a E0000001 validation error with a cause, got %v", err)
+	}
+
+	app := sdk.NewBookmarkApplication()
+	app.Label = "bookmark"
+	app.Settings = &sdk.BookmarkApplicationSettings{App: &sdk.BookmarkApplicationSettingsApplication{Url: "https://example.com"}}
+	if _, _, err = client.Application.CreateApplication(ctx, app, nil); err != nil {
+		t.Fatalf("failed to create app: %v", err)
+	}
+	resp, err = client.Application.DeleteApplication(ctx, app.Id)
+	if resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected an active app not to be deleted, got %v", err)
+	}
+	if _, err = client.Application.DeactivateApplication(ctx, app.Id); err != nil {
+		t.Fatalf("failed to deactivate app: %v", err)
+	}
+	if _, err = client.Application.DeleteApplication(ctx, app.Id); err != nil {
+		t.Errorf("expected an inactive app to be deleted, got %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, s.URL+"/api/v1/users", nil)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected a request without an API token to be refused, got %d", res.StatusCode)
+	}
+}
+
+func TestServerRateLimit(t *testing.T) {
+	s := NewServer(WithRateLimit(2))
+	defer s.Close()
+
+	var statuses []int
+	var remaining []string
+	for i := 0; i < 3; i++ {
+		req, _ := http.NewRequest(http.MethodGet, s.URL+"/api/v1/groups", nil)
+		req.Header.Set("Authorization", "SSWS token")
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		statuses = append(statuses, res.StatusCode)
+		remaining = append(remaining, res.Header.Get("X-Rate-Limit-Remaining"))
+		if res.Header.Get("X-Rate-Limit-Limit") != "2" || res.Header.Get("X-Rate-Limit-Reset") == "" {
+			t.Errorf("expected the rate limit headers, got %v", res.Header)
+		}
+	}
+	if fmt.Sprint(statuses) != "[200 200 429]" || fmt.Sprint(remaining) != "[1 0 0]" {
+		t.Errorf("expected the third request to be rate limited, got statuses %v remaining %v", statuses, remaining)
+	}
+}
+
+func TestServerPolicyPriority(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	client := newTestClient(t, s)
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		policy := &sdk.Policy{Type: sdk.PasswordPolicyType, Name: fmt.Sprintf("policy-%d", i), PriorityPtr: sdk.Int64Ptr(1)}
+		if _, _, err := client.Policy.CreatePolicy(ctx, policy, nil); err != nil {
+			t.Fatalf("failed to create policy: %v", err)
+		}
+	}
+	policies, _, err := client.Policy.ListPolicies(ctx, &query.Params{Type: "PASSWORD"})
+	if err != nil {
+		t.Fatalf("failed to list policies: %v", err)
+	}
+	var names []string
+	for _, policy := range policies {
+		p := policy.(*sdk.Policy)
+		names = append(names, fmt.Sprintf("%s:%d", p.Name, *p.PriorityPtr))
+	}
+	if fmt.Sprint(names) != "[policy-3:1 policy-2:2 policy-1:3 Default Policy:4]" {
+		t.Errorf("expected each policy to be inserted first and the default one last, got %v", names)
+	}
+
+	if _, _, err = client.Policy.ListPolicies(ctx, nil); err == nil {
+		t.Error("expected listing policies without a type to fail")
+	}
+}
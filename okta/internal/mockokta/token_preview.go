@@ -0,0 +1,176 @@
+package mockokta
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	userAttribute = regexp.MustCompile(`^user\.(\w+)$`)
+	stringLiteral = regexp.MustCompile(`^"([^"]*)"$`)
+)
+
+// previewAuthServerTokens evaluates the claims of the ID and access tokens of
+// a client for a user and scopes. Claims with an expression value support
+// user profile attributes, e.g. user.department, and string literals, others
+// fail as not implemented. The endpoint is undocumented, the shape of the
+// request and response follows the one of okta_auth_server_token_preview
+// rather than a recorded response of an org.
+func (s *Server) previewAuthServerTokens(w http.ResponseWriter, r *http.Request, params []string) {
+	authServer, ok := s.authServer(w, params[0])
+	if !ok {
+		return
+	}
+	apps := s.apps.list(func(app object) bool {
+		return stringField(app, "credentials.oauthClient.client_id") == params[1]
+	})
+	if len(apps) == 0 {
+		s.writeNotFound(w, params[1], "OAuth2Client")
+		return
+	}
+	body, ok := s.decode(w, r)
+	if !ok {
+		return
+	}
+	userID, _ := body["userId"].(string)
+	user, ok := s.findUser(userID)
+	if !ok {
+		s.writeNotFound(w, userID, "User")
+		return
+	}
+	var scopes []string
+	if values, ok := body["scopes"].([]interface{}); ok {
+		for _, v := range values {
+			scopes = append(scopes, fmt.Sprint(v))
+		}
+	}
+	var unknown []string
+	for _, scope := range scopes {
+		if len(s.scopes[params[0]].list(func(o object) bool { return o["name"] == scope })) == 0 {
+			unknown = append(unknown, scope)
+		}
+	}
+	if len(unknown) > 0 {
+		s.writeValidationError(w, "scopes", "The following scopes are invalid: "+strings.Join(unknown, ", "))
+		return
+	}
+
+	issuer := authServer["issuer"]
+	audience := ""
+	if audiences, ok := authServer["audiences"].([]interface{}); ok && len(audiences) > 0 {
+		audience = fmt.Sprint(audiences[0])
+	}
+	accessToken := object{
+		"ver": 1,
+		"iss": issuer,
+		"aud": audience,
+		"cid": params[1],
+		"uid": user["id"],
+		"sub": stringField(user, "profile.login"),
+		"scp": scopes,
+	}
+	idToken := object{
+		"ver": 1,
+		"iss": issuer,
+		"aud": params[1],
+		"sub": user["id"],
+	}
+	if contains(scopes, "profile") {
+		idToken["name"] = strings.TrimSpace(stringField(user, "profile.firstName") + " " + stringField(user, "profile.lastName"))
+		idToken["preferred_username"] = stringField(user, "profile.login")
+	}
+	if contains(scopes, "email") {
+		idToken["email"] = stringField(user, "profile.email")
+	}
+
+	for _, claim := range s.claims[params[0]].list(nil) {
+		if claim["system"] == true || claim["status"] != "ACTIVE" || !claimRequested(claim, scopes) {
+			continue
+		}
+		value, ok := s.claimValue(w, claim, user)
+		if !ok {
+			return
+		}
+		if claim["claimType"] == "IDENTITY" {
+			idToken[claim["name"].(string)] = value
+		} else {
+			accessToken[claim["name"].(string)] = value
+		}
+	}
+	s.writeJSON(w, http.StatusOK, object{"idToken": idToken, "accessToken": accessToken})
+}
+
+// claimRequested reports whether the claim is included for the scopes, a
+// claim without scope conditions is included for any.
+func claimRequested(claim object, scopes []string) bool {
+	conditionScopes, _ := field(claim, "conditions.scopes")
+	values, _ := conditionScopes.([]interface{})
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if contains(scopes, fmt.Sprint(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) claimValue(w http.ResponseWriter, claim object, user object) (interface{}, bool) {
+	value, _ := claim["value"].(string)
+	switch claim["valueType"] {
+	case "GROUPS":
+		return s.claimGroups(w, claim, user)
+	case "EXPRESSION":
+		value = strings.TrimSpace(value)
+		if m := userAttribute.FindStringSubmatch(value); m != nil {
+			v, _ := field(user, "profile."+m[1])
+			return v, true
+		}
+		if m := stringLiteral.FindStringSubmatch(value); m != nil {
+			return m[1], true
+		}
+	}
+	s.writeError(w, http.StatusNotImplemented, "E0000060", fmt.Sprintf("Unsupported operation: the mock Okta server doesn't evaluate the value %q of claim %s", value, claim["name"]))
+	return nil, false
+}
+
+// claimGroups returns the sorted names of the groups of the user matching the
+// group filter of the claim.
+func (s *Server) claimGroups(w http.ResponseWriter, claim object, user object) (interface{}, bool) {
+	filter, _ := claim["value"].(string)
+	var match func(name string) bool
+	switch claim["groupFilterType"] {
+	case "STARTS_WITH":
+		match = func(name string) bool { return strings.HasPrefix(name, filter) }
+	case "EQUALS":
+		match = func(name string) bool { return name == filter }
+	case "CONTAINS":
+		match = func(name string) bool { return strings.Contains(name, filter) }
+	case "REGEX":
+		re, err := regexp.Compile("^(?:" + filter + ")$")
+		if err != nil {
+			s.writeValidationError(w, "value", "The regular expression of the group filter is invalid")
+			return nil, false
+		}
+		match = re.MatchString
+	default:
+		s.writeValidationError(w, "groupFilterType", "The group filter type is invalid")
+		return nil, false
+	}
+	id := user["id"].(string)
+	names := []string{}
+	for _, group := range s.groups.list(nil) {
+		if group["id"] != s.everyoneID && !contains(s.groupMembers[group["id"].(string)], id) {
+			continue
+		}
+		if name := stringField(group, "profile.name"); match(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, true
+}
@@ -0,0 +1,110 @@
+package mockokta
+
+import (
+	"net/http"
+)
+
+func (s *Server) userSchemaRoutes() {
+	s.handle(http.MethodGet, "/api/v1/meta/schemas/user/{}", s.getUserSchema)
+	s.handle(http.MethodPost, "/api/v1/meta/schemas/user/{}", s.updateUserSchema)
+}
+
+func (s *Server) seedUserSchema() {
+	base := object{}
+	for _, name := range []string{"login", "email", "firstName", "lastName"} {
+		base[name] = object{
+			"title":       name,
+			"type":        "string",
+			"required":    true,
+			"mutability":  "READ_WRITE",
+			"scope":       "NONE",
+			"permissions": []interface{}{object{"principal": "SELF", "action": "READ_WRITE"}},
+			"master":      object{"type": "PROFILE_MASTER"},
+		}
+	}
+	s.userSchemas["default"] = object{
+		"id":      s.URL + "/meta/schemas/user/default",
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"name":    "user",
+		"title":   "User",
+		"type":    "object",
+		"definitions": object{
+			"base":   object{"id": "#base", "type": "object", "properties": base, "required": []interface{}{"login", "email", "firstName", "lastName"}},
+			"custom": object{"id": "#custom", "type": "object", "properties": object{}, "required": []interface{}{}},
+		},
+		"_links": s.links("/api/v1/meta/schemas/user/default"),
+	}
+}
+
+func (s *Server) getUserSchema(w http.ResponseWriter, r *http.Request, params []string) {
+	userSchema, ok := s.userSchemas[params[0]]
+	if !ok {
+		s.writeNotFound(w, params[0], "UserSchema")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, userSchema)
+}
+
+// updateUserSchema partially updates the properties of a schema, a custom
+// property set to null is removed. Base properties can't be added or
+// removed, only their title, permissions and master can change.
+func (s *Server) updateUserSchema(w http.ResponseWriter, r *http.Request, params []string) {
+	userSchema, ok := s.userSchemas[params[0]]
+	if !ok {
+		s.writeNotFound(w, params[0], "UserSchema")
+		return
+	}
+	body, ok := s.decode(w, r)
+	if !ok {
+		return
+	}
+	base, _ := field(userSchema, "definitions.base.properties")
+	baseProperties := base.(map[string]interface{})
+	custom, _ := field(userSchema, "definitions.custom.properties")
+	customProperties := custom.(map[string]interface{})
+
+	updates, _ := field(body, "definitions.base.properties")
+	baseUpdates, _ := updates.(map[string]interface{})
+	for name, v := range baseUpdates {
+		property, ok := baseProperties[name].(map[string]interface{})
+		update, _ := v.(map[string]interface{})
+		if !ok || update == nil {
+			s.writeValidationError(w, name, "Base properties cannot be added or removed")
+			return
+		}
+		for _, key := range []string{"title", "permissions", "master", "required", "pattern"} {
+			if value, ok := update[key]; ok {
+				property[key] = value
+			}
+		}
+	}
+
+	updates, _ = field(body, "definitions.custom.properties")
+	customUpdates, _ := updates.(map[string]interface{})
+	for name, v := range customUpdates {
+		if _, ok := baseProperties[name]; ok {
+			s.writeValidationError(w, name, "A property with this name already exists in the base schema")
+			return
+		}
+		if v == nil {
+			delete(customProperties, name)
+			continue
+		}
+		property, _ := v.(map[string]interface{})
+		for _, key := range []string{"title", "type"} {
+			if value, _ := property[key].(string); value == "" {
+				s.writeValidationError(w, name+"."+key, "The field cannot be left blank")
+				return
+			}
+		}
+		if _, ok := property["scope"]; !ok {
+			property["scope"] = "NONE"
+		}
+		if _, ok := property["mutability"]; !ok {
+			property["mutability"] = "READ_WRITE"
+		}
+		customProperties[name] = property
+	}
+	userSchema["lastUpdated"] = s.now()
+	s.writeJSON(w, http.StatusOK, userSchema)
+}
@@ -0,0 +1,303 @@
+package mockokta
+
+import (
+	"net/http"
+	"strings"
+)
+
+func (s *Server) userRoutes() {
+	s.handle(http.MethodGet, "/api/v1/users", s.listUsers)
+	s.handle(http.MethodPost, "/api/v1/users", s.createUser)
+	s.handle(http.MethodGet, "/api/v1/users/{}", s.getUser)
+	s.handle(http.MethodPost, "/api/v1/users/{}", s.updateUser)
+	s.handle(http.MethodPut, "/api/v1/users/{}", s.replaceUser)
+	s.handle(http.MethodDelete, "/api/v1/users/{}", s.deleteUser)
+	s.handle(http.MethodPost, "/api/v1/users/{}/lifecycle/{}", s.changeUserLifecycle)
+	s.handle(http.MethodGet, "/api/v1/users/{}/groups", s.listUserGroups)
+	s.handle(http.MethodGet, "/api/v1/users/{}/roles", s.listUserRoles)
+}
+
+// findUser returns the user with the ID or login, me is the admin user.
+func (s *Server) findUser(idOrLogin string) (object, bool) {
+	if idOrLogin == "me" {
+		idOrLogin = s.adminID
+	}
+	if user, ok := s.users.get(idOrLogin); ok {
+		return user, true
+	}
+	for _, user := range s.users.list(nil) {
+		if strings.EqualFold(stringField(user, "profile.login"), idOrLogin) {
+			return user, true
+		}
+	}
+	return nil, false
+}
+
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request, _ []string) {
+	filter := listFilter(r, objectResolver)
+	notDeprovisioned := func(user object) bool {
+		return user["status"] != "DEPROVISIONED"
+	}
+	if filter != nil {
+		// deprovisioned users are only listed when searched for
+		notDeprovisioned = nil
+	}
+	users := s.users.list(allOf(notDeprovisioned, filter,
+		queryPrefix(r, "profile.login", "profile.email", "profile.firstName", "profile.lastName")))
+	s.writeList(w, r, users, 200, 200)
+}
+
+func (s *Server) createUser(w http.ResponseWriter, r *http.Request, _ []string) {
+	user, ok := s.decode(w, r)
+	if !ok {
+		return
+	}
+	profile, _ := user["profile"].(map[string]interface{})
+	if profile == nil {
+		profile = object{}
+		user["profile"] = profile
+	}
+	for _, name := range []string{"login", "email", "firstName", "lastName"} {
+		if v, _ := profile[name].(string); v == "" {
+			s.writeValidationError(w, name, "The field cannot be left blank")
+			return
+		}
+	}
+	if _, exists := s.findUser(profile["login"].(string)); exists {
+		s.writeValidationError(w, "login", "An object with this field already exists in the current organization")
+		return
+	}
+	credentials, _ := user["credentials"].(map[string]interface{})
+	hasPassword := false
+	if credentials != nil {
+		password, _ := credentials["password"].(map[string]interface{})
+		hasPassword = password != nil && (password["value"] != nil || password["hash"] != nil)
+	}
+	id := s.newID("00u")
+	now := s.now()
+	user["id"] = id
+	user["created"] = now
+	user["lastUpdated"] = now
+	user["statusChanged"] = now
+	user["credentials"] = s.userCredentials(credentials)
+	user["_links"] = s.links("/api/v1/users/" + id)
+	switch {
+	case !queryBool(r, "activate", true):
+		user["status"] = "STAGED"
+	case hasPassword:
+		user["status"] = "ACTIVE"
+		user["activated"] = now
+		user["passwordChanged"] = now
+	default:
+		user["status"] = "PROVISIONED"
+		user["activated"] = now
+	}
+	delete(user, "groupIds")
+	s.users.add(id, user)
+	s.publish("user.lifecycle.create", "Create Okta user", userTarget(user))
+	s.writeJSON(w, http.StatusOK, user)
+}
+
+// userCredentials returns the credentials of a user as returned by the API,
+// without the password.
+func (s *Server) userCredentials(credentials map[string]interface{}) object {
+	result := object{"provider": object{"type": "OKTA", "name": "OKTA"}}
+	if credentials == nil {
+		return result
+	}
+	if _, ok := credentials["password"]; ok {
+		result["password"] = object{}
+	}
+	if question, ok := credentials["recovery_question"].(map[string]interface{}); ok {
+		result["recovery_question"] = object{"question": question["question"]}
+	}
+	return result
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request, params []string) {
+	user, ok := s.findUser(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "User")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, user)
+}
+
+// updateUser partially updates the profile and credentials of a user.
+func (s *Server) updateUser(w http.ResponseWriter, r *http.Request, params []string) {
+	user, ok := s.findUser(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "User")
+		return
+	}
+	body, ok := s.decode(w, r)
+	if !ok {
+		return
+	}
+	if profile, ok := body["profile"].(map[string]interface{}); ok {
+		merge(user["profile"].(map[string]interface{}), profile)
+	}
+	if credentials, ok := body["credentials"].(map[string]interface{}); ok {
+		merge(user["credentials"].(map[string]interface{}), s.userCredentials(credentials))
+	}
+	user["lastUpdated"] = s.now()
+	s.publish("user.account.update_profile", "Update user profile for Okta", userTarget(user))
+	s.writeJSON(w, http.StatusOK, user)
+}
+
+// replaceUser replaces the profile of a user.
+func (s *Server) replaceUser(w http.ResponseWriter, r *http.Request, params []string) {
+	user, ok := s.findUser(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "User")
+		return
+	}
+	body, ok := s.decode(w, r)
+	if !ok {
+		return
+	}
+	if profile, ok := body["profile"].(map[string]interface{}); ok {
+		user["profile"] = profile
+	}
+	if credentials, ok := body["credentials"].(map[string]interface{}); ok {
+		merge(user["credentials"].(map[string]interface{}), s.userCredentials(credentials))
+	}
+	user["lastUpdated"] = s.now()
+	s.publish("user.account.update_profile", "Update user profile for Okta", userTarget(user))
+	s.writeJSON(w, http.StatusOK, user)
+}
+
+// deleteUser deactivates a user, deletes it when it's already deactivated.
+func (s *Server) deleteUser(w http.ResponseWriter, r *http.Request, params []string) {
+	user, ok := s.findUser(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "User")
+		return
+	}
+	if user["status"] != "DEPROVISIONED" {
+		s.setUserStatus(user, "DEPROVISIONED")
+		s.publish("user.lifecycle.deactivate", "Deactivate Okta user", userTarget(user))
+		s.writeNoContent(w)
+		return
+	}
+	id := user["id"].(string)
+	s.publish("user.lifecycle.delete.initiated", "Delete Okta user", userTarget(user))
+	s.users.remove(id)
+	for groupID := range s.groupMembers {
+		s.groupMembers[groupID] = without(s.groupMembers[groupID], id)
+	}
+	for _, appUsers := range s.appUsers {
+		appUsers.remove(id)
+	}
+	s.writeNoContent(w)
+}
+
+func (s *Server) changeUserLifecycle(w http.ResponseWriter, r *http.Request, params []string) {
+	user, ok := s.findUser(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "User")
+		return
+	}
+	status := user["status"]
+	switch params[1] {
+	case "activate", "reactivate":
+		if status == "ACTIVE" {
+			s.writeError(w, http.StatusForbidden, "E0000016", "Activation failed because the user is already active")
+			return
+		}
+		s.setUserStatus(user, "ACTIVE")
+		user["activated"] = user["lastUpdated"]
+		s.publish("user.lifecycle.activate", "Activate Okta user", userTarget(user))
+	case "deactivate":
+		s.setUserStatus(user, "DEPROVISIONED")
+		s.publish("user.lifecycle.deactivate", "Deactivate Okta user", userTarget(user))
+	case "suspend":
+		if status != "ACTIVE" {
+			s.writeError(w, http.StatusBadRequest, "E0000001", "Api validation failed: status", "status: Cannot suspend a user that is not active")
+			return
+		}
+		s.setUserStatus(user, "SUSPENDED")
+		s.publish("user.lifecycle.suspend", "Suspend Okta user", userTarget(user))
+	case "unsuspend":
+		if status != "SUSPENDED" {
+			s.writeError(w, http.StatusBadRequest, "E0000001", "Api validation failed: status", "status: Cannot unsuspend a user that is not suspended")
+			return
+		}
+		s.setUserStatus(user, "ACTIVE")
+		s.publish("user.lifecycle.unsuspend", "Unsuspend Okta user", userTarget(user))
+	case "unlock":
+		if status == "LOCKED_OUT" {
+			s.setUserStatus(user, "ACTIVE")
+		}
+	case "expire_password":
+		s.setUserStatus(user, "PASSWORD_EXPIRED")
+		s.writeJSON(w, http.StatusOK, user)
+		return
+	case "reset_password":
+		s.setUserStatus(user, "RECOVERY")
+	default:
+		s.writeNotFound(w, params[1], "Lifecycle operation")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, object{})
+}
+
+func (s *Server) setUserStatus(user object, status string) {
+	now := s.now()
+	user["status"] = status
+	user["statusChanged"] = now
+	user["lastUpdated"] = now
+}
+
+func (s *Server) listUserGroups(w http.ResponseWriter, r *http.Request, params []string) {
+	user, ok := s.findUser(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "User")
+		return
+	}
+	id := user["id"].(string)
+	groups := s.groups.list(func(group object) bool {
+		return group["id"] == s.everyoneID || contains(s.groupMembers[group["id"].(string)], id)
+	})
+	s.writeList(w, r, groups, 200, 200)
+}
+
+// listUserRoles lists the admin roles of a user, the admin user is super
+// admin.
+func (s *Server) listUserRoles(w http.ResponseWriter, r *http.Request, params []string) {
+	user, ok := s.findUser(params[0])
+	if !ok {
+		s.writeNotFound(w, params[0], "User")
+		return
+	}
+	roles := []object{}
+	if user["id"] == s.adminID {
+		roles = append(roles, object{
+			"id":             "ra1mocksuperadmin00",
+			"label":          "Super Administrator",
+			"type":           "SUPER_ADMIN",
+			"status":         "ACTIVE",
+			"assignmentType": "USER",
+		})
+	}
+	s.writeJSON(w, http.StatusOK, roles)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func without(values []string, value string) []string {
+	var result []string
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
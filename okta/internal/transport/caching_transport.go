@@ -0,0 +1,126 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/okta/terraform-provider-okta/sdk/cache"
+)
+
+type CachingTransport struct {
+	base   http.RoundTripper
+	cache  cache.Cache
+	logger hclog.Logger
+	mu     sync.Mutex
+	// keys indexes cached keys by their URL path so that a mutation to a path
+	// can invalidate every cached query string variation of it.
+	keys map[string]map[string]struct{}
+}
+
+// NewCachingTransport returns a transport that serves successful GET requests
+// from the cache and invalidates cached entries for a path, its parents, its
+// children, and every path holding one of its object IDs whenever a non-GET
+// request is made to that path. The cache is
+// expected to be scoped to the lifetime of the provider, e.g. a single
+// terraform plan or apply.
+func NewCachingTransport(base http.RoundTripper, c cache.Cache, logger hclog.Logger) *CachingTransport {
+	return &CachingTransport{
+		base:   base,
+		cache:  c,
+		logger: logger,
+		keys:   make(map[string]map[string]struct{}),
+	}
+}
+
+// RoundTrip returns a cached response for GET requests when one is available,
+// otherwise it calls the base round tripper and caches successful GET
+// responses.
+func (t *CachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := strings.TrimSuffix(req.URL.Path, "/")
+	if req.Method != http.MethodGet {
+		t.invalidate(path)
+		return t.base.RoundTrip(req)
+	}
+
+	key := cache.CreateCacheKey(req)
+	if t.cache.Has(key) {
+		if resp := t.cache.Get(key); resp != nil {
+			t.logger.Debug(fmt.Sprintf("serving cached response for \"%s %s\"", req.Method, req.URL.RequestURI()))
+			resp.Request = req
+			return resp, nil
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusOK {
+		t.cache.Set(key, resp)
+		t.index(path, key)
+	}
+	return resp, nil
+}
+
+func (t *CachingTransport) index(path, key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.keys[path]; !ok {
+		t.keys[path] = make(map[string]struct{})
+	}
+	t.keys[path][key] = struct{}{}
+}
+
+// invalidate deletes cached entries for the given path, for any of its parent
+// paths (e.g. the list endpoint of the mutated object), for any of its child
+// paths (e.g. sub-resources of a deleted object) and for any path holding one
+// of its object IDs (e.g. /api/v1/users/{u}/groups after a PUT to
+// /api/v1/groups/{g}/users/{u}).
+func (t *CachingTransport) invalidate(path string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ids := objectIDs(path)
+	for cached, keys := range t.keys {
+		if !relatedPaths(path, cached) && !holdsObjectID(cached, ids) {
+			continue
+		}
+		for key := range keys {
+			t.cache.Delete(key)
+		}
+		delete(t.keys, cached)
+	}
+}
+
+func relatedPaths(a, b string) bool {
+	return strings.HasPrefix(a+"/", b+"/") || strings.HasPrefix(b+"/", a+"/")
+}
+
+// objectIDs returns the segments of an API path following a collection
+// segment, e.g. g and u of /api/v1/groups/g/users/u. Segments like the action
+// of a lifecycle path are returned as well, which only invalidates more.
+func objectIDs(path string) map[string]struct{} {
+	ids := make(map[string]struct{})
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(segments) > 2 && segments[0] == "api" {
+		segments = segments[2:]
+	}
+	for i := 1; i < len(segments); i += 2 {
+		if segments[i] != "" {
+			ids[segments[i]] = struct{}{}
+		}
+	}
+	return ids
+}
+
+func holdsObjectID(path string, ids map[string]struct{}) bool {
+	for _, segment := range strings.Split(path, "/") {
+		if _, ok := ids[segment]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package transport
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/okta/terraform-provider-okta/sdk/cache"
+)
+
+func TestCachingTransport(t *testing.T) {
+	calls := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls[r.Method+" "+r.URL.Path]++
+		fmt.Fprintf(w, `{"path":%q,"call":%d}`, r.URL.Path, calls[r.Method+" "+r.URL.Path])
+	}))
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: NewCachingTransport(http.DefaultTransport, cache.NewGoCache(60, 60), hclog.NewNullLogger()),
+	}
+	do := func(method, path string) string {
+		req, _ := http.NewRequest(method, server.URL+path, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	first := do(http.MethodGet, "/api/v1/groups/abc")
+	if second := do(http.MethodGet, "/api/v1/groups/abc"); second != first {
+		t.Errorf("expected cached response %q, got %q", first, second)
+	}
+	do(http.MethodGet, "/api/v1/groups")
+	do(http.MethodGet, "/api/v1/groups")
+	do(http.MethodGet, "/api/v1/users/xyz")
+	if calls["GET /api/v1/groups/abc"] != 1 || calls["GET /api/v1/groups"] != 1 {
+		t.Fatalf("expected one upstream call per path, got %+v", calls)
+	}
+
+	do(http.MethodPut, "/api/v1/groups/abc")
+	do(http.MethodGet, "/api/v1/groups/abc")
+	do(http.MethodGet, "/api/v1/groups")
+	do(http.MethodGet, "/api/v1/users/xyz")
+	if calls["GET /api/v1/groups/abc"] != 2 {
+		t.Errorf("expected mutated path to be invalidated, got %d calls", calls["GET /api/v1/groups/abc"])
+	}
+	if calls["GET /api/v1/groups"] != 2 {
+		t.Errorf("expected parent list path to be invalidated, got %d calls", calls["GET /api/v1/groups"])
+	}
+	if calls["GET /api/v1/users/xyz"] != 1 {
+		t.Errorf("expected unrelated path to remain cached, got %d calls", calls["GET /api/v1/users/xyz"])
+	}
+
+	do(http.MethodGet, "/api/v1/groups/abc/users")
+	do(http.MethodDelete, "/api/v1/groups/abc")
+	do(http.MethodGet, "/api/v1/groups/abc/users")
+	if calls["GET /api/v1/groups/abc/users"] != 2 {
+		t.Errorf("expected child path to be invalidated, got %d calls", calls["GET /api/v1/groups/abc/users"])
+	}
+
+	do(http.MethodGet, "/api/v1/users/xyz/groups")
+	do(http.MethodPut, "/api/v1/groups/def/users/xyz")
+	do(http.MethodGet, "/api/v1/users/xyz/groups")
+	if calls["GET /api/v1/users/xyz/groups"] != 2 {
+		t.Errorf("expected path holding the mutated user to be invalidated, got %d calls", calls["GET /api/v1/users/xyz/groups"])
+	}
+}
@@ -0,0 +1,240 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// lastUpdatedFields are the fields holding the modification time of an
+// object, v3 models of some endpoints use lastUpdate rather than lastUpdated.
+var lastUpdatedFields = []string{"lastUpdated", "lastUpdate"}
+
+type ConflictTransport struct {
+	base   http.RoundTripper
+	logger hclog.Logger
+}
+
+// UpdateConflictError is returned by the ConflictTransport for a PUT to an
+// object that changed since it was read with the same context.
+type UpdateConflictError struct {
+	URL         string
+	LastUpdated string
+	Current     string
+	Fields      []string
+}
+
+func (e *UpdateConflictError) Error() string {
+	msg := fmt.Sprintf("refused to overwrite %s, it was updated at %s after being read (last updated at %s)", e.URL, e.Current, e.LastUpdated)
+	if len(e.Fields) > 0 {
+		msg += fmt.Sprintf(", changed fields: %s", strings.Join(e.Fields, ", "))
+	}
+	return msg
+}
+
+// NewConflictTransport returns a transport that, for requests made with a
+// context from WithUpdateConflicts, records the lastUpdated of the objects
+// read and, before a PUT to an object read earlier, reads it again and
+// refuses the PUT with an UpdateConflictError if its lastUpdated moved.
+// Requests made with any other context are passed to the base round tripper
+// as they are.
+func NewConflictTransport(base http.RoundTripper, logger hclog.Logger) *ConflictTransport {
+	return &ConflictTransport{
+		base:   base,
+		logger: logger,
+	}
+}
+
+// RoundTrip records the objects read and checks the objects overwritten.
+func (t *ConflictTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	conflicts, ok := req.Context().Value(updateConflictsKey).(*UpdateConflicts)
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+	path := strings.TrimSuffix(req.URL.Path, "/")
+	switch req.Method {
+	case http.MethodGet:
+		resp, err := t.base.RoundTrip(req)
+		if err == nil {
+			conflicts.observe(path, resp)
+		}
+		return resp, err
+	case http.MethodPut:
+		if read := conflicts.get(path); read != nil {
+			if err := t.check(req, path, read); err != nil {
+				if req.Body != nil {
+					req.Body.Close()
+				}
+				t.logger.Warn(err.Error())
+				conflicts.add(err)
+				return nil, err
+			}
+		}
+		resp, err := t.base.RoundTrip(req)
+		if err == nil {
+			// the object now is the one just written, or unknown
+			conflicts.forget(path)
+			conflicts.observe(path, resp)
+		}
+		return resp, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// check reads the object again and returns an UpdateConflictError if it was
+// updated since it was read.
+func (t *ConflictTransport) check(req *http.Request, path string, read *readObject) *UpdateConflictError {
+	get, err := http.NewRequestWithContext(req.Context(), http.MethodGet, req.URL.String(), nil)
+	if err != nil {
+		return nil
+	}
+	get.Header = req.Header.Clone()
+	get.Header.Del("Content-Type")
+	resp, err := t.base.RoundTrip(get)
+	if err != nil {
+		t.logger.Warn(fmt.Sprintf("failed to read %s before overwriting it: %v", path, err))
+		return nil
+	}
+	current := readObjectFrom(resp)
+	if current == nil || current.lastUpdated == read.lastUpdated {
+		return nil
+	}
+	return &UpdateConflictError{
+		URL:         req.URL.String(),
+		LastUpdated: read.lastUpdated,
+		Current:     current.lastUpdated,
+		Fields:      changedFields("", read.body, current.body),
+	}
+}
+
+type readObject struct {
+	lastUpdated string
+	body        map[string]interface{}
+}
+
+// readObjectFrom returns the object of a successful response if it has a
+// lastUpdated field, the response body is left readable.
+func readObjectFrom(resp *http.Response) *readObject {
+	if resp.StatusCode != http.StatusOK || resp.Body == nil {
+		return nil
+	}
+	b, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+	if err != nil {
+		return nil
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(b, &body); err != nil {
+		return nil
+	}
+	for _, field := range lastUpdatedFields {
+		if lastUpdated, ok := body[field].(string); ok && lastUpdated != "" {
+			return &readObject{lastUpdated: lastUpdated, body: body}
+		}
+	}
+	return nil
+}
+
+// changedFields returns the paths of the fields that differ between two
+// versions of an object, ignoring the modification time and links.
+func changedFields(prefix string, a, b map[string]interface{}) []string {
+	keys := map[string]bool{}
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+	var fields []string
+	for k := range keys {
+		if prefix == "" && (k == "_links" || k == "_embedded" || contains(lastUpdatedFields, k)) {
+			continue
+		}
+		am, aIsMap := a[k].(map[string]interface{})
+		bm, bIsMap := b[k].(map[string]interface{})
+		switch {
+		case aIsMap && bIsMap:
+			fields = append(fields, changedFields(prefix+k+".", am, bm)...)
+		case !reflect.DeepEqual(a[k], b[k]):
+			fields = append(fields, prefix+k)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+type updateConflictsContextKey string
+
+const updateConflictsKey updateConflictsContextKey = "updateConflicts"
+
+// UpdateConflicts records the objects read and collects the PUT requests
+// refused by the ConflictTransport for requests made with a given context.
+type UpdateConflicts struct {
+	lock      sync.Mutex
+	read      map[string]*readObject
+	conflicts []*UpdateConflictError
+}
+
+// WithUpdateConflicts returns a context for which objects overwritten after
+// being read are checked for updates made in between.
+func WithUpdateConflicts(ctx context.Context) (context.Context, *UpdateConflicts) {
+	conflicts := &UpdateConflicts{read: map[string]*readObject{}}
+	return context.WithValue(ctx, updateConflictsKey, conflicts), conflicts
+}
+
+// Conflicts returns the refused PUT requests.
+func (c *UpdateConflicts) Conflicts() []*UpdateConflictError {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return append([]*UpdateConflictError{}, c.conflicts...)
+}
+
+func (c *UpdateConflicts) observe(path string, resp *http.Response) {
+	read := readObjectFrom(resp)
+	if read == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	// the first read is the one the update is based on
+	if _, ok := c.read[path]; !ok {
+		c.read[path] = read
+	}
+}
+
+func (c *UpdateConflicts) forget(path string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.read, path)
+}
+
+func (c *UpdateConflicts) get(path string) *readObject {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.read[path]
+}
+
+func (c *UpdateConflicts) add(err *UpdateConflictError) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.conflicts = append(c.conflicts, err)
+}
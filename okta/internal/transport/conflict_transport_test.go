@@ -0,0 +1,86 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+func TestConflictTransport(t *testing.T) {
+	var version, puts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			atomic.AddInt32(&puts, 1)
+			atomic.AddInt32(&version, 1)
+		}
+		v := atomic.LoadInt32(&version)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"id":"0oa1","label":"label %d","settings":{"app":{"url":"https://example.com"},"notes":"v%d"},"lastUpdated":"2023-01-0%dT00:00:00.000Z","_links":{"self":{"href":"v%d"}}}`, v, v, v+1, v)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewConflictTransport(http.DefaultTransport, hclog.NewNullLogger())}
+	do := func(ctx context.Context, method string) error {
+		req, _ := http.NewRequestWithContext(ctx, method, server.URL+"/api/v1/apps/0oa1", strings.NewReader("{}"))
+		resp, err := client.Do(req)
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	// without the context PUTs are passed as they are
+	if err := do(context.Background(), http.MethodGet); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := do(context.Background(), http.MethodPut); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// consecutive updates of an object read once are allowed
+	ctx, conflicts := WithUpdateConflicts(context.Background())
+	if err := do(ctx, http.MethodGet); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := do(ctx, http.MethodPut); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(conflicts.Conflicts()) != 0 {
+		t.Fatalf("expected no conflicts, got %v", conflicts.Conflicts())
+	}
+
+	// an update after the object was updated by someone else is refused
+	ctx, conflicts = WithUpdateConflicts(context.Background())
+	if err := do(ctx, http.MethodGet); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	atomic.AddInt32(&version, 1)
+	before := atomic.LoadInt32(&puts)
+	err := do(ctx, http.MethodPut)
+	var conflictErr *UpdateConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("expected an update conflict error, got %v", err)
+	}
+	if after := atomic.LoadInt32(&puts); after != before {
+		t.Errorf("expected the PUT to be refused, %d PUTs reached the server", after-before)
+	}
+	expected := []string{"label", "settings.notes"}
+	if !reflect.DeepEqual(conflictErr.Fields, expected) {
+		t.Errorf("expected changed fields %v, got %v", expected, conflictErr.Fields)
+	}
+	if got := conflicts.Conflicts(); len(got) != 1 || got[0] != conflictErr {
+		t.Errorf("expected the conflict to be recorded, got %v", got)
+	}
+}
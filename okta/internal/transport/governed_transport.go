@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/hashicorp/go-hclog"
 
@@ -19,21 +18,24 @@ const (
 )
 
 type GovernedTransport struct {
-	base     http.RoundTripper
-	apiMutex *apimutex.APIMutex
-	logger   hclog.Logger
+	base      http.RoundTripper
+	apiMutex  *apimutex.APIMutex
+	scheduler *scheduler
+	logger    hclog.Logger
 }
 
 // NewGovernedTransport returns a governed transport that relies on pre- and post-
-// requests from the http round tripper. The pre request consults the api mutex
-// to determine if sleeping for the Okta API one minute bucket is called for.
-// The post request updates the information it is holding about the current api
-// rate limits.
+// requests from the http round tripper. The pre request queues the request with
+// a scheduler that consults the api mutex to admit requests per Okta API rate
+// limit bucket, writes ahead of reads, in FIFO order. The post request updates
+// the information it is holding about the current api rate limits and releases
+// the request's slot in the scheduler.
 func NewGovernedTransport(base http.RoundTripper, apiMutex *apimutex.APIMutex, logger hclog.Logger) *GovernedTransport {
 	return &GovernedTransport{
-		base:     base,
-		apiMutex: apiMutex,
-		logger:   logger,
+		base:      base,
+		apiMutex:  apiMutex,
+		scheduler: newScheduler(apiMutex, realClock{}),
+		logger:    logger,
 	}
 }
 
@@ -48,6 +50,7 @@ func (t *GovernedTransport) RoundTrip(req *http.Request) (*http.Response, error)
 	resp, err := t.base.RoundTrip(req)
 	// always attempt to save x-headers
 	t.postRequestHook(req.Method, path, resp)
+	t.scheduler.release(req.Method, path)
 	if err != nil {
 		return nil, err
 	}
@@ -56,31 +59,24 @@ func (t *GovernedTransport) RoundTrip(req *http.Request) (*http.Response, error)
 }
 
 func (t *GovernedTransport) preRequestHook(ctx context.Context, method, path string) error {
-	if t.apiMutex.HasCapacity(method, path) {
-		return nil
-	}
-
-	status := t.apiMutex.Status(method, path)
-	now := time.Now().Unix()
-	timeToSleep := status.Reset() - now
+	return t.scheduler.acquire(ctx, method, path, func() {
+		status := t.apiMutex.Status(method, path)
+		timeToSleep := status.Reset() - t.scheduler.clock.Now().Unix()
+		if timeToSleep < 0 {
+			timeToSleep = 0
+		}
 
-	line := fmt.Sprintf("Throttling API requests; sleeping for %d seconds until rate limit reset (path class %q, bucket %q: %d remaining of %d total); current request \"%s %s\"",
-		timeToSleep,
-		t.apiMutex.Class(method, path),
-		t.apiMutex.Bucket(method, path),
-		status.Remaining(),
-		status.Limit(),
-		method,
-		path,
-	)
-	t.logger.Info(line)
-
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-time.NewTimer(time.Second * time.Duration(timeToSleep)).C:
-		return nil
-	}
+		line := fmt.Sprintf("Throttling API requests; queued for up to %d seconds until rate limit reset (path class %q, bucket %q: %d remaining of %d total); current request \"%s %s\"",
+			timeToSleep,
+			t.apiMutex.Class(method, path),
+			t.apiMutex.Bucket(method, path),
+			status.Remaining(),
+			status.Limit(),
+			method,
+			path,
+		)
+		t.logger.Info(line)
+	})
 }
 
 func (t *GovernedTransport) postRequestHook(method, path string, resp *http.Response) {
@@ -0,0 +1,118 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// permissionGatedEndpoints are GET endpoints known to respond 401
+// Unauthorized or 403 Forbidden to API tokens minted by admins with a lesser
+// role than Super Admin, or to orgs without the corresponding feature.
+var permissionGatedEndpoints = []*regexp.Regexp{
+	regexp.MustCompile(`^/api/v1/users/[^/]+/roles$`),
+	regexp.MustCompile(`^/api/v1/users/[^/]+/roles/[^/]+/targets/(groups|catalog/apps)$`),
+	regexp.MustCompile(`^/api/v1/groups/[^/]+/roles$`),
+	regexp.MustCompile(`^/api/v1/groups/[^/]+/roles/[^/]+/targets/(groups|catalog/apps)$`),
+	regexp.MustCompile(`^/api/v1/users/[^/]+/subscriptions$`),
+	regexp.MustCompile(`^/api/v1/roles/[^/]+/subscriptions$`),
+	regexp.MustCompile(`^/api/v1/iam/(roles|resource-sets)$`),
+	regexp.MustCompile(`^/api/v1/iam/(roles|resource-sets)/[^/]+(/.*)?$`),
+	regexp.MustCompile(`^/api/v1/meta/types/user/[^/]+$`),
+	regexp.MustCompile(`^/api/v1/org/privacy/oktaSupport$`),
+	regexp.MustCompile(`^/api/v1/org/captcha$`),
+	regexp.MustCompile(`^/api/v1/threats/configuration$`),
+	regexp.MustCompile(`^/api/v1/org/preferences$`),
+}
+
+type PermissionTransport struct {
+	base     http.RoundTripper
+	logger   hclog.Logger
+	softFail atomic.Bool
+}
+
+// NewPermissionTransport returns a transport that, when soft fail is enabled,
+// records 401 Unauthorized and 403 Forbidden responses from known permission
+// gated GET endpoints as warnings on the request's context, see
+// WithPermissionWarnings. The responses themselves are returned unchanged, it
+// is up to the read collecting the warnings to keep the prior state of the
+// attributes it couldn't read.
+func NewPermissionTransport(base http.RoundTripper, logger hclog.Logger) *PermissionTransport {
+	return &PermissionTransport{
+		base:   base,
+		logger: logger,
+	}
+}
+
+// SetSoftFail toggles recording permission errors as warnings. It is safe to
+// call after the transport is in use, e.g. once the API token's role is known.
+func (t *PermissionTransport) SetSoftFail(softFail bool) {
+	t.softFail.Store(softFail)
+}
+
+// RoundTrip returns the base round tripper's response, recording a warning
+// when it is a permission error on a known permission gated endpoint.
+func (t *PermissionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || !t.softFail.Load() || req.Method != http.MethodGet {
+		return resp, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusForbidden {
+		return resp, nil
+	}
+	if !permissionGated(req.URL.Path) {
+		return resp, nil
+	}
+
+	msg := fmt.Sprintf("Permission denied with %q on \"%s %s\", the API token's role does not have permission to read it", resp.Status, req.Method, req.URL.Path)
+	t.logger.Warn(msg)
+	if warnings, ok := req.Context().Value(permissionWarningsKey).(*PermissionWarnings); ok {
+		warnings.add(msg)
+	}
+	return resp, nil
+}
+
+func permissionGated(path string) bool {
+	for _, endpoint := range permissionGatedEndpoints {
+		if endpoint.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+type permissionWarningsContextKey string
+
+const permissionWarningsKey permissionWarningsContextKey = "permissionWarnings"
+
+// PermissionWarnings collects the permission errors recorded by the
+// PermissionTransport for requests made with a given context.
+type PermissionWarnings struct {
+	lock     sync.Mutex
+	messages []string
+}
+
+// WithPermissionWarnings returns a context that collects the permission errors
+// recorded for requests made with it.
+func WithPermissionWarnings(ctx context.Context) (context.Context, *PermissionWarnings) {
+	warnings := &PermissionWarnings{}
+	return context.WithValue(ctx, permissionWarningsKey, warnings), warnings
+}
+
+// Messages returns the collected warning messages.
+func (w *PermissionWarnings) Messages() []string {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return append([]string{}, w.messages...)
+}
+
+func (w *PermissionWarnings) add(msg string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.messages = append(w.messages, msg)
+}
@@ -0,0 +1,63 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+func TestPermissionTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"errorCode":"E0000006","errorSummary":"You do not have permission to perform the requested action"}`))
+	}))
+	defer server.Close()
+
+	transport := NewPermissionTransport(http.DefaultTransport, hclog.NewNullLogger())
+	client := &http.Client{Transport: transport}
+	do := func(ctx context.Context, method, path string) (int, string) {
+		req, _ := http.NewRequestWithContext(ctx, method, server.URL+path, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	if status, _ := do(context.Background(), http.MethodGet, "/api/v1/users/me/roles"); status != http.StatusForbidden {
+		t.Errorf("expected %d when soft fail is disabled, got %d", http.StatusForbidden, status)
+	}
+
+	transport.SetSoftFail(true)
+	ctx, warnings := WithPermissionWarnings(context.Background())
+	tests := []struct {
+		method string
+		path   string
+		status int
+		body   string
+	}{
+		{http.MethodGet, "/api/v1/users/00u1234/roles", http.StatusForbidden, "E0000006"},
+		{http.MethodGet, "/api/v1/iam/resource-sets/iam1234", http.StatusForbidden, "E0000006"},
+		{http.MethodPost, "/api/v1/users/00u1234/roles", http.StatusForbidden, "E0000006"},
+		{http.MethodGet, "/api/v1/users/00u1234", http.StatusForbidden, "E0000006"},
+	}
+	for _, test := range tests {
+		status, body := do(ctx, test.method, test.path)
+		if status != test.status {
+			t.Errorf("expected %d for \"%s %s\", got %d", test.status, test.method, test.path, status)
+		}
+		if !strings.Contains(body, test.body) {
+			t.Errorf("expected the error response for \"%s %s\" to be returned unchanged, got %q", test.method, test.path, body)
+		}
+	}
+	if len(warnings.Messages()) != 2 {
+		t.Errorf("expected 2 warnings, got %+v", warnings.Messages())
+	}
+}
@@ -0,0 +1,96 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// readOnlyAllowedPaths are non-GET endpoints that don't change the org, e.g.
+// the access token request made by the SDKs for private key authorization.
+var readOnlyAllowedPaths = map[string]bool{
+	"/oauth2/v1/token": true,
+}
+
+type ReadOnlyTransport struct {
+	base   http.RoundTripper
+	logger hclog.Logger
+}
+
+// RefusedRequestError is returned by the ReadOnlyTransport for a request that
+// could change the org.
+type RefusedRequestError struct {
+	Method string
+	URL    string
+}
+
+func (e *RefusedRequestError) Error() string {
+	return fmt.Sprintf("provider is read only, refused to make request \"%s %s\"", e.Method, e.URL)
+}
+
+// NewReadOnlyTransport returns a transport that only passes GET, HEAD and
+// OPTIONS requests to the base round tripper. Any other request is refused
+// with a RefusedRequestError, which is logged and recorded on the request's
+// context, see WithRefusedRequests.
+func NewReadOnlyTransport(base http.RoundTripper, logger hclog.Logger) *ReadOnlyTransport {
+	return &ReadOnlyTransport{
+		base:   base,
+		logger: logger,
+	}
+}
+
+// RoundTrip returns the base round tripper's response for requests that
+// can't change the org.
+func (t *ReadOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return t.base.RoundTrip(req)
+	}
+	if readOnlyAllowedPaths[req.URL.Path] {
+		return t.base.RoundTrip(req)
+	}
+
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	err := &RefusedRequestError{Method: req.Method, URL: req.URL.String()}
+	t.logger.Warn(err.Error())
+	if refused, ok := req.Context().Value(refusedRequestsKey).(*RefusedRequests); ok {
+		refused.add(err)
+	}
+	return nil, err
+}
+
+type refusedRequestsContextKey string
+
+const refusedRequestsKey refusedRequestsContextKey = "refusedRequests"
+
+// RefusedRequests collects the requests refused by the ReadOnlyTransport for
+// requests made with a given context.
+type RefusedRequests struct {
+	lock     sync.Mutex
+	requests []*RefusedRequestError
+}
+
+// WithRefusedRequests returns a context that collects the requests refused
+// for it.
+func WithRefusedRequests(ctx context.Context) (context.Context, *RefusedRequests) {
+	refused := &RefusedRequests{}
+	return context.WithValue(ctx, refusedRequestsKey, refused), refused
+}
+
+// Requests returns the refused requests.
+func (r *RefusedRequests) Requests() []*RefusedRequestError {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]*RefusedRequestError{}, r.requests...)
+}
+
+func (r *RefusedRequests) add(err *RefusedRequestError) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.requests = append(r.requests, err)
+}
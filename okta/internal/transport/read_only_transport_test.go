@@ -0,0 +1,60 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+func TestReadOnlyTransport(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewReadOnlyTransport(http.DefaultTransport, hclog.NewNullLogger())}
+	ctx, refused := WithRefusedRequests(context.Background())
+	tests := []struct {
+		method  string
+		path    string
+		refused bool
+	}{
+		{http.MethodGet, "/api/v1/groups/00g1234", false},
+		{http.MethodPost, "/oauth2/v1/token?grant_type=client_credentials", false},
+		{http.MethodPost, "/api/v1/groups", true},
+		{http.MethodPut, "/api/v1/groups/00g1234", true},
+		{http.MethodDelete, "/api/v1/groups/00g1234/users/00u1234", true},
+	}
+	for _, test := range tests {
+		req, _ := http.NewRequestWithContext(ctx, test.method, server.URL+test.path, strings.NewReader("{}"))
+		resp, err := client.Do(req)
+		var refusedErr *RefusedRequestError
+		switch {
+		case test.refused && !errors.As(err, &refusedErr):
+			t.Errorf("expected \"%s %s\" to be refused, got %v", test.method, test.path, err)
+		case !test.refused && err != nil:
+			t.Errorf("unexpected error for \"%s %s\": %v", test.method, test.path, err)
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests to reach the server, got %d", requests)
+	}
+	got := refused.Requests()
+	if len(got) != 3 {
+		t.Fatalf("expected 3 refused requests, got %+v", got)
+	}
+	if got[0].Method != http.MethodPost || got[0].URL != server.URL+"/api/v1/groups" {
+		t.Errorf("unexpected refused request %+v", got[0])
+	}
+}
@@ -0,0 +1,249 @@
+package transport
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/okta/terraform-provider-okta/okta/internal/apimutex"
+)
+
+// clock abstracts time so the scheduler can be driven by a fake clock in
+// tests.
+type clock interface {
+	Now() time.Time
+	AfterFunc(d time.Duration, f func()) stopper
+}
+
+type stopper interface {
+	Stop() bool
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) stopper {
+	return time.AfterFunc(d, f)
+}
+
+// scheduler admits requests per Okta API rate limit bucket. Each bucket has a
+// FIFO queue for writes and one for reads; writes are always admitted ahead
+// of reads. A request is admitted while the bucket's known consumption, plus
+// requests already admitted but not yet completed, is below the api mutex's
+// capacity. Waiters are woken by completed requests or, when the bucket is
+// exhausted, by a single jittered timer at the bucket's reset time rather than
+// each goroutine sleeping on its own.
+type scheduler struct {
+	apiMutex *apimutex.APIMutex
+	clock    clock
+	jitter   time.Duration
+	lock     sync.Mutex
+	queues   map[string]*bucketQueue
+}
+
+type bucketQueue struct {
+	writes   []*waiter
+	reads    []*waiter
+	inflight int
+	// window and admittedInWindow account for requests admitted after the
+	// known reset time has passed but before a response from the new one
+	// minute window has updated the api mutex.
+	window           int64
+	admittedInWindow int
+	timer            stopper
+}
+
+type waiter struct {
+	method   string
+	path     string
+	ready    chan struct{}
+	admitted bool
+}
+
+func newScheduler(apiMutex *apimutex.APIMutex, c clock) *scheduler {
+	return &scheduler{
+		apiMutex: apiMutex,
+		clock:    c,
+		jitter:   time.Second,
+		queues:   map[string]*bucketQueue{},
+	}
+}
+
+// acquire blocks until the request is admitted or the context is done. onWait
+// is called when the request is queued rather than admitted immediately.
+func (s *scheduler) acquire(ctx context.Context, method, path string, onWait func()) error {
+	s.lock.Lock()
+	q := s.queue(method, path)
+	w := &waiter{
+		method: method,
+		path:   path,
+		ready:  make(chan struct{}),
+	}
+	if isWrite(method) {
+		q.writes = append(q.writes, w)
+	} else {
+		q.reads = append(q.reads, w)
+	}
+	s.dispatch(q)
+	if w.admitted {
+		s.lock.Unlock()
+		return nil
+	}
+	s.lock.Unlock()
+	if onWait != nil {
+		onWait()
+	}
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		if w.admitted {
+			q.inflight--
+		} else {
+			q.writes = removeWaiter(q.writes, w)
+			q.reads = removeWaiter(q.reads, w)
+		}
+		s.dispatch(q)
+		return ctx.Err()
+	}
+}
+
+// release marks an admitted request as completed and admits any waiters the
+// bucket now has room for.
+func (s *scheduler) release(method, path string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	q := s.queue(method, path)
+	if q.inflight > 0 {
+		q.inflight--
+	}
+	s.dispatch(q)
+}
+
+func (s *scheduler) queue(method, path string) *bucketQueue {
+	bucket := s.apiMutex.Bucket(method, path)
+	q, ok := s.queues[bucket]
+	if !ok {
+		q = &bucketQueue{}
+		s.queues[bucket] = q
+	}
+	return q
+}
+
+// dispatch admits waiters in priority order until the bucket is out of
+// capacity. It must be called with the scheduler lock held.
+func (s *scheduler) dispatch(q *bucketQueue) {
+	for {
+		w := q.head()
+		if w == nil {
+			return
+		}
+		if !s.hasCapacity(q, w) {
+			s.wakeAtReset(q, w)
+			return
+		}
+		q.pop()
+		w.admitted = true
+		q.inflight++
+		close(w.ready)
+	}
+}
+
+func (s *scheduler) hasCapacity(q *bucketQueue, w *waiter) bool {
+	status := s.apiMutex.Status(w.method, w.path)
+	now := s.clock.Now().Unix()
+	limit := status.Limit()
+
+	// nothing is known about the bucket, or the status hasn't been updated
+	// recently; assume there is capacity
+	if limit == 0 || status.Reset()+60 < now {
+		return true
+	}
+
+	var used int
+	if now >= status.Reset() {
+		// the one minute window has rolled over, only count what has been
+		// admitted since
+		if q.window != status.Reset() {
+			q.window = status.Reset()
+			q.admittedInWindow = 0
+		}
+		used = q.admittedInWindow
+	} else {
+		used = limit - status.Remaining() + q.inflight
+	}
+
+	if used > 0 && used*100 >= s.apiMutex.Capacity()*limit {
+		return false
+	}
+	if now >= status.Reset() {
+		q.admittedInWindow++
+	}
+	return true
+}
+
+// wakeAtReset arms a single timer for the bucket so that waiters are
+// re-evaluated shortly after the bucket's reset time. The wake up is jittered
+// so buckets don't all wake on the same second.
+func (s *scheduler) wakeAtReset(q *bucketQueue, w *waiter) {
+	if q.timer != nil {
+		return
+	}
+	wait := time.Duration(s.apiMutex.Status(w.method, w.path).Reset()-s.clock.Now().Unix()) * time.Second
+	if wait <= 0 {
+		wait = time.Second
+	}
+	if s.jitter > 0 {
+		wait += time.Duration(rand.Int63n(int64(s.jitter)))
+	}
+	q.timer = s.clock.AfterFunc(wait, func() {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		q.timer = nil
+		s.dispatch(q)
+	})
+}
+
+func (q *bucketQueue) head() *waiter {
+	if len(q.writes) > 0 {
+		return q.writes[0]
+	}
+	if len(q.reads) > 0 {
+		return q.reads[0]
+	}
+	return nil
+}
+
+func (q *bucketQueue) pop() {
+	if len(q.writes) > 0 {
+		q.writes = q.writes[1:]
+		return
+	}
+	q.reads = q.reads[1:]
+}
+
+func removeWaiter(waiters []*waiter, w *waiter) []*waiter {
+	for i, candidate := range waiters {
+		if candidate == w {
+			return append(waiters[:i], waiters[i+1:]...)
+		}
+	}
+	return waiters
+}
+
+func isWrite(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,217 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/okta/terraform-provider-okta/okta/internal/apimutex"
+)
+
+type fakeClock struct {
+	lock   sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	at      time.Time
+	f       func()
+	stopped bool
+}
+
+func (t *fakeTimer) Stop() bool {
+	stopped := t.stopped
+	t.stopped = true
+	return !stopped
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) stopper {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	t := &fakeTimer{at: c.now.Add(d), f: f}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// Advance moves the clock forward and runs any timers that are due.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	c.now = c.now.Add(d)
+	var due, pending []*fakeTimer
+	for _, t := range c.timers {
+		if !t.stopped && !t.at.After(c.now) {
+			due = append(due, t)
+		} else if !t.stopped {
+			pending = append(pending, t)
+		}
+	}
+	c.timers = pending
+	c.lock.Unlock()
+	for _, t := range due {
+		t.f()
+	}
+}
+
+// fakeBucket simulates the Okta API's accounting of a single rate limit bucket
+// and records how many requests were served in each one minute window.
+type fakeBucket struct {
+	lock      sync.Mutex
+	clock     *fakeClock
+	limit     int
+	remaining int
+	reset     int64
+	served    map[int64]int
+	order     []string
+}
+
+func (b *fakeBucket) serve(name string) (remaining int, reset int64) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	now := b.clock.Now().Unix()
+	if now >= b.reset {
+		b.reset = now + 60
+		b.remaining = b.limit
+	}
+	b.remaining--
+	b.served[b.reset]++
+	b.order = append(b.order, name)
+	return b.remaining, b.reset
+}
+
+func newTestScheduler(t *testing.T, capacity, limit, remaining int) (*scheduler, *fakeClock, *fakeBucket) {
+	apiMutex, err := apimutex.NewAPIMutex(capacity)
+	if err != nil {
+		t.Fatalf("api mutex constructor had error %+v", err)
+	}
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	bucket := &fakeBucket{
+		clock:     clock,
+		limit:     limit,
+		remaining: remaining,
+		reset:     clock.Now().Unix() + 60,
+		served:    map[int64]int{},
+	}
+	bucket.served[bucket.reset] = limit - remaining
+	apiMutex.Update(http.MethodGet, "/api/v1/users", limit, remaining, bucket.reset)
+	return newScheduler(apiMutex, clock), clock, bucket
+}
+
+// do runs a request through the scheduler against the fake bucket.
+func do(t *testing.T, s *scheduler, bucket *fakeBucket, method, name string, wg *sync.WaitGroup) {
+	defer wg.Done()
+	path := "/api/v1/users"
+	if err := s.acquire(context.Background(), method, path, nil); err != nil {
+		t.Errorf("unexpected error %+v", err)
+		return
+	}
+	remaining, reset := bucket.serve(name)
+	s.apiMutex.Update(method, path, bucket.limit, remaining, reset)
+	s.release(method, path)
+}
+
+// waitFor polls the scheduler until the given number of requests are queued
+// and none are in flight.
+func waitFor(t *testing.T, s *scheduler, queued int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		s.lock.Lock()
+		q := s.queue(http.MethodGet, "/api/v1/users")
+		done := len(q.writes)+len(q.reads) == queued && q.inflight == 0
+		s.lock.Unlock()
+		if done {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d queued requests", queued)
+}
+
+func TestSchedulerNeverExceedsBucketShare(t *testing.T) {
+	capacity, limit := 50, 10
+	share := limit * capacity / 100
+	s, clock, bucket := newTestScheduler(t, capacity, limit, limit)
+
+	requests := 23
+	var wg sync.WaitGroup
+	wg.Add(requests)
+	for i := 0; i < requests; i++ {
+		go do(t, s, bucket, http.MethodGet, "read", &wg)
+	}
+
+	for queued := requests - share; queued > 0; queued -= share {
+		waitFor(t, s, queued)
+		clock.Advance(61 * time.Second)
+	}
+	wg.Wait()
+
+	total := 0
+	for reset, served := range bucket.served {
+		if served > share {
+			t.Errorf("window %d served %d requests, exceeding share of %d", reset, served, share)
+		}
+		total += served
+	}
+	if total != requests {
+		t.Errorf("expected %d requests to be served, got %d", requests, total)
+	}
+}
+
+func TestSchedulerAdmitsWritesBeforeReadsInFIFOOrder(t *testing.T) {
+	capacity, limit := 10, 10
+	// the bucket is already at capacity so every request is queued
+	s, clock, bucket := newTestScheduler(t, capacity, limit, limit-1)
+
+	var wg sync.WaitGroup
+	requests := []struct {
+		method string
+		name   string
+	}{
+		{http.MethodGet, "read-1"},
+		{http.MethodGet, "read-2"},
+		{http.MethodPost, "write-1"},
+		{http.MethodGet, "read-3"},
+		{http.MethodPost, "write-2"},
+	}
+	for i, r := range requests {
+		wg.Add(1)
+		go do(t, s, bucket, r.method, r.name, &wg)
+		waitFor(t, s, i+1)
+	}
+	for queued := len(requests) - 1; queued >= 0; queued-- {
+		clock.Advance(61 * time.Second)
+		waitFor(t, s, queued)
+	}
+	wg.Wait()
+
+	expected := []string{"write-1", "write-2", "read-1", "read-2", "read-3"}
+	for i, name := range expected {
+		if bucket.order[i] != name {
+			t.Fatalf("expected admission order %v, got %v", expected, bucket.order)
+		}
+	}
+}
+
+func TestSchedulerCanceledWaiterIsRemoved(t *testing.T) {
+	s, _, _ := newTestScheduler(t, 10, 10, 9)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error)
+	go func() {
+		errs <- s.acquire(ctx, http.MethodGet, "/api/v1/users", nil)
+	}()
+	waitFor(t, s, 1)
+	cancel()
+	if err := <-errs; err != context.Canceled {
+		t.Fatalf("expected %v error, got %+v", context.Canceled, err)
+	}
+	waitFor(t, s, 0)
+}
@@ -0,0 +1,107 @@
+package okta
+
+import (
+	"context"
+	"sync"
+
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+// Maximum page sizes, the limit query parameter, of list endpoints used when
+// reading everything the endpoint has to offer. See
+// https://developer.okta.com/docs/reference/core-okta-api/
+const (
+	maxAppsPageSize       int64 = 200
+	maxAppUsersPageSize   int64 = 500
+	maxGroupsPageSize     int64 = 10000
+	maxGroupUsersPageSize int64 = 1000
+	maxUsersPageSize      int64 = 200
+)
+
+// forEachPage streams the items of a paged v2 SDK list endpoint to fn. first
+// makes the request for the first page and later pages are requested by
+// following the response's next link. Paging stops when there are no more
+// pages, a page is empty, or fn returns false, e.g. once every ID being
+// looked for has been found.
+func forEachPage[T any](ctx context.Context, first func() ([]T, *sdk.Response, error), fn func(T) bool) error {
+	items, resp, err := first()
+	if err != nil {
+		return err
+	}
+	for {
+		for _, item := range items {
+			if !fn(item) {
+				return nil
+			}
+		}
+		if len(items) == 0 || resp == nil || !resp.HasNextPage() {
+			return nil
+		}
+		var next []T
+		resp, err = resp.Next(ctx, &next)
+		if err != nil {
+			return err
+		}
+		items = next
+	}
+}
+
+// collectPages returns the items of every page of a paged v2 SDK list
+// endpoint, see forEachPage.
+func collectPages[T any](ctx context.Context, first func() ([]T, *sdk.Response, error)) ([]T, error) {
+	var result []T
+	err := forEachPage(ctx, first, func(item T) bool {
+		result = append(result, item)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// forEachParallel calls fn for 0 to n-1 with no more concurrent calls than the
+// provider's parallelism setting. It is intended for independent lookups such
+// as reading the groups of each user in a list; fn should write its result
+// into the i-th element of a pre-sized slice. The first error cancels the
+// context passed to the remaining calls and is returned.
+func forEachParallel(ctx context.Context, m interface{}, n int, fn func(ctx context.Context, i int) error) error {
+	parallelism := 1
+	if config, ok := m.(*Config); ok && config.parallelism > 1 {
+		parallelism = config.parallelism
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, parallelism)
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := fn(ctx, i); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(i)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,142 @@
+package okta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/okta/terraform-provider-okta/sdk/query"
+)
+
+// newPagedGroupUsersServer serves the users of group "g1" two per page and
+// counts the requests made.
+func newPagedGroupUsersServer(t *testing.T, users int) (*sdk.Client, *int32) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		after, _ := strconv.Atoi(r.URL.Query().Get("after"))
+		end := after + 2
+		if end > users {
+			end = users
+		}
+		if end < users {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/api/v1/groups/g1/users?after=%d&limit=2>; rel="next"`, r.Host, end))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		body := "["
+		for i := after; i < end; i++ {
+			if i > after {
+				body += ","
+			}
+			body += fmt.Sprintf(`{"id":"u%d"}`, i)
+		}
+		_, _ = w.Write([]byte(body + "]"))
+	}))
+	t.Cleanup(server.Close)
+
+	_, client, err := sdk.NewClient(
+		context.Background(),
+		sdk.WithOrgUrl(server.URL),
+		sdk.WithToken("token"),
+		sdk.WithAuthorizationMode("SSWS"),
+		sdk.WithCache(false),
+		sdk.WithTestingDisableHttpsCheck(true),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client, &requests
+}
+
+func TestForEachPage(t *testing.T) {
+	ctx := context.Background()
+	first := func(client *sdk.Client) func() ([]*sdk.User, *sdk.Response, error) {
+		return func() ([]*sdk.User, *sdk.Response, error) {
+			return client.Group.ListGroupUsers(ctx, "g1", &query.Params{Limit: 2})
+		}
+	}
+
+	client, requests := newPagedGroupUsersServer(t, 5)
+	users, err := collectPages(ctx, first(client))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(users) != 5 || users[4].Id != "u4" {
+		t.Errorf("expected 5 users, got %d", len(users))
+	}
+	if *requests != 3 {
+		t.Errorf("expected 3 requests, got %d", *requests)
+	}
+
+	client, requests = newPagedGroupUsersServer(t, 5)
+	var seen []string
+	err = forEachPage(ctx, first(client), func(user *sdk.User) bool {
+		seen = append(seen, user.Id)
+		return user.Id != "u2"
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(seen) != 3 {
+		t.Errorf("expected paging to stop after 3 users, got %v", seen)
+	}
+	if *requests != 2 {
+		t.Errorf("expected 2 requests, got %d", *requests)
+	}
+}
+
+func TestForEachParallel(t *testing.T) {
+	config := &Config{parallelism: 3}
+
+	var inflight, maxInflight int32
+	var lock sync.Mutex
+	results := make([]int, 20)
+	err := forEachParallel(context.Background(), config, len(results), func(ctx context.Context, i int) error {
+		n := atomic.AddInt32(&inflight, 1)
+		defer atomic.AddInt32(&inflight, -1)
+		lock.Lock()
+		if n > maxInflight {
+			maxInflight = n
+		}
+		lock.Unlock()
+		time.Sleep(time.Millisecond)
+		results[i] = i * i
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if maxInflight > 3 {
+		t.Errorf("expected at most 3 concurrent calls, got %d", maxInflight)
+	}
+	for i, result := range results {
+		if result != i*i {
+			t.Fatalf("expected result %d at %d, got %d", i*i, i, result)
+		}
+	}
+
+	var calls int32
+	failure := errors.New("failure")
+	err = forEachParallel(context.Background(), config, 100, func(ctx context.Context, i int) error {
+		atomic.AddInt32(&calls, 1)
+		if i == 0 {
+			return failure
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err != failure {
+		t.Errorf("expected %v, got %v", failure, err)
+	}
+	if calls == 100 {
+		t.Errorf("expected the first error to stop remaining calls")
+	}
+}
@@ -289,26 +289,28 @@ func findSystemPolicyByType(ctx context.Context, m interface{}, _type string) (*
 }
 
 func findPolicyByNameAndType(ctx context.Context, m interface{}, name, policyType string) (*sdk.Policy, error) {
-	policies, resp, err := getOktaClientFromMetadata(m).Policy.ListPolicies(ctx, &query.Params{Type: policyType})
+	policies, err := listPolicies(ctx, m, policyType)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list policies: %v", err)
 	}
-	for {
-		for _, _policy := range policies {
-			policy := _policy.(*sdk.Policy)
-			if policy.Name == name {
-				return policy, nil
-			}
-		}
-		if resp.HasNextPage() {
-			resp, err = resp.Next(ctx, &policies)
-			if err != nil {
-				return nil, fmt.Errorf("failed to list policies: %v", err)
-			}
-			continue
-		} else {
-			break
+	for _, policy := range policies {
+		if policy.Name == name {
+			return policy, nil
 		}
 	}
 	return nil, fmt.Errorf("no policies retrieved for policy type '%s' and name '%s'", policyType, name)
 }
+
+func listPolicies(ctx context.Context, m interface{}, policyType string) ([]*sdk.Policy, error) {
+	return collectPages(ctx, func() ([]*sdk.Policy, *sdk.Response, error) {
+		_policies, resp, err := getOktaClientFromMetadata(m).Policy.ListPolicies(ctx, &query.Params{Type: policyType})
+		if err != nil {
+			return nil, resp, err
+		}
+		policies := make([]*sdk.Policy, len(_policies))
+		for i := range _policies {
+			policies[i] = _policies[i].(*sdk.Policy)
+		}
+		return policies, resp, nil
+	})
+}
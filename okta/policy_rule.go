@@ -39,7 +39,7 @@ var (
 		"priority": {
 			Type:        schema.TypeInt,
 			Optional:    true,
-			Description: "Policy Rule Priority, this attribute can be set to a valid priority. To avoid endless diff situation we error if an invalid priority is provided. API defaults it to the last (lowest) if not there.",
+			Description: "Policy Rule Priority, this attribute can be set to a valid priority. To avoid endless diff situation we error if an invalid priority is provided. API defaults it to the last (lowest) if not there. Omit it when the policy's rules are ordered by okta_policy_rule_order.",
 			// Suppress diff if config is empty.
 			DiffSuppressFunc: createValueDiffSuppression("0"),
 		},
@@ -238,13 +238,23 @@ func updateRule(ctx context.Context, d *schema.ResourceData, m interface{}, temp
 	if err != nil {
 		return err
 	}
-	err = validatePriority(template.Priority, rule.Priority)
-	if err != nil {
-		return err
+	if policyRulePriorityConfigured(d) {
+		err = validatePriority(template.Priority, rule.Priority)
+		if err != nil {
+			return err
+		}
 	}
 	return policyRuleActivate(ctx, d, m)
 }
 
+// policyRulePriorityConfigured is false when the rule's priority is left to
+// the API or to an okta_policy_rule_order resource ordering the policy's
+// rules, in which case the rule keeps the priority it has and the API
+// shifting it isn't an error.
+func policyRulePriorityConfigured(d *schema.ResourceData) bool {
+	return !d.GetRawConfig().GetAttr("priority").IsNull()
+}
+
 // activate or deactivate a policy rule according to the terraform schema status field
 func policyRuleActivate(ctx context.Context, d *schema.ResourceData, m interface{}) error {
 	client := getOktaClientFromMetadata(m).Policy
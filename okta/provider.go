@@ -11,12 +11,15 @@ import (
 	"github.com/cenkalti/backoff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	"github.com/okta/terraform-provider-okta/okta/internal/mutexkv"
+	"github.com/okta/terraform-provider-okta/okta/internal/transport"
 )
 
 // Resource names, defined in place, used throughout the provider and tests
 const (
+	adminRoleAssignments          = "okta_admin_role_assignments"
 	adminRoleCustom               = "okta_admin_role_custom"
 	adminRoleCustomAssignments    = "okta_admin_role_custom_assignments"
 	adminRoleTargets              = "okta_admin_role_targets"
@@ -44,15 +47,19 @@ const (
 	appUserBaseSchemaProperty     = "okta_app_user_base_schema_property"
 	appUserSchemaProperty         = "okta_app_user_schema_property"
 	authenticator                 = "okta_authenticator"
+	authenticatorMethod           = "okta_authenticator_method"
 	authServer                    = "okta_auth_server"
 	authServerClaim               = "okta_auth_server_claim"
 	authServerClaimDefault        = "okta_auth_server_claim_default"
 	authServerClaims              = "okta_auth_server_claims"
 	authServerDefault             = "okta_auth_server_default"
+	authServerKeyRotation         = "okta_auth_server_key_rotation"
+	authServerKeys                = "okta_auth_server_keys"
 	authServerPolicy              = "okta_auth_server_policy"
 	authServerPolicyRule          = "okta_auth_server_policy_rule"
 	authServerScope               = "okta_auth_server_scope"
 	authServerScopes              = "okta_auth_server_scopes"
+	authServerTokenPreview        = "okta_auth_server_token_preview"
 	behavior                      = "okta_behavior"
 	behaviors                     = "okta_behaviors"
 	brand                         = "okta_brand"
@@ -88,6 +95,7 @@ const (
 	idpSamlKey                    = "okta_idp_saml_key"
 	idpSocial                     = "okta_idp_social"
 	inlineHook                    = "okta_inline_hook"
+	inlineHookPreview             = "okta_inline_hook_preview"
 	linkDefinition                = "okta_link_definition"
 	linkValue                     = "okta_link_value"
 	networkZone                   = "okta_network_zone"
@@ -102,6 +110,7 @@ const (
 	policyProfileEnrollmentApps   = "okta_policy_profile_enrollment_apps"
 	policyRuleIdpDiscovery        = "okta_policy_rule_idp_discovery"
 	policyRuleMfa                 = "okta_policy_rule_mfa"
+	policyRuleOrder               = "okta_policy_rule_order"
 	policyRulePassword            = "okta_policy_rule_password"
 	policyRuleProfileEnrollment   = "okta_policy_rule_profile_enrollment"
 	policyRuleSignOn              = "okta_policy_rule_signon"
@@ -111,6 +120,7 @@ const (
 	resourceSet                   = "okta_resource_set"
 	roleSubscription              = "okta_role_subscription"
 	securityNotificationEmails    = "okta_security_notification_emails"
+	systemLogs                    = "okta_system_logs"
 	templateSms                   = "okta_template_sms"
 	theme                         = "okta_theme"
 	themes                        = "okta_themes"
@@ -124,6 +134,7 @@ const (
 	userGroupMemberships          = "okta_user_group_memberships"
 	userProfileMappingSource      = "okta_user_profile_mapping_source"
 	users                         = "okta_users"
+	userSchema                    = "okta_user_schema"
 	userSchemaProperty            = "okta_user_schema_property"
 	userSecurityQuestions         = "okta_user_security_questions"
 	userType                      = "okta_user_type"
@@ -135,7 +146,7 @@ var oktaMutexKV = mutexkv.NewMutexKV()
 // Provider establishes a client connection to an okta site
 // determined by its schema string values
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"org_name": {
 				Type:        schema.TypeString,
@@ -235,8 +246,36 @@ func Provider() *schema.Provider {
 				ValidateDiagFunc: intBetween(0, 300),
 				Description:      "Timeout for single request (in seconds) which is made to Okta, the default is `0` (means no limit is set). The maximum value can be `300`.",
 			},
+			"api_token_role": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: "The admin role of the admin who minted the API token, e.g. `SUPER_ADMIN` or `ORG_ADMIN`. When the role is not " +
+					"`SUPER_ADMIN`, 401 and 403 errors reading known permission gated endpoints (e.g. admin roles) are reported " +
+					"as warnings and the prior state is kept rather than failing the run. When not set, the role is detected with GET /api/v1/users/me/roles.",
+			},
+			"request_cache_ttl": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ValidateDiagFunc: intBetween(0, 3600),
+				Description: "Time (in seconds) successful GET responses are cached for the duration of a single terraform command. " +
+					"A POST, PUT or DELETE to a path invalidates its cached responses and those of its parent paths, child paths and of paths holding its object IDs. The default is `0` (caching is disabled).",
+			},
+			"read_only": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Description: "Refuse any request to the Okta API that could change the org, i.e. anything other than a GET. " +
+					"A create, update or delete fails with an error naming the resource and the requests it would have made. The default is `false`.",
+			},
+			"detect_update_conflicts": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Description: "Refuse to update an object that was changed outside of Terraform since it was last read. Before an update the object " +
+					"is read again and the update fails naming the attributes that differ from the state, a PUT is also refused if the " +
+					"object's lastUpdated moved since the update read it. The default is `false`.",
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
+			adminRoleAssignments:          resourceAdminRoleAssignments(),
 			adminRoleCustom:               resourceAdminRoleCustom(),
 			adminRoleCustomAssignments:    resourceAdminRoleCustomAssignments(),
 			adminRoleTargets:              resourceAdminRoleTargets(),
@@ -258,13 +297,16 @@ func Provider() *schema.Provider {
 			appSwa:                        resourceAppSwa(),
 			appThreeField:                 resourceAppThreeField(),
 			appUser:                       resourceAppUser(),
+			appUserAssignments:            resourceAppUserAssignments(),
 			appUserBaseSchemaProperty:     resourceAppUserBaseSchemaProperty(),
 			appUserSchemaProperty:         resourceAppUserSchemaProperty(),
 			authenticator:                 resourceAuthenticator(),
+			authenticatorMethod:           resourceAuthenticatorMethod(),
 			authServer:                    resourceAuthServer(),
 			authServerClaim:               resourceAuthServerClaim(),
 			authServerClaimDefault:        resourceAuthServerClaimDefault(),
 			authServerDefault:             resourceAuthServerDefault(),
+			authServerKeyRotation:         resourceAuthServerKeyRotation(),
 			authServerPolicy:              resourceAuthServerPolicy(),
 			authServerPolicyRule:          resourceAuthServerPolicyRule(),
 			authServerScope:               resourceAuthServerScope(),
@@ -307,6 +349,7 @@ func Provider() *schema.Provider {
 			policyProfileEnrollmentApps:   resourcePolicyProfileEnrollmentApps(),
 			policyRuleIdpDiscovery:        resourcePolicyRuleIdpDiscovery(),
 			policyRuleMfa:                 resourcePolicyMfaRule(),
+			policyRuleOrder:               resourcePolicyRuleOrder(),
 			policyRulePassword:            resourcePolicyPasswordRule(),
 			policyRuleProfileEnrollment:   resourcePolicyProfileEnrollmentRule(),
 			policyRuleSignOn:              resourcePolicySignOnRule(),
@@ -325,10 +368,12 @@ func Provider() *schema.Provider {
 			userBaseSchemaProperty:        resourceUserBaseSchemaProperty(),
 			userFactorQuestion:            resourceUserFactorQuestion(),
 			userGroupMemberships:          resourceUserGroupMemberships(),
+			userSchema:                    resourceUserSchema(),
 			userSchemaProperty:            resourceUserCustomSchemaProperty(),
 			userType:                      resourceUserType(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
+			adminRoleAssignments:     dataSourceAdminRoleAssignments(),
 			app:                      dataSourceApp(),
 			appGroupAssignments:      dataSourceAppGroupAssignments(),
 			appMetadataSaml:          dataSourceAppMetadataSaml(),
@@ -340,8 +385,10 @@ func Provider() *schema.Provider {
 			authServer:               dataSourceAuthServer(),
 			authServerClaim:          dataSourceAuthServerClaim(),
 			authServerClaims:         dataSourceAuthServerClaims(),
+			authServerKeys:           dataSourceAuthServerKeys(),
 			authServerPolicy:         dataSourceAuthServerPolicy(),
 			authServerScopes:         dataSourceAuthServerScopes(),
+			authServerTokenPreview:   dataSourceAuthServerTokenPreview(),
 			behavior:                 dataSourceBehavior(),
 			behaviors:                dataSourceBehaviors(),
 			brand:                    dataSourceBrand(),
@@ -360,9 +407,11 @@ func Provider() *schema.Provider {
 			idpOidc:                  dataSourceIdpOidc(),
 			idpSaml:                  dataSourceIdpSaml(),
 			idpSocial:                dataSourceIdpSocial(),
+			inlineHookPreview:        dataSourceInlineHookPreview(),
 			networkZone:              dataSourceNetworkZone(),
 			policy:                   dataSourcePolicy(),
 			roleSubscription:         dataSourceRoleSubscription(),
+			systemLogs:               dataSourceSystemLogs(),
 			theme:                    dataSourceTheme(),
 			themes:                   dataSourceThemes(),
 			trustedOrigins:           dataSourceTrustedOrigins(),
@@ -374,6 +423,100 @@ func Provider() *schema.Provider {
 		},
 		ConfigureContextFunc: providerConfigure,
 	}
+
+	for name, resource := range provider.ResourcesMap {
+		// update conflict detection reads with the unwrapped read function
+		resource.UpdateContext = withRefusedRequests(name, "update", withUpdateConflictDetection(name, resource))
+		resource.CreateContext = withRefusedRequests(name, "create", resource.CreateContext)
+		resource.ReadContext = withRefusedRequests(name, "read", readWithPermissionWarnings(resource))
+		resource.DeleteContext = withRefusedRequests(name, "delete", resource.DeleteContext)
+	}
+	for name, dataSource := range provider.DataSourcesMap {
+		dataSource.ReadContext = withRefusedRequests(name, "read", readWithPermissionWarnings(dataSource))
+	}
+
+	return provider
+}
+
+// readWithPermissionWarnings surfaces permission errors on known endpoints
+// recorded by the http transport during a read as warning diagnostics. The
+// errors of the read are dropped and the prior state is kept as is, rather
+// than the attributes that couldn't be read being emptied. A read without a
+// prior state, like the read of a data source, has nothing to keep and fails.
+func readWithPermissionWarnings(r *schema.Resource) schema.ReadContextFunc {
+	read := r.ReadContext
+	if read == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		ctx, warnings := transport.WithPermissionWarnings(ctx)
+		prior := d.State()
+		diags := read(ctx, d, m)
+		messages := warnings.Messages()
+		if len(messages) == 0 {
+			return diags
+		}
+		detail := "There is no prior state to keep for the attributes read from this endpoint."
+		if prior != nil && prior.ID != "" {
+			detail = "The prior state of the attributes read from this endpoint is kept."
+			restorePriorState(r, d, prior)
+			var kept diag.Diagnostics
+			for _, diagnostic := range diags {
+				if diagnostic.Severity != diag.Error {
+					kept = append(kept, diagnostic)
+				}
+			}
+			diags = kept
+		}
+		for _, msg := range messages {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  msg,
+				Detail:   detail + " Set the provider's api_token_role to SUPER_ADMIN to fail on permission errors instead.",
+			})
+		}
+		return diags
+	}
+}
+
+// restorePriorState sets the ID and every attribute of the resource data back
+// to the prior state, undoing what a failed read set.
+func restorePriorState(r *schema.Resource, d *schema.ResourceData, prior *terraform.InstanceState) {
+	previous := r.Data(prior)
+	d.SetId(prior.ID)
+	for k := range r.Schema {
+		_ = d.Set(k, previous.Get(k))
+	}
+}
+
+// withRefusedRequests replaces the diagnostics of an operation that made
+// requests refused by the http transport, as the provider is read only, with
+// one naming the resource and every request refused.
+func withRefusedRequests(name, operation string, fn func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if fn == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		ctx, refused := transport.WithRefusedRequests(ctx)
+		diags := fn(ctx, d, m)
+		requests := refused.Requests()
+		if len(requests) == 0 {
+			return diags
+		}
+		resource := name
+		if d.Id() != "" {
+			resource = fmt.Sprintf("%s (%s)", name, d.Id())
+		}
+		detail := "The provider is configured with read_only = true, the refused requests were:\n"
+		for _, req := range requests {
+			detail += fmt.Sprintf("\n  %s %s", req.Method, req.URL)
+		}
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Refused to %s %s, the provider is read only", operation, resource),
+			Detail:   detail,
+		}}
+	}
 }
 
 // providerConfigure is only called once when a terraform command is run but it
@@ -9,14 +9,18 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"strings"
 	"sync"
 	"testing"
 
+	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
@@ -25,6 +29,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/okta/okta-sdk-golang/v3/okta"
+	"github.com/okta/terraform-provider-okta/okta/internal/mockokta"
 	"github.com/okta/terraform-provider-okta/sdk"
 	"gopkg.in/dnaeon/go-vcr.v3/cassette"
 	"gopkg.in/dnaeon/go-vcr.v3/recorder"
@@ -132,6 +137,127 @@ func TestProvider_impl(t *testing.T) {
 	_ = Provider()
 }
 
+func TestProviderReadOnly(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"00g1234","profile":{"name":"test"}}`))
+	}))
+	defer server.Close()
+
+	provider := Provider()
+	d := schema.TestResourceDataRaw(t, provider.Schema, map[string]interface{}{
+		"org_name":   "test",
+		"api_token":  "token",
+		"http_proxy": server.URL,
+		"read_only":  true,
+	})
+	config := NewConfig(d)
+	if err := config.loadClients(context.Background()); err != nil {
+		t.Fatalf("failed to load clients: %v", err)
+	}
+
+	groupResource := provider.ResourcesMap[group]
+	d = groupResource.TestResourceData()
+	d.Set("name", "test")
+	diags := groupResource.CreateContext(context.Background(), d, config)
+	if len(diags) != 1 || diags[0].Summary != "Refused to create okta_group, the provider is read only" {
+		t.Fatalf("expected a read only diagnostic, got %+v", diags)
+	}
+	if !strings.Contains(diags[0].Detail, "POST "+server.URL+"/api/v1/groups") {
+		t.Errorf("expected the refused request in %q", diags[0].Detail)
+	}
+
+	d.SetId("00g1234")
+	if diags := groupResource.ReadContext(context.Background(), d, config); diags.HasError() {
+		t.Errorf("unexpected read error %+v", diags)
+	}
+	if !reflect.DeepEqual(methods, []string{http.MethodGet}) {
+		t.Errorf("expected only the read to reach the server, got %v", methods)
+	}
+}
+
+// TestProviderPermissionWarnings reads a custom role whose permissions the
+// API token isn't allowed to read.
+func TestProviderPermissionWarnings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/iam/roles/cr1" {
+			_, _ = w.Write([]byte(`{"id":"cr1","label":"changed","description":"changed"}`))
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"errorCode":"E0000006","errorSummary":"You do not have permission to perform the requested action"}`))
+	}))
+	defer server.Close()
+
+	provider := Provider()
+	roleResource := provider.ResourcesMap[adminRoleCustom]
+	prior := &terraform.InstanceState{ID: "cr1", Attributes: map[string]string{
+		"id":            "cr1",
+		"label":         "test",
+		"description":   "test",
+		"permissions.#": "1",
+		"permissions.0": "okta.users.read",
+	}}
+	for _, role := range []string{"ORG_ADMIN", superAdminRole} {
+		t.Run(role, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, provider.Schema, map[string]interface{}{
+				"org_name":       "test",
+				"api_token":      "token",
+				"http_proxy":     server.URL,
+				"api_token_role": role,
+			})
+			config := NewConfig(d)
+			if err := config.loadClients(context.Background()); err != nil {
+				t.Fatalf("failed to load clients: %v", err)
+			}
+			d = roleResource.Data(prior)
+			diags := roleResource.ReadContext(context.Background(), d, config)
+			if role == superAdminRole {
+				if !diags.HasError() {
+					t.Errorf("expected the permission error to fail the read, got %+v", diags)
+				}
+				return
+			}
+			if len(diags) != 1 || diags[0].Severity != diag.Warning || !strings.Contains(diags[0].Summary, "/api/v1/iam/roles/cr1/permissions") {
+				t.Fatalf("expected a single permission warning, got %+v", diags)
+			}
+			if d.Id() != "cr1" || d.Get("label") != "test" || d.Get("description") != "test" || d.Get("permissions").(*schema.Set).Len() != 1 {
+				t.Errorf("expected the prior state to be kept, got %v", d.State())
+			}
+		})
+	}
+}
+
+func TestMockUnsupportedTypes(t *testing.T) {
+	c := resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: `
+resource "okta_group" "test" {
+  name = "testAcc_replace_with_uuid"
+}
+resource "local_file" "test" {
+  content  = okta_group.test.id
+  filename = "group.txt"
+}`},
+			{Config: `
+data "okta_brands" "test" {}
+resource "okta_network_zone" "test" {
+  name = "testAcc_replace_with_uuid"
+}
+resource "okta_network_zone" "other" {
+  name = "testAcc_replace_with_uuid_other"
+}`},
+		},
+	}
+	expected := []string{"okta_brands", "okta_network_zone"}
+	if unsupported := mockUnsupportedTypes(c); !reflect.DeepEqual(unsupported, expected) {
+		t.Errorf("expected %v, got %v", expected, unsupported)
+	}
+}
+
 func testAccPreCheck(t *testing.T) func() {
 	return func() {
 		err := accPreCheck()
@@ -278,9 +404,54 @@ func sdkSupplementClientForTest() *sdk.APISupplement {
 	return sdkSupplementClient
 }
 
+// newTestConfig returns provider metadata with clients for an org served by
+// the handler.
+func newTestConfig(t *testing.T, handler http.Handler) *Config {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	_, client, err := sdk.NewClient(
+		context.Background(),
+		sdk.WithOrgUrl(server.URL),
+		sdk.WithToken("token"),
+		sdk.WithAuthorizationMode("SSWS"),
+		sdk.WithCache(false),
+		sdk.WithTestingDisableHttpsCheck(true),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return &Config{
+		oktaSDKClientV2:         client,
+		oktaSDKsupplementClient: &sdk.APISupplement{RequestExecutor: client.CloneRequestExecutor()},
+		logger:                  hclog.NewNullLogger(),
+		parallelism:             2,
+		timeOperations:          NewTestTimeOperations(),
+	}
+}
+
+// updateResourceData returns the data of an update of the resource from the
+// state of d to config, diffed the way a plan diffs them.
+func updateResourceData(t *testing.T, r *schema.Resource, d *schema.ResourceData, config map[string]interface{}, m interface{}) *schema.ResourceData {
+	state := d.State()
+	diff, err := r.SimpleDiff(context.Background(), state, terraform.NewResourceConfigRaw(config), m)
+	if err != nil {
+		t.Fatalf("failed to diff: %v", err)
+	}
+	data, err := schema.InternalMap(r.Schema).Data(state, diff)
+	if err != nil {
+		t.Fatalf("failed to apply diff: %v", err)
+	}
+	return data
+}
+
 // oktaResourceTest is the entry to overriding the Terraform SDKs Acceptance
 // Test framework before the call to resource.Test
 func oktaResourceTest(t *testing.T, c resource.TestCase) {
+	if os.Getenv("OKTA_MOCK_TF_ACC") != "" {
+		mockResourceTest(t, c)
+		return
+	}
+
 	// plug in the VCR
 	mgr := newVCRManager(t.Name())
 
@@ -341,6 +512,95 @@ func oktaResourceTest(t *testing.T, c resource.TestCase) {
 	}
 }
 
+// mockOktaTypes are the resource and data source types backed by the API of
+// the fake Okta org of mockokta.
+var mockOktaTypes = map[string]bool{
+	app:                    true,
+	appAutoLogin:           true,
+	appBasicAuth:           true,
+	appBookmark:            true,
+	appGroupAssignment:     true,
+	appGroupAssignments:    true,
+	appSecurePasswordStore: true,
+	appSwa:                 true,
+	appThreeField:          true,
+	appUser:                true,
+	appUserAssignments:     true,
+	authServer:             true,
+	authServerClaim:        true,
+	authServerClaims:       true,
+	authServerKeyRotation:  true,
+	authServerKeys:         true,
+	authServerPolicy:       true,
+	authServerPolicyRule:   true,
+	authServerScope:        true,
+	authServerScopes:       true,
+	authServerTokenPreview: true,
+	defaultPolicy:          true,
+	eventHook:              true,
+	group:                  true,
+	groupEveryone:          true,
+	groupMemberships:       true,
+	groupRule:              true,
+	groups:                 true,
+	inlineHook:             true,
+	inlineHookPreview:      true,
+	policy:                 true,
+	policyPassword:         true,
+	policyRuleOrder:        true,
+	policyRulePassword:     true,
+	policyRuleSignOn:       true,
+	policySignOn:           true,
+	user:                   true,
+	userBaseSchemaProperty: true,
+	userGroupMemberships:   true,
+	userSchema:             true,
+	userSchemaProperty:     true,
+	users:                  true,
+}
+
+var configTypeRegexp = regexp.MustCompile(`(?m)^\s*(?:resource|data)\s+"(okta_[a-z0-9_]+)"`)
+
+// mockUnsupportedTypes returns the resource and data source types of the
+// configs of the test steps that aren't in mockOktaTypes.
+func mockUnsupportedTypes(c resource.TestCase) []string {
+	var unsupported []string
+	for _, step := range c.Steps {
+		for _, match := range configTypeRegexp.FindAllStringSubmatch(step.Config, -1) {
+			if !mockOktaTypes[match[1]] && !contains(unsupported, match[1]) {
+				unsupported = append(unsupported, match[1])
+			}
+		}
+	}
+	return unsupported
+}
+
+// mockResourceTest runs the acceptance test against a fake Okta org served by
+// mockokta, a new one for each test. Tests of types missing from mockOktaTypes
+// are skipped, other requests the fake org doesn't implement fail the test
+// with a 501 Not Implemented error.
+func mockResourceTest(t *testing.T, c resource.TestCase) {
+	if unsupported := mockUnsupportedTypes(c); len(unsupported) > 0 {
+		t.Skipf("%q test uses %s, which the mock Okta org doesn't implement, skipping test. See .github/CONTRIBUTING.md#acceptance-tests-with-the-mock-okta-org for more information.", t.Name(), strings.Join(unsupported, ", "))
+		return
+	}
+	server := mockokta.NewServer()
+	t.Cleanup(server.Close)
+
+	t.Setenv("OKTA_HTTP_PROXY", server.URL)
+	t.Setenv("OKTA_ORG_NAME", "mock")
+	t.Setenv("OKTA_BASE_URL", TestDomainName)
+	t.Setenv("OKTA_API_TOKEN", "token")
+	for _, name := range []string{"OKTA_ACCESS_TOKEN", "OKTA_API_CLIENT_ID", "OKTA_API_PRIVATE_KEY", "OKTA_API_PRIVATE_KEY_ID", "OKTA_API_SCOPES"} {
+		t.Setenv(name, "")
+	}
+	// the clients of the checks are the ones of the provider configured for
+	// the fake org
+	testSdkV3Client, testSdkV2Client, testSdkSupplementClient = nil, nil, nil
+	fmt.Printf("=== MOCK OKTA %q for %s\n", server.URL, t.Name())
+	resource.Test(t, c)
+}
+
 // vcrProviderFactoriesForTest Returns the overridden provider factories used by
 // the resource test case given the state of the VCR manager.  func
 // vcrProviderFactoriesForTest(mgr *vcrManager) map[string]func()
@@ -392,6 +652,20 @@ func vcrProviderFactoriesForTest(mgr *vcrManager) map[string]func() (tfprotov5.P
 	}
 }
 
+// vcrURL drops the limit query parameter of a URL. The page sizes requested by
+// the provider change without changing the objects listed by the recorded
+// pages, so cassettes stay valid when the page size of a list changes.
+func vcrURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil {
+		return s
+	}
+	q := u.Query()
+	q.Del("limit")
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
 func newVCRRecorder(mgr *vcrManager, transport http.RoundTripper) (rec *recorder.Recorder, err error) {
 	rec, err = recorder.NewWithOptions(&recorder.Options{
 		CassetteName:       mgr.CassettePath(),
@@ -405,8 +679,8 @@ func newVCRRecorder(mgr *vcrManager, transport http.RoundTripper) (rec *recorder
 
 	// Defines how VCR will match requests to responses.
 	rec.SetMatcher(func(r *http.Request, i cassette.Request) bool {
-		// Default matcher compares method and URL only
-		if !cassette.DefaultMatcher(r, i) {
+		// Compares method and URL, less the page size, see vcrURL
+		if r.Method != i.Method || vcrURL(r.URL.String()) != vcrURL(i.URL) {
 			return false
 		}
 		// TODO: there might be header information we could inspect to make this more precise
@@ -0,0 +1,196 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceAdminRoleAssignments() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAdminRoleAssignmentsCreate,
+		ReadContext:   resourceAdminRoleAssignmentsRead,
+		UpdateContext: resourceAdminRoleAssignmentsUpdate,
+		DeleteContext: resourceAdminRoleAssignmentsDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				_ = d.Set("principal_type", d.Id())
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		Description: "Resource to authoritatively manage every admin role assignment of the users or the groups of the org.",
+		Schema: map[string]*schema.Schema{
+			"principal_type": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{userScope, groupScope}, false)),
+				Description:      "Type of the principals whose admin roles are managed, USER or GROUP",
+			},
+			"assignment": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Admin role held by a principal, any other admin role assignment of a principal of the type is removed",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"principal_id": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "ID of the user or group",
+						},
+						"role_type": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Type of the standard role, e.g. SUPER_ADMIN, or CUSTOM for a custom role",
+						},
+						"custom_role_id": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "ID of the custom role, required for CUSTOM role type",
+						},
+						"resource_set_id": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "ID of the resource set the custom role is granted on, required for CUSTOM role type",
+						},
+					},
+				},
+			},
+			"allow_list": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "IDs of principals, e.g. break-glass accounts, whose admin roles are neither read nor changed",
+			},
+		},
+	}
+}
+
+func resourceAdminRoleAssignmentsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	principalType := d.Get("principal_type").(string)
+	if err := applyAdminRoleAssignments(ctx, d, m); err != nil {
+		return diag.Errorf("failed to apply admin role assignments of %s principals: %v", principalType, err)
+	}
+	d.SetId(principalType)
+	return resourceAdminRoleAssignmentsRead(ctx, d, m)
+}
+
+func resourceAdminRoleAssignmentsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	assignments, err := listAdminRoleAssignments(ctx, m, d.Id())
+	if err != nil {
+		return diag.Errorf("failed to list admin role assignments of %s principals: %v", d.Id(), err)
+	}
+	allowList := convertInterfaceToStringSetNullable(d.Get("allow_list"))
+	var arr []interface{}
+	for _, assignment := range assignments {
+		if contains(allowList, assignment.principalID) {
+			continue
+		}
+		arr = append(arr, flattenAdminRoleAssignment(assignment))
+	}
+	_ = d.Set("principal_type", d.Id())
+	err = setNonPrimitives(d, map[string]interface{}{"assignment": arr})
+	if err != nil {
+		return diag.Errorf("failed to set admin role assignments: %v", err)
+	}
+	return nil
+}
+
+func resourceAdminRoleAssignmentsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := applyAdminRoleAssignments(ctx, d, m); err != nil {
+		return diag.Errorf("failed to apply admin role assignments of %s principals: %v", d.Id(), err)
+	}
+	return resourceAdminRoleAssignmentsRead(ctx, d, m)
+}
+
+// resourceAdminRoleAssignmentsDelete leaves the admin roles as they are,
+// destroying the resource must not strip every admin of the org.
+func resourceAdminRoleAssignmentsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return nil
+}
+
+// applyAdminRoleAssignments grants the configured roles that aren't held yet
+// and then removes every other role assignment of a principal of the type
+// that isn't on the allow list.
+func applyAdminRoleAssignments(ctx context.Context, d *schema.ResourceData, m interface{}) error {
+	principalType := d.Get("principal_type").(string)
+	desired, err := buildAdminRoleAssignments(d)
+	if err != nil {
+		return err
+	}
+	current, err := listAdminRoleAssignments(ctx, m, principalType)
+	if err != nil {
+		return err
+	}
+	toAssign, toRemove := splitAdminRoleAssignments(desired, current, convertInterfaceToStringSetNullable(d.Get("allow_list")))
+	for _, assignment := range toAssign {
+		logger(m).Info("assigning admin role", "assignment", assignment.String())
+		if err := assignAdminRole(ctx, m, assignment); err != nil {
+			return fmt.Errorf("failed to assign %s: %v", assignment, err)
+		}
+	}
+	for _, assignment := range toRemove {
+		logger(m).Info("removing admin role", "assignment", assignment.String())
+		if err := unassignAdminRole(ctx, m, assignment); err != nil {
+			return fmt.Errorf("failed to remove %s: %v", assignment, err)
+		}
+	}
+	return nil
+}
+
+func splitAdminRoleAssignments(desired, current []*adminRoleAssignment, allowList []string) (toAssign, toRemove []*adminRoleAssignment) {
+	held := make(map[string]bool, len(current))
+	for _, assignment := range current {
+		held[assignment.key()] = true
+	}
+	wanted := make(map[string]bool, len(desired))
+	for _, assignment := range desired {
+		wanted[assignment.key()] = true
+		if !held[assignment.key()] {
+			toAssign = append(toAssign, assignment)
+		}
+	}
+	for _, assignment := range current {
+		if !wanted[assignment.key()] && !contains(allowList, assignment.principalID) {
+			toRemove = append(toRemove, assignment)
+		}
+	}
+	return
+}
+
+func buildAdminRoleAssignments(d *schema.ResourceData) ([]*adminRoleAssignment, error) {
+	principalType := d.Get("principal_type").(string)
+	allowList := convertInterfaceToStringSetNullable(d.Get("allow_list"))
+	var assignments []*adminRoleAssignment
+	for _, raw := range d.Get("assignment").(*schema.Set).List() {
+		v := raw.(map[string]interface{})
+		assignment := &adminRoleAssignment{
+			principalType: principalType,
+			principalID:   v["principal_id"].(string),
+			roleType:      v["role_type"].(string),
+			customRoleID:  v["custom_role_id"].(string),
+			resourceSetID: v["resource_set_id"].(string),
+		}
+		if contains(allowList, assignment.principalID) {
+			return nil, fmt.Errorf("principal (%s) is on the allow list, its admin roles can't be assigned", assignment.principalID)
+		}
+		isCustom := assignment.roleType == customRoleType
+		if isCustom != (assignment.customRoleID != "") || isCustom != (assignment.resourceSetID != "") {
+			return nil, fmt.Errorf("custom_role_id and resource_set_id must be set for, and only for, the %s role type, got %s", customRoleType, assignment)
+		}
+		assignments = append(assignments, assignment)
+	}
+	return assignments, nil
+}
+
+func flattenAdminRoleAssignment(assignment *adminRoleAssignment) map[string]interface{} {
+	return map[string]interface{}{
+		"principal_id":    assignment.principalID,
+		"role_type":       assignment.roleType,
+		"custom_role_id":  assignment.customRoleID,
+		"resource_set_id": assignment.resourceSetID,
+	}
+}
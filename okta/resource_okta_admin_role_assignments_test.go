@@ -0,0 +1,136 @@
+package okta
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+func TestSplitAdminRoleAssignments(t *testing.T) {
+	current := []*adminRoleAssignment{
+		{principalType: userScope, principalID: "u1", roleType: "SUPER_ADMIN", roleID: "ra1"},
+		{principalType: userScope, principalID: "u2", roleType: "APP_ADMIN", roleID: "ra2"},
+		{principalType: userScope, principalID: "u3", roleType: "SUPER_ADMIN", roleID: "ra3"},
+		{principalType: userScope, principalID: "u2", roleType: customRoleType, customRoleID: "cr1", resourceSetID: "rs1", memberID: "m1"},
+	}
+	desired := []*adminRoleAssignment{
+		{principalType: userScope, principalID: "u2", roleType: "APP_ADMIN"},
+		{principalType: userScope, principalID: "u2", roleType: "USER_ADMIN"},
+		{principalType: userScope, principalID: "u4", roleType: customRoleType, customRoleID: "cr1", resourceSetID: "rs1"},
+	}
+	toAssign, toRemove := splitAdminRoleAssignments(desired, current, []string{"u1"})
+	var assigned, removed []string
+	for _, assignment := range toAssign {
+		assigned = append(assigned, assignment.String())
+	}
+	for _, assignment := range toRemove {
+		removed = append(removed, assignment.String())
+	}
+	expectedAssigned := []string{
+		"USER_ADMIN of user (u2)",
+		"custom role (cr1) on resource set (rs1) of user (u4)",
+	}
+	expectedRemoved := []string{
+		"SUPER_ADMIN of user (u3)",
+		"custom role (cr1) on resource set (rs1) of user (u2)",
+	}
+	if !reflect.DeepEqual(assigned, expectedAssigned) {
+		t.Errorf("expected %v to be assigned, got %v", expectedAssigned, assigned)
+	}
+	if !reflect.DeepEqual(removed, expectedRemoved) {
+		t.Errorf("expected %v to be removed, got %v", expectedRemoved, removed)
+	}
+}
+
+func TestBuildAdminRoleAssignments(t *testing.T) {
+	tests := []struct {
+		assignment map[string]interface{}
+		allowList  []interface{}
+		err        string
+	}{
+		{
+			assignment: map[string]interface{}{"principal_id": "u1", "role_type": "SUPER_ADMIN"},
+		},
+		{
+			assignment: map[string]interface{}{"principal_id": "u1", "role_type": customRoleType, "custom_role_id": "cr1", "resource_set_id": "rs1"},
+		},
+		{
+			assignment: map[string]interface{}{"principal_id": "u1", "role_type": customRoleType, "custom_role_id": "cr1"},
+			err:        "must be set for, and only for, the CUSTOM role type",
+		},
+		{
+			assignment: map[string]interface{}{"principal_id": "u1", "role_type": "APP_ADMIN", "resource_set_id": "rs1"},
+			err:        "must be set for, and only for, the CUSTOM role type",
+		},
+		{
+			assignment: map[string]interface{}{"principal_id": "u1", "role_type": "SUPER_ADMIN"},
+			allowList:  []interface{}{"u1"},
+			err:        "principal (u1) is on the allow list",
+		},
+	}
+	for _, test := range tests {
+		d := schema.TestResourceDataRaw(t, resourceAdminRoleAssignments().Schema, map[string]interface{}{
+			"principal_type": userScope,
+			"assignment":     []interface{}{test.assignment},
+			"allow_list":     test.allowList,
+		})
+		assignments, err := buildAdminRoleAssignments(d)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%v: expected error %q, got %v", test.assignment, test.err, err)
+			}
+			continue
+		}
+		if err != nil || len(assignments) != 1 || assignments[0].principalType != userScope {
+			t.Errorf("%v: unexpected result %v, %v", test.assignment, assignments, err)
+		}
+	}
+}
+
+func TestCustomRoleMemberPrincipal(t *testing.T) {
+	tests := []struct {
+		href          string
+		principalType string
+		principalID   string
+	}{
+		{"https://example.okta.com/api/v1/users/00u1234", userScope, "00u1234"},
+		{"https://example.okta.com/api/v1/groups/00g1234", groupScope, "00g1234"},
+		{"https://example.okta.com/api/v1/apps/0oa1234", "", ""},
+	}
+	for _, test := range tests {
+		member := &sdk.CustomRoleBindingMember{
+			Id:    "irb1234",
+			Links: map[string]interface{}{"self": map[string]interface{}{"href": test.href}},
+		}
+		principalType, principalID := customRoleMemberPrincipal(member)
+		if principalType != test.principalType || principalID != test.principalID {
+			t.Errorf("%s: expected %s %s, got %s %s", test.href, test.principalType, test.principalID, principalType, principalID)
+		}
+	}
+
+	links := &sdk.IAMLinks{Next: &sdk.IAMLink{Href: "https://example.okta.com/api/v1/iam/assignees/users?after=00u5678&limit=20"}}
+	if after := links.After(); after != "00u5678" {
+		t.Errorf("expected the next page to be after 00u5678, got %q", after)
+	}
+	if after := (&sdk.IAMLinks{}).After(); after != "" {
+		t.Errorf("expected no next page, got %q", after)
+	}
+}
+
+func TestAdminRoleAssignmentsPrincipalType(t *testing.T) {
+	for _, principalType := range []*schema.Schema{
+		resourceAdminRoleAssignments().Schema["principal_type"],
+		dataSourceAdminRoleAssignments().Schema["principal_type"],
+	} {
+		for value, valid := range map[string]bool{"USER": true, "GROUP": true, "user": false, "GROUPS": false} {
+			diags := principalType.ValidateDiagFunc(value, cty.GetAttrPath("principal_type"))
+			if diags.HasError() == valid {
+				t.Errorf("expected principal_type %q to be valid: %t, got %v", value, valid, diags)
+			}
+		}
+	}
+}
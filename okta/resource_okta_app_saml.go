@@ -47,6 +47,7 @@ func resourceAppSaml() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: appImporter,
 		},
+		CustomizeDiff: appSamlKeyRotationDiff,
 		// For those familiar with Terraform schemas be sure to check the base application schema and/or
 		// the examples in the documentation
 		Schema: buildAppSchema(map[string]*schema.Schema{
@@ -75,6 +76,36 @@ func resourceAppSaml() *schema.Resource {
 				Optional:    true,
 				Description: "Number of years the certificate is valid.",
 			},
+			"active_key_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				Description:   "ID of the key the app signs with. Set it to the staged_key_id to promote the staged key.",
+				ConflictsWith: []string{"key_name"},
+			},
+			"rotate_before_days": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ValidateDiagFunc: intBetween(1, 3650),
+				Description:      "Number of days before the active key expires that a new key is generated and staged alongside it.",
+				RequiredWith:     []string{"key_years_valid"},
+				ConflictsWith:    []string{"key_name"},
+			},
+			"staged_key_id": {
+				Type:        schema.TypeString,
+				Description: "ID of the newest key generated after the active key, not yet signing",
+				Computed:    true,
+			},
+			"staged_certificate": {
+				Type:        schema.TypeString,
+				Description: "cert of the staged key",
+				Computed:    true,
+			},
+			"staged_metadata": {
+				Type:        schema.TypeString,
+				Description: "SAML xml metadata payload of the staged key",
+				Computed:    true,
+			},
 			"keys": {
 				Type:        schema.TypeList,
 				Description: "Application keys",
@@ -482,6 +513,7 @@ func resourceAppSamlRead(ctx context.Context, d *schema.ResourceData, m interfac
 	if app.Credentials.Signing.Kid != "" && app.Status != statusInactive {
 		keyID := app.Credentials.Signing.Kid
 		_ = d.Set("key_id", keyID)
+		_ = d.Set("active_key_id", keyID)
 		keyMetadata, metadataRoot, err := getAPISupplementFromMetadata(m).GetSAMLMetadata(ctx, d.Id(), keyID)
 		if err != nil {
 			return diag.Errorf("failed to get app's SAML metadata: %v", err)
@@ -511,6 +543,20 @@ func resourceAppSamlRead(ctx context.Context, d *schema.ResourceData, m interfac
 		return diag.Errorf("failed to set Application Credential Key Values: %v", err)
 	}
 
+	var stagedKeyID, stagedCertificate, stagedMetadata string
+	if staged := stagedAppKey(keys, app.Credentials.Signing.Kid); staged != nil && app.Status != statusInactive {
+		keyMetadata, metadataRoot, err := getAPISupplementFromMetadata(m).GetSAMLMetadata(ctx, d.Id(), staged.Kid)
+		if err != nil {
+			return diag.Errorf("failed to get app's SAML metadata for staged key: %v", err)
+		}
+		stagedKeyID = staged.Kid
+		stagedMetadata = string(keyMetadata)
+		stagedCertificate = metadataRoot.IDPSSODescriptors[0].KeyDescriptors[0].KeyInfo.X509Data.X509Certificates[0].Data
+	}
+	_ = d.Set("staged_key_id", stagedKeyID)
+	_ = d.Set("staged_certificate", stagedCertificate)
+	_ = d.Set("staged_metadata", stagedMetadata)
+
 	appRead(d, app.Name, app.Status, app.SignOnMode, app.Label, app.Accessibility, app.Visibility, app.Settings.Notes)
 	if app.SignOnMode == "SAML_1_1" {
 		_ = d.Set("saml_version", saml11)
@@ -544,6 +590,18 @@ func resourceAppSamlUpdate(ctx context.Context, d *schema.ResourceData, m interf
 			return diag.Errorf("failed to create new certificate for SAML application: %v", err)
 		}
 	}
+	if days := d.Get("rotate_before_days").(int); days > 0 && app.Credentials.Signing != nil {
+		keys, err := fetchAppKeys(ctx, m, d.Id())
+		if err != nil {
+			return diag.Errorf("failed to load existing keys for SAML application: %v", err)
+		}
+		if appKeyRotationDue(keys, app.Credentials.Signing.Kid, days, time.Now()) {
+			logger(m).Info("staging new key for SAML application", "id", d.Id())
+			if _, err := generateCertificate(ctx, d, m, d.Id()); err != nil {
+				return diag.Errorf("failed to create staged certificate for SAML application: %v", err)
+			}
+		}
+	}
 	if d.HasChange("logo") {
 		err = handleAppLogo(ctx, d, m, app.Id, app.Links)
 		if err != nil {
@@ -671,7 +729,11 @@ func buildSamlApp(d *schema.ResourceData) (*sdk.SamlApplication, error) {
 		app.Settings.SignOn.AttributeStatements = []*sdk.SamlAttributeStatement{}
 	}
 
-	if id, ok := d.GetOk("key_id"); ok {
+	if id, ok := d.GetOk("active_key_id"); ok {
+		app.Credentials.Signing = &sdk.ApplicationCredentialsSigning{
+			Kid: id.(string),
+		}
+	} else if id, ok := d.GetOk("key_id"); ok {
 		app.Credentials.Signing = &sdk.ApplicationCredentialsSigning{
 			Kid: id.(string),
 		}
@@ -708,6 +770,7 @@ func tryCreateCertificate(ctx context.Context, d *schema.ResourceData, m interfa
 
 		// Set ID and the read done at the end of update and create will do the GET on metadata
 		_ = d.Set("key_id", key.Kid)
+		_ = d.Set("active_key_id", key.Kid)
 		client := getOktaClientFromMetadata(m)
 		app, err := buildSamlApp(d)
 		if err != nil {
@@ -742,3 +805,87 @@ func validateAppSaml(d *schema.ResourceData) error {
 	}
 	return nil
 }
+
+// appSamlKeyRotationDiff plans staging a new key, see rotate_before_days, once
+// the active key expires within that many days and no key is staged yet.
+func appSamlKeyRotationDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	days := d.Get("rotate_before_days").(int)
+	if d.Id() == "" || days == 0 {
+		return nil
+	}
+	keys, err := appKeysFromState(d.Get("keys").([]interface{}))
+	if err != nil {
+		return err
+	}
+	if !appKeyRotationDue(keys, d.Get("active_key_id").(string), days, time.Now()) {
+		return nil
+	}
+	for _, k := range []string{"keys", "staged_key_id", "staged_certificate", "staged_metadata"} {
+		if err := d.SetNewComputed(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appKeysFromState returns the keys as set by setAppKeys, only the ID and
+// dates are read back.
+func appKeysFromState(state []interface{}) ([]*sdk.JsonWebKey, error) {
+	const layout = "2006-01-02 15:04:05.999999999 -0700 MST"
+	keys := make([]*sdk.JsonWebKey, len(state))
+	for i, v := range state {
+		key := v.(map[string]interface{})
+		created, err := time.Parse(layout, key["created"].(string))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse created date of key %s: %v", key["kid"], err)
+		}
+		expiresAt, err := time.Parse(layout, key["expires_at"].(string))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse expiration date of key %s: %v", key["kid"], err)
+		}
+		keys[i] = &sdk.JsonWebKey{
+			Kid:       key["kid"].(string),
+			Created:   &created,
+			ExpiresAt: &expiresAt,
+		}
+	}
+	return keys, nil
+}
+
+// stagedAppKey returns the newest key created after the active key, i.e. a
+// key published alongside the active key to be promoted later.
+func stagedAppKey(keys []*sdk.JsonWebKey, activeKid string) *sdk.JsonWebKey {
+	var active *sdk.JsonWebKey
+	for _, key := range keys {
+		if key.Kid == activeKid {
+			active = key
+		}
+	}
+	if active == nil || active.Created == nil {
+		return nil
+	}
+	var staged *sdk.JsonWebKey
+	for _, key := range keys {
+		if key.Kid == activeKid || key.Created == nil || !key.Created.After(*active.Created) {
+			continue
+		}
+		if staged == nil || key.Created.After(*staged.Created) {
+			staged = key
+		}
+	}
+	return staged
+}
+
+// appKeyRotationDue is true when the active key expires within days of now
+// and no key is staged to replace it.
+func appKeyRotationDue(keys []*sdk.JsonWebKey, activeKid string, days int, now time.Time) bool {
+	if stagedAppKey(keys, activeKid) != nil {
+		return false
+	}
+	for _, key := range keys {
+		if key.Kid == activeKid && key.ExpiresAt != nil {
+			return now.AddDate(0, 0, days).After(*key.ExpiresAt)
+		}
+	}
+	return false
+}
@@ -7,12 +7,57 @@ import (
 	"reflect"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/okta/terraform-provider-okta/sdk"
 )
 
+// Ensure a newer key is staged and rotation is due only within
+// rotate_before_days of the expiry of the active key
+func TestAppSamlKeyRotation(t *testing.T) {
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	key := func(kid string, created, expiresAt time.Time) map[string]interface{} {
+		return map[string]interface{}{
+			"kid":        kid,
+			"created":    created.String(),
+			"expires_at": expiresAt.String(),
+		}
+	}
+	state := []interface{}{
+		key("old", now.AddDate(-4, 0, 0), now.AddDate(-2, 0, 0)),
+		key("active", now.AddDate(-2, 0, 0), now.AddDate(0, 0, 20)),
+	}
+	keys, err := appKeysFromState(state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !keys[1].ExpiresAt.Equal(now.AddDate(0, 0, 20)) {
+		t.Errorf("expected expires at %v, got %v", now.AddDate(0, 0, 20), keys[1].ExpiresAt)
+	}
+	if staged := stagedAppKey(keys, "active"); staged != nil {
+		t.Errorf("expected no staged key as the old key is older than the active key, got %s", staged.Kid)
+	}
+	if appKeyRotationDue(keys, "active", 10, now) {
+		t.Errorf("expected rotation not to be due 20 days before expiry with rotate_before_days 10")
+	}
+	if !appKeyRotationDue(keys, "active", 30, now) {
+		t.Errorf("expected rotation to be due 20 days before expiry with rotate_before_days 30")
+	}
+
+	keys, _ = appKeysFromState(append(state, key("staged", now, now.AddDate(2, 0, 0))))
+	if staged := stagedAppKey(keys, "active"); staged == nil || staged.Kid != "staged" {
+		t.Errorf("expected the staged key, got %+v", staged)
+	}
+	if appKeyRotationDue(keys, "active", 30, now) {
+		t.Errorf("expected rotation not to be due once a key is staged")
+	}
+	if staged := stagedAppKey(keys, "staged"); staged != nil {
+		t.Errorf("expected no staged key once the staged key is promoted, got %s", staged.Kid)
+	}
+}
+
 // Ensure conditional require logic causes this plan to fail
 func TestAccResourceOktaAppSaml_conditionalRequire(t *testing.T) {
 	mgr := newFixtureManager(appSaml, t.Name())
@@ -109,7 +109,7 @@ func resourceAppSignOnPolicyDelete(ctx context.Context, d *schema.ResourceData,
 	}
 
 	client := getOktaClientFromMetadata(m)
-	apps, err := listApps(ctx, client, nil, defaultPaginationLimit)
+	apps, err := listApps(ctx, client, nil, maxAppsPageSize)
 	if err != nil {
 		return diag.Errorf("failed to list apps in preparation to delete authentication policy: %v", err)
 	}
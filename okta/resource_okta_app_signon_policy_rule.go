@@ -38,10 +38,8 @@ func resourceAppSignOnPolicyRule() *schema.Resource {
 			"priority": {
 				Type:     schema.TypeInt,
 				Optional: true,
-				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
-					p, n := d.GetChange("priority")
-					return p == n && new == "0"
-				},
+				// Suppress diff if config is empty.
+				DiffSuppressFunc: createValueDiffSuppression("0"),
 			},
 			"groups_included": {
 				Type:        schema.TypeSet,
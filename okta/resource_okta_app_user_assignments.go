@@ -0,0 +1,293 @@
+package okta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+func resourceAppUserAssignments() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAppUserAssignmentsCreate,
+		ReadContext:   resourceAppUserAssignmentsRead,
+		UpdateContext: resourceAppUserAssignmentsUpdate,
+		DeleteContext: resourceAppUserAssignmentsDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				_ = d.Set("app_id", d.Id())
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		Schema: map[string]*schema.Schema{
+			"app_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "App to assign the users to",
+			},
+			"users": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Description: "Users directly assigned to the app, any other direct assignment is removed",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "User associated with the application",
+						},
+						"username": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Username of the user in the app, not to be set for apps with a shared username",
+						},
+						"password": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "Password of the user in the app",
+						},
+						"profile": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							ValidateDiagFunc: stringIsJSON,
+							Description:      "JSON of the app user profile attributes to set",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceAppUserAssignmentsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	appID := d.Get("app_id").(string)
+	if err := applyAppUserAssignments(ctx, d, m); err != nil {
+		return diag.Errorf("failed to assign users to application (%s): %v", appID, err)
+	}
+	// okta_app_user_assignments completely controls the direct user assignments of an application
+	d.SetId(appID)
+	return resourceAppUserAssignmentsRead(ctx, d, m)
+}
+
+func resourceAppUserAssignmentsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	appUsers, resp, err := listApplicationUsers(ctx, getOktaClientFromMetadata(m), d.Get("app_id").(string))
+	if err := suppressErrorOn404(resp, err); err != nil {
+		return diag.Errorf("failed to list application's users: %v", err)
+	}
+	if is404(resp) {
+		d.SetId("")
+		return nil
+	}
+	err = setNonPrimitives(d, map[string]interface{}{
+		"users": syncAppUserAssignments(d.Get("users").(*schema.Set).List(), appUsers),
+	})
+	if err != nil {
+		return diag.Errorf("failed to set application's users: %v", err)
+	}
+	return nil
+}
+
+func resourceAppUserAssignmentsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := applyAppUserAssignments(ctx, d, m); err != nil {
+		return diag.Errorf("failed to update users of application (%s): %v", d.Id(), err)
+	}
+	return resourceAppUserAssignmentsRead(ctx, d, m)
+}
+
+func resourceAppUserAssignmentsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := getOktaClientFromMetadata(m)
+	appID := d.Get("app_id").(string)
+	users := d.Get("users").(*schema.Set).List()
+	err := forEachParallel(ctx, m, len(users), func(ctx context.Context, i int) error {
+		userID := users[i].(map[string]interface{})["id"].(string)
+		resp, err := client.Application.DeleteApplicationUser(ctx, appID, userID, nil)
+		if err := suppressErrorOn404(resp, err); err != nil {
+			return fmt.Errorf("failed to remove user (%s): %v", userID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.Errorf("failed to remove users from application (%s): %v", appID, err)
+	}
+	return nil
+}
+
+// applyAppUserAssignments makes the direct user assignments of the app match
+// the configured users. Users assigned to the app only through a group are
+// assigned directly when configured and are otherwise left alone. The
+// requests are made concurrently up to the provider's parallelism.
+func applyAppUserAssignments(ctx context.Context, d *schema.ResourceData, m interface{}) error {
+	client := getOktaClientFromMetadata(m)
+	appID := d.Get("app_id").(string)
+	appUsers, _, err := listApplicationUsers(ctx, client, appID)
+	if err != nil {
+		return fmt.Errorf("failed to list application's users: %v", err)
+	}
+	oldUsers, newUsers := d.GetChange("users")
+	toAssign, toUpdate, toRemove := splitAppUserAssignments(
+		oldUsers.(*schema.Set).List(),
+		newUsers.(*schema.Set).List(),
+		appUsers,
+	)
+	logger(m).Info("applying application user assignments", "app_id", appID,
+		"assign", len(toAssign), "update", len(toUpdate), "remove", len(toRemove))
+
+	err = forEachParallel(ctx, m, len(toRemove), func(ctx context.Context, i int) error {
+		resp, err := client.Application.DeleteApplicationUser(ctx, appID, toRemove[i], nil)
+		if err := suppressErrorOn404(resp, err); err != nil {
+			return fmt.Errorf("failed to remove user (%s): %v", toRemove[i], err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	err = forEachParallel(ctx, m, len(toAssign), func(ctx context.Context, i int) error {
+		if _, _, err := client.Application.AssignUserToApplication(ctx, appID, *toAssign[i]); err != nil {
+			return fmt.Errorf("failed to assign user (%s): %v", toAssign[i].Id, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return forEachParallel(ctx, m, len(toUpdate), func(ctx context.Context, i int) error {
+		if _, _, err := client.Application.UpdateApplicationUser(ctx, appID, toUpdate[i].Id, *toUpdate[i]); err != nil {
+			return fmt.Errorf("failed to update user (%s): %v", toUpdate[i].Id, err)
+		}
+		return nil
+	})
+}
+
+// splitAppUserAssignments returns the configured users that aren't directly
+// assigned to the app yet, the directly assigned ones whose username,
+// password or profile changed and the IDs of the direct assignments that
+// aren't configured anymore.
+func splitAppUserAssignments(oldUsers, newUsers []interface{}, appUsers []*sdk.AppUser) (toAssign, toUpdate []*sdk.AppUser, toRemove []string) {
+	direct := make(map[string]bool)
+	for _, appUser := range appUsers {
+		if appUser.Scope == "USER" {
+			direct[appUser.Id] = true
+		}
+	}
+	previous := make(map[string]map[string]interface{}, len(oldUsers))
+	for _, raw := range oldUsers {
+		user := raw.(map[string]interface{})
+		previous[user["id"].(string)] = user
+	}
+	configured := make(map[string]bool, len(newUsers))
+	for _, raw := range newUsers {
+		user := raw.(map[string]interface{})
+		id := user["id"].(string)
+		configured[id] = true
+		switch {
+		case !direct[id]:
+			toAssign = append(toAssign, buildAppUserAssignment(user))
+		case !reflect.DeepEqual(previous[id], user) && hasAppUserAssignmentSettings(user):
+			toUpdate = append(toUpdate, buildAppUserAssignment(user))
+		}
+	}
+	for _, appUser := range appUsers {
+		if direct[appUser.Id] && !configured[appUser.Id] {
+			toRemove = append(toRemove, appUser.Id)
+		}
+	}
+	return
+}
+
+func hasAppUserAssignmentSettings(user map[string]interface{}) bool {
+	return user["username"].(string) != "" || user["password"].(string) != "" || user["profile"].(string) != ""
+}
+
+func buildAppUserAssignment(user map[string]interface{}) *sdk.AppUser {
+	appUser := &sdk.AppUser{
+		Id:    user["id"].(string),
+		Scope: "USER",
+	}
+	username := user["username"].(string)
+	password := user["password"].(string)
+	if username != "" || password != "" {
+		appUser.Credentials = &sdk.AppUserCredentials{UserName: username}
+		if password != "" {
+			appUser.Credentials.Password = &sdk.AppUserPasswordCredential{Value: password}
+		}
+	}
+	if rawProfile := user["profile"].(string); rawProfile != "" {
+		var profile interface{}
+		// JSON is already validated
+		_ = json.Unmarshal([]byte(rawProfile), &profile)
+		appUser.Profile = profile
+	}
+	return appUser
+}
+
+// syncAppUserAssignments returns the users for the state from the direct
+// user assignments of the app. The username and profile are only read back
+// for users that have them set, only the profile attributes already set are
+// read back and the password, which isn't returned, is kept as is.
+func syncAppUserAssignments(users []interface{}, appUsers []*sdk.AppUser) []interface{} {
+	known := make(map[string]map[string]interface{}, len(users))
+	for _, raw := range users {
+		user := raw.(map[string]interface{})
+		known[user["id"].(string)] = user
+	}
+	var result []interface{}
+	for _, appUser := range appUsers {
+		if appUser.Scope != "USER" {
+			continue
+		}
+		var username string
+		if appUser.Credentials != nil {
+			username = appUser.Credentials.UserName
+		}
+		user, ok := known[appUser.Id]
+		if !ok {
+			result = append(result, map[string]interface{}{
+				"id":       appUser.Id,
+				"username": username,
+				"password": "",
+				"profile":  "",
+			})
+			continue
+		}
+		synced := map[string]interface{}{
+			"id":       appUser.Id,
+			"username": user["username"],
+			"password": user["password"],
+			"profile":  user["profile"],
+		}
+		if user["username"].(string) != "" {
+			synced["username"] = username
+		}
+		if user["profile"].(string) != "" {
+			synced["profile"] = syncAppUserProfile(user["profile"].(string), appUser.Profile)
+		}
+		result = append(result, synced)
+	}
+	return result
+}
+
+// syncAppUserProfile returns the profile JSON with the values of its
+// attributes taken from the app user's profile.
+func syncAppUserProfile(rawProfile string, appUserProfile interface{}) string {
+	profile := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(rawProfile), &profile); err != nil {
+		return rawProfile
+	}
+	current, _ := appUserProfile.(map[string]interface{})
+	for k := range profile {
+		profile[k] = current[k]
+	}
+	result, err := json.Marshal(profile)
+	if err != nil {
+		return rawProfile
+	}
+	return string(result)
+}
@@ -0,0 +1,144 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+func TestSplitAppUserAssignments(t *testing.T) {
+	user := func(id, username, profile string) map[string]interface{} {
+		return map[string]interface{}{"id": id, "username": username, "password": "", "profile": profile}
+	}
+	appUsers := []*sdk.AppUser{
+		{Id: "u1", Scope: "USER"},
+		{Id: "u2", Scope: "USER"},
+		{Id: "u3", Scope: "USER"},
+		{Id: "u4", Scope: "GROUP"},
+		{Id: "u5", Scope: "GROUP"},
+	}
+	oldUsers := []interface{}{user("u1", "one", ""), user("u2", "two", ""), user("u3", "three", "")}
+	newUsers := []interface{}{user("u1", "one", ""), user("u2", "two", `{"role":"admin"}`), user("u4", "", ""), user("u6", "six", "")}
+
+	toAssign, toUpdate, toRemove := splitAppUserAssignments(oldUsers, newUsers, appUsers)
+	var assigned, updated []string
+	for _, appUser := range toAssign {
+		assigned = append(assigned, appUser.Id)
+	}
+	for _, appUser := range toUpdate {
+		updated = append(updated, appUser.Id)
+	}
+	if !reflect.DeepEqual(assigned, []string{"u4", "u6"}) {
+		t.Errorf("expected u4 and u6 to be assigned, got %v", assigned)
+	}
+	if !reflect.DeepEqual(updated, []string{"u2"}) {
+		t.Errorf("expected u2 to be updated, got %v", updated)
+	}
+	if !reflect.DeepEqual(toRemove, []string{"u3"}) {
+		t.Errorf("expected u3 to be removed, got %v", toRemove)
+	}
+	if toAssign[0].Credentials != nil || toAssign[1].Credentials.UserName != "six" {
+		t.Errorf("unexpected credentials %+v, %+v", toAssign[0].Credentials, toAssign[1].Credentials)
+	}
+	if profile, _ := toUpdate[0].Profile.(map[string]interface{}); profile["role"] != "admin" {
+		t.Errorf("expected u2's profile to be set, got %v", toUpdate[0].Profile)
+	}
+}
+
+func TestSyncAppUserAssignments(t *testing.T) {
+	users := []interface{}{
+		map[string]interface{}{"id": "u1", "username": "one", "password": "secret", "profile": `{"role":"admin"}`},
+		map[string]interface{}{"id": "u2", "username": "", "password": "", "profile": ""},
+		map[string]interface{}{"id": "u3", "username": "three", "password": "", "profile": ""},
+	}
+	appUsers := []*sdk.AppUser{
+		{
+			Id:          "u1",
+			Scope:       "USER",
+			Credentials: &sdk.AppUserCredentials{UserName: "renamed"},
+			Profile:     map[string]interface{}{"role": "user", "email": "one@example.com"},
+		},
+		{Id: "u2", Scope: "USER", Credentials: &sdk.AppUserCredentials{UserName: "two"}},
+		{Id: "u4", Scope: "USER", Credentials: &sdk.AppUserCredentials{UserName: "four"}},
+		{Id: "u5", Scope: "GROUP", Credentials: &sdk.AppUserCredentials{UserName: "five"}},
+	}
+	expected := []interface{}{
+		map[string]interface{}{"id": "u1", "username": "renamed", "password": "secret", "profile": `{"role":"user"}`},
+		map[string]interface{}{"id": "u2", "username": "", "password": "", "profile": ""},
+		map[string]interface{}{"id": "u4", "username": "four", "password": "", "profile": ""},
+	}
+	if got := syncAppUserAssignments(users, appUsers); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestAccResourceOktaAppUserAssignments_crud(t *testing.T) {
+	resourceName := fmt.Sprintf("%s.test", appUserAssignments)
+	mgr := newFixtureManager(appUserAssignments, t.Name())
+	config := mgr.GetFixtures("basic.tf", t)
+	updatedConfig := mgr.GetFixtures("updated.tf", t)
+	user0 := fmt.Sprintf("%s.test.0", user)
+	user1 := fmt.Sprintf("%s.test.1", user)
+	user2 := fmt.Sprintf("%s.test.2", user)
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		CheckDestroy:      checkUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "users.#", "3"),
+					ensureAppUserAssignmentsExist(resourceName, user0, user1, user2),
+				),
+			},
+			{
+				Config: updatedConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "users.#", "2"),
+					ensureAppUserAssignmentsExist(resourceName, user0, user2),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// ensureAppUserAssignmentsExist checks that exactly the given users are
+// directly assigned to the app.
+func ensureAppUserAssignmentsExist(resourceName string, usersExpected ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		appID := s.RootModule().Resources[resourceName].Primary.Attributes["app_id"]
+		appUsers, _, err := listApplicationUsers(context.Background(), sdkV2ClientForTest(), appID)
+		if err != nil {
+			return err
+		}
+		direct := make(map[string]bool)
+		for _, appUser := range appUsers {
+			if appUser.Scope == "USER" {
+				direct[appUser.Id] = true
+			}
+		}
+		for _, name := range usersExpected {
+			id := s.RootModule().Resources[name].Primary.Attributes["id"]
+			if !direct[id] {
+				return fmt.Errorf("user %s (%s) is not assigned to app %s", name, id, appID)
+			}
+		}
+		if len(direct) != len(usersExpected) {
+			return fmt.Errorf("expected %d users assigned to app %s, got %d", len(usersExpected), appID, len(direct))
+		}
+		return nil
+	}
+}
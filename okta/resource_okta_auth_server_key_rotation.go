@@ -0,0 +1,109 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+// resourceAuthServerKeyRotation rotates the signing keys of an authorization
+// server when it's created and whenever its rotation_trigger changes. The
+// NEXT key becomes ACTIVE, the ACTIVE key EXPIRED and Okta generates a new
+// NEXT key.
+func resourceAuthServerKeyRotation() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAuthServerKeyRotationCreate,
+		ReadContext:   resourceAuthServerKeyRotationRead,
+		UpdateContext: resourceAuthServerKeyRotationUpdate,
+		DeleteContext: resourceFuncNoOp,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"auth_server_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the authorization server whose keys are rotated",
+			},
+			"rotation_trigger": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Arbitrary value, the keys are rotated each time it changes",
+			},
+			"kid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ID of the ACTIVE key",
+			},
+			"next_kid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ID of the NEXT key, the one promoted to ACTIVE on the next rotation",
+			},
+			"last_rotated": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the keys were last rotated",
+			},
+		},
+	}
+}
+
+func resourceAuthServerKeyRotationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	authServerID := d.Get("auth_server_id").(string)
+	if err := rotateAuthServerKeys(ctx, m, authServerID); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(authServerID)
+	return resourceAuthServerKeyRotationRead(ctx, d, m)
+}
+
+func resourceAuthServerKeyRotationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	authServer, resp, err := getOktaClientFromMetadata(m).AuthorizationServer.GetAuthorizationServer(ctx, d.Id())
+	if err := suppressErrorOn404(resp, err); err != nil {
+		return diag.Errorf("failed to get authorization server: %v", err)
+	}
+	if authServer == nil {
+		d.SetId("")
+		return nil
+	}
+	keys, _, err := getOktaClientFromMetadata(m).AuthorizationServer.ListAuthorizationServerKeys(ctx, d.Id())
+	if err != nil {
+		return diag.Errorf("failed to list authorization server keys: %v", err)
+	}
+	_ = d.Set("auth_server_id", d.Id())
+	for _, key := range keys {
+		switch key.Status {
+		case "ACTIVE":
+			_ = d.Set("kid", key.Kid)
+		case "NEXT":
+			_ = d.Set("next_kid", key.Kid)
+		}
+	}
+	if authServer.Credentials != nil && authServer.Credentials.Signing != nil && authServer.Credentials.Signing.LastRotated != nil {
+		_ = d.Set("last_rotated", authServer.Credentials.Signing.LastRotated.String())
+	}
+	return nil
+}
+
+func resourceAuthServerKeyRotationUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.HasChange("rotation_trigger") {
+		if err := rotateAuthServerKeys(ctx, m, d.Id()); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return resourceAuthServerKeyRotationRead(ctx, d, m)
+}
+
+func rotateAuthServerKeys(ctx context.Context, m interface{}, authServerID string) error {
+	logger(m).Info("rotating authorization server keys", "auth_server_id", authServerID)
+	_, _, err := getOktaClientFromMetadata(m).AuthorizationServer.RotateAuthorizationServerKeys(ctx, authServerID, sdk.JwkUse{Use: "sig"})
+	if err != nil {
+		return fmt.Errorf("failed to rotate authorization server keys: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/okta/terraform-provider-okta/okta/internal/mockokta"
+)
+
+func TestAccResourceOktaAuthServerKeyRotation_crud(t *testing.T) {
+	resourceName := fmt.Sprintf("%s.test", authServerKeyRotation)
+	mgr := newFixtureManager(authServerKeyRotation, t.Name())
+	config := mgr.GetFixtures("basic.tf", t)
+	updatedConfig := mgr.GetFixtures("basic_updated.tf", t)
+	var rotatedKid string
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		CheckDestroy:      checkResourceDestroy(authServer, authServerExists),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "auth_server_id", "okta_auth_server.test", "id"),
+					resource.TestCheckResourceAttrSet(resourceName, "kid"),
+					resource.TestCheckResourceAttrSet(resourceName, "next_kid"),
+					resource.TestCheckResourceAttrSet(resourceName, "last_rotated"),
+					func(s *terraform.State) error {
+						rotatedKid = s.RootModule().Resources[resourceName].Primary.Attributes["next_kid"]
+						return nil
+					},
+				),
+			},
+			{
+				Config: updatedConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "rotation_trigger", "2024-02"),
+					func(s *terraform.State) error {
+						if kid := s.RootModule().Resources[resourceName].Primary.Attributes["kid"]; kid != rotatedKid {
+							return fmt.Errorf("expected the NEXT key %q to be promoted to ACTIVE, got %q", rotatedKid, kid)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestResourceAuthServerKeyRotation(t *testing.T) {
+	server := mockokta.NewServer()
+	t.Cleanup(server.Close)
+	m := newTestConfig(t, server)
+	ctx := context.Background()
+	r := resourceAuthServerKeyRotation()
+	// updateData returns the data of an update of the rotation trigger
+	updateData := func(d *schema.ResourceData, trigger string) *schema.ResourceData {
+		return updateResourceData(t, r, d, map[string]interface{}{"auth_server_id": "default", "rotation_trigger": trigger}, m)
+	}
+
+	keys, _, err := getOktaClientFromMetadata(m).AuthorizationServer.ListAuthorizationServerKeys(ctx, "default")
+	if err != nil || len(keys) != 2 || keys[0].Status != "ACTIVE" || keys[1].Status != "NEXT" {
+		t.Fatalf("expected the ACTIVE and NEXT keys of the default auth server, got %v: %v", keys, err)
+	}
+	activeKid, nextKid := keys[0].Kid, keys[1].Kid
+
+	d := r.Data(nil)
+	_ = d.Set("auth_server_id", "default")
+	_ = d.Set("rotation_trigger", "1")
+	if diags := r.CreateContext(ctx, d, m); diags.HasError() {
+		t.Fatalf("failed to create: %v", diags)
+	}
+	if d.Id() != "default" || d.Get("kid") != nextKid || d.Get("next_kid") == "" || d.Get("last_rotated") == "" {
+		t.Errorf("expected the NEXT key %q to be promoted to ACTIVE, got kid %q next_kid %q", nextKid, d.Get("kid"), d.Get("next_kid"))
+	}
+
+	// an update without a new trigger doesn't rotate the keys
+	d = updateData(d, "1")
+	if diags := r.UpdateContext(ctx, d, m); diags.HasError() {
+		t.Fatalf("failed to update: %v", diags)
+	}
+	if d.Get("kid") != nextKid {
+		t.Errorf("expected the keys not to be rotated, got kid %q", d.Get("kid"))
+	}
+
+	newNextKid := d.Get("next_kid").(string)
+	d = updateData(d, "2")
+	if diags := r.UpdateContext(ctx, d, m); diags.HasError() {
+		t.Fatalf("failed to update: %v", diags)
+	}
+	if d.Get("kid") != newNextKid {
+		t.Errorf("expected the NEXT key %q to be promoted to ACTIVE, got %q", newNextKid, d.Get("kid"))
+	}
+
+	ds := dataSourceAuthServerKeys()
+	dd := ds.Data(nil)
+	_ = dd.Set("auth_server_id", "default")
+	if diags := ds.ReadContext(ctx, dd, m); diags.HasError() {
+		t.Fatalf("failed to read keys: %v", diags)
+	}
+	var statuses []string
+	for _, key := range dd.Get("keys").([]interface{}) {
+		k := key.(map[string]interface{})
+		statuses = append(statuses, fmt.Sprintf("%s:%s", k["status"], k["kid"]))
+		if k["kid"] == activeKid {
+			t.Errorf("expected the key %q expired twice ago to be dropped", activeKid)
+		}
+	}
+	if len(statuses) != 3 || statuses[0] != "EXPIRED:"+nextKid || statuses[1] != "ACTIVE:"+newNextKid {
+		t.Errorf("expected the EXPIRED, ACTIVE and NEXT keys, got %v", statuses)
+	}
+}
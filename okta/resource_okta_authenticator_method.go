@@ -0,0 +1,200 @@
+package okta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+func resourceAuthenticatorMethod() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAuthenticatorMethodCreate,
+		ReadContext:   resourceAuthenticatorMethodRead,
+		UpdateContext: resourceAuthenticatorMethodUpdate,
+		DeleteContext: resourceFuncNoOp,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				parts := strings.Split(d.Id(), "/")
+				if len(parts) != 2 {
+					return nil, fmt.Errorf("invalid resource import specifier, expecting the following format: <authenticator_id>/<type>")
+				}
+				_ = d.Set("authenticator_id", parts[0])
+				_ = d.Set("type", parts[1])
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		Description: "Resource to manage the status and settings of a method of an OIE authenticator. Methods are never deleted, destroying the resource leaves the method as it is.",
+		Schema: map[string]*schema.Schema{
+			"authenticator_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the authenticator",
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Type of the method, e.g. push, totp or signed_nonce for Okta Verify, sms or voice for phone, webauthn for WebAuthn",
+			},
+			"status": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          statusActive,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{statusActive, statusInactive}, false)),
+				Description:      "Method status: ACTIVE or INACTIVE",
+			},
+			"settings": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "Method specific settings in JSON format, only the given settings are managed",
+				ValidateDiagFunc: stringIsJSON,
+				StateFunc:        normalizeDataJSON,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return new == ""
+				},
+			},
+		},
+	}
+}
+
+// resourceAuthenticatorMethodCreate every method of an authenticator already
+// exists, create is a soft import of the method followed by an update.
+func resourceAuthenticatorMethodCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if isClassicOrg(ctx, m) {
+		return resourceOIEOnlyFeatureError(authenticatorMethod)
+	}
+	authenticatorID := d.Get("authenticator_id").(string)
+	methodType := d.Get("type").(string)
+	if err := applyAuthenticatorMethod(ctx, d, m); err != nil {
+		return diag.Errorf("failed to update %s method of authenticator (%s): %v", methodType, authenticatorID, err)
+	}
+	d.SetId(fmt.Sprintf("%s/%s", authenticatorID, methodType))
+	return resourceAuthenticatorMethodRead(ctx, d, m)
+}
+
+func resourceAuthenticatorMethodRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if isClassicOrg(ctx, m) {
+		return resourceOIEOnlyFeatureError(authenticatorMethod)
+	}
+	authenticatorID := d.Get("authenticator_id").(string)
+	methodType := d.Get("type").(string)
+	method, resp, err := getAPISupplementFromMetadata(m).GetAuthenticatorMethod(ctx, authenticatorID, methodType)
+	if err := suppressErrorOn404(resp, err); err != nil {
+		return diag.Errorf("failed to get %s method of authenticator (%s): %v", methodType, authenticatorID, err)
+	}
+	if method == nil {
+		d.SetId("")
+		return nil
+	}
+	_ = d.Set("status", method.Status)
+	settings, err := flattenAuthenticatorMethodSettings(d.Get("settings").(string), method.Settings)
+	if err != nil {
+		return diag.Errorf("failed to set %s method settings: %v", methodType, err)
+	}
+	_ = d.Set("settings", settings)
+	return nil
+}
+
+func resourceAuthenticatorMethodUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if isClassicOrg(ctx, m) {
+		return resourceOIEOnlyFeatureError(authenticatorMethod)
+	}
+	if err := applyAuthenticatorMethod(ctx, d, m); err != nil {
+		return diag.Errorf("failed to update %s method of authenticator (%s): %v", d.Get("type"), d.Get("authenticator_id"), err)
+	}
+	return resourceAuthenticatorMethodRead(ctx, d, m)
+}
+
+// applyAuthenticatorMethod replaces the method settings when the configured
+// ones differ from the actual ones, then changes the method status if needed.
+func applyAuthenticatorMethod(ctx context.Context, d *schema.ResourceData, m interface{}) error {
+	authenticatorID := d.Get("authenticator_id").(string)
+	methodType := d.Get("type").(string)
+	client := getAPISupplementFromMetadata(m)
+	method, _, err := client.GetAuthenticatorMethod(ctx, authenticatorID, methodType)
+	if err != nil {
+		return err
+	}
+	settings, err := mergeAuthenticatorMethodSettings(d.Get("settings").(string), method.Settings)
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(settings, method.Settings) {
+		body := sdk.AuthenticatorMethod{
+			Type:     method.Type,
+			Status:   method.Status,
+			Settings: settings,
+		}
+		if _, _, err = client.UpdateAuthenticatorMethod(ctx, authenticatorID, methodType, body); err != nil {
+			return err
+		}
+	}
+	status := d.Get("status").(string)
+	if status == method.Status {
+		return nil
+	}
+	if status == statusActive {
+		_, _, err = client.ActivateAuthenticatorMethod(ctx, authenticatorID, methodType)
+	} else {
+		_, _, err = client.DeactivateAuthenticatorMethod(ctx, authenticatorID, methodType)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to change method status: %v", err)
+	}
+	return nil
+}
+
+// mergeAuthenticatorMethodSettings returns the actual settings overridden by
+// the configured ones.
+func mergeAuthenticatorMethodSettings(configured string, actual map[string]interface{}) (map[string]interface{}, error) {
+	if configured == "" {
+		return actual, nil
+	}
+	var settings map[string]interface{}
+	if err := json.Unmarshal([]byte(configured), &settings); err != nil {
+		return nil, err
+	}
+	merged := make(map[string]interface{}, len(actual)+len(settings))
+	for k, v := range actual {
+		merged[k] = v
+	}
+	for k, v := range settings {
+		merged[k] = v
+	}
+	return merged, nil
+}
+
+// flattenAuthenticatorMethodSettings returns the actual settings in JSON
+// format, limited to the configured ones if any, so that settings left to
+// their defaults don't show as drift.
+func flattenAuthenticatorMethodSettings(configured string, actual map[string]interface{}) (string, error) {
+	if len(actual) == 0 {
+		return "", nil
+	}
+	settings := actual
+	if configured != "" {
+		var keys map[string]interface{}
+		if err := json.Unmarshal([]byte(configured), &keys); err != nil {
+			return "", err
+		}
+		settings = make(map[string]interface{}, len(keys))
+		for k := range keys {
+			if v, ok := actual[k]; ok {
+				settings[k] = v
+			}
+		}
+	}
+	b, err := json.Marshal(settings)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
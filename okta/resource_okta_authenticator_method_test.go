@@ -0,0 +1,140 @@
+package okta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+func TestAccResourceOktaAuthenticatorMethod_crud(t *testing.T) {
+	resourceName := fmt.Sprintf("%s.test", authenticatorMethod)
+	config := `
+data "okta_authenticator" "test" {
+  key = "webauthn"
+}
+
+resource "okta_authenticator_method" "test" {
+  authenticator_id = data.okta_authenticator.test.id
+  type             = "webauthn"
+  settings = jsonencode({
+    userVerification = "%s"
+  })
+}
+`
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		CheckDestroy:      nil,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, "REQUIRED"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "status", statusActive),
+					resource.TestCheckResourceAttr(resourceName, "type", "webauthn"),
+					testAttributeJSON(resourceName, "settings", `{"userVerification":"REQUIRED"}`),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, "PREFERRED"),
+				Check: resource.ComposeTestCheckFunc(
+					testAttributeJSON(resourceName, "settings", `{"userVerification":"PREFERRED"}`),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceAuthenticatorMethodCreate(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+	method := &sdk.AuthenticatorMethod{
+		Type:     "webauthn",
+		Status:   statusActive,
+		Settings: map[string]interface{}{"userVerification": "DISCOURAGED", "attachment": "ANY"},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/okta-organization", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"pipeline":"idx"}`)
+	})
+	mux.HandleFunc("/api/v1/authenticators/aut1/methods/webauthn", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == http.MethodPut {
+			var body sdk.AuthenticatorMethod
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			method.Settings = body.Settings
+			b, _ := json.Marshal(body.Settings)
+			requests = append(requests, fmt.Sprintf("PUT %s", b))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(method)
+	})
+	mux.HandleFunc("/api/v1/authenticators/aut1/methods/webauthn/lifecycle/deactivate", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		method.Status = statusInactive
+		requests = append(requests, "POST deactivate")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(method)
+	})
+	m := newTestConfig(t, mux)
+
+	d := schema.TestResourceDataRaw(t, resourceAuthenticatorMethod().Schema, map[string]interface{}{
+		"authenticator_id": "aut1",
+		"type":             "webauthn",
+		"status":           statusInactive,
+		"settings":         `{"userVerification":"REQUIRED"}`,
+	})
+	if diags := resourceAuthenticatorMethodCreate(context.Background(), d, m); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	expected := []string{
+		`PUT {"attachment":"ANY","userVerification":"REQUIRED"}`,
+		"POST deactivate",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("expected requests %v, got %v", expected, requests)
+	}
+	if d.Id() != "aut1/webauthn" {
+		t.Errorf("expected ID aut1/webauthn, got %s", d.Id())
+	}
+	if status := d.Get("status").(string); status != statusInactive {
+		t.Errorf("expected status %s, got %s", statusInactive, status)
+	}
+	if settings := d.Get("settings").(string); settings != `{"userVerification":"REQUIRED"}` {
+		t.Errorf("expected only the configured settings in state, got %s", settings)
+	}
+
+	requests = nil
+	if diags := resourceAuthenticatorMethodUpdate(context.Background(), d, m); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if len(requests) != 0 {
+		t.Errorf("expected no changes when the method is up to date, got %v", requests)
+	}
+}
+
+func TestResourceAuthenticatorMethodStatus(t *testing.T) {
+	status := resourceAuthenticatorMethod().Schema["status"]
+	for _, value := range []string{statusActive, statusInactive} {
+		if diags := status.ValidateDiagFunc(value, cty.GetAttrPath("status")); diags.HasError() {
+			t.Errorf("expected %s to be valid, got %v", value, diags)
+		}
+	}
+	if diags := status.ValidateDiagFunc("active", cty.GetAttrPath("status")); !diags.HasError() {
+		t.Error("expected an error for a lowercase status")
+	}
+}
@@ -26,7 +26,10 @@ func resourceEventHook() *schema.Resource {
 			"events": {
 				Type:     schema.TypeSet,
 				Required: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: stringIsEventHookEventType,
+				},
 			},
 			"headers": {
 				Type:     schema.TypeSet,
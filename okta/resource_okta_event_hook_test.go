@@ -3,12 +3,18 @@ package okta
 import (
 	"context"
 	"fmt"
+	"os"
+	"sort"
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/okta/terraform-provider-okta/okta/internal/hookreceiver"
+	"github.com/okta/terraform-provider-okta/okta/internal/mockokta"
 	"github.com/okta/terraform-provider-okta/sdk"
 )
 
@@ -111,6 +117,156 @@ func TestAccResourceOktaEventHook_crud(t *testing.T) {
 	})
 }
 
+// TestAccResourceOktaEventHook_verification verifies a hook delivering to a
+// local receiver, which only the mock Okta org can reach.
+func TestAccResourceOktaEventHook_verification(t *testing.T) {
+	if os.Getenv("OKTA_MOCK_TF_ACC") == "" {
+		t.Skipf("%q test is only run against the mock Okta org, see .github/CONTRIBUTING.md#acceptance-tests-with-the-mock-okta-org", t.Name())
+	}
+	receiver := hookreceiver.New(hookreceiver.Config{
+		AuthKey:   "Authorization",
+		AuthValue: "secret",
+		Headers:   map[string]string{"x-env": "test"},
+	})
+	t.Cleanup(receiver.Close)
+	config := fmt.Sprintf(`
+resource "okta_event_hook" "test" {
+  name   = "testAcc_%[1]d"
+  events = ["user.lifecycle.create"]
+  channel = {
+    type    = "HTTP"
+    version = "1.0.0"
+    uri     = %[2]q
+  }
+  auth = {
+    type  = "HEADER"
+    key   = "Authorization"
+    value = "secret"
+  }
+  headers {
+    key   = "x-env"
+    value = "test"
+  }
+}
+
+resource "okta_event_hook_verification" "test" {
+  event_hook_id = okta_event_hook.test.id
+}
+
+resource "okta_user" "test" {
+  first_name = "TestAcc"
+  last_name  = "Smith"
+  login      = "testAcc-%[1]d@example.com"
+  email      = "testAcc-%[1]d@example.com"
+
+  depends_on = [okta_event_hook_verification.test]
+}`, acctest.RandInt(), receiver.URL)
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: func(s *terraform.State) error {
+					if !receiver.Verified() {
+						return fmt.Errorf("expected the receiver to be verified")
+					}
+					userID := s.RootModule().Resources["okta_user.test"].Primary.ID
+					for _, event := range receiver.Events() {
+						if event.EventType == "user.lifecycle.create" && event.Target[0].ID == userID {
+							return nil
+						}
+					}
+					return fmt.Errorf("expected the creation of user %s to be delivered, got %v", userID, receiver.Events())
+				},
+			},
+		},
+	})
+}
+
+func TestResourceEventHookEventsValidation(t *testing.T) {
+	if !sort.StringsAreSorted(eventHookEventTypes) {
+		t.Fatal("expected the event types eligible for event hooks to be sorted")
+	}
+	r := resourceEventHook()
+	validate := func(events ...interface{}) diag.Diagnostics {
+		return r.Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":    "test",
+			"events":  events,
+			"channel": map[string]interface{}{"version": "1.0.0", "uri": "https://example.com/test"},
+		}))
+	}
+	if diags := validate("user.lifecycle.create", "group.user_membership.add"); diags.HasError() {
+		t.Errorf("expected eligible event types to be valid, got %v", diags)
+	}
+	diags := validate("user.lifecycle.create", "user.lifecycle.update")
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "'user.lifecycle.update' is not an event type eligible for event hooks") || !strings.Contains(diags[0].Summary, "user.lifecycle.delete.initiated") {
+		t.Errorf("expected an error listing the eligible event types of user.lifecycle, got %v", diags)
+	}
+	diags = validate("app.oauth2.token.grant")
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "event-hook-eligible") {
+		t.Errorf("expected an error pointing at the eligible event types, got %v", diags)
+	}
+}
+
+func TestResourceEventHookDelivery(t *testing.T) {
+	server := mockokta.NewServer()
+	t.Cleanup(server.Close)
+	receiver := hookreceiver.New(hookreceiver.Config{
+		AuthKey:   "Authorization",
+		AuthValue: "secret",
+		Headers:   map[string]string{"x-env": "test"},
+	})
+	t.Cleanup(receiver.Close)
+	m := newTestConfig(t, server)
+	ctx := context.Background()
+
+	// newHook creates a hook of the receiver with the secret
+	newHook := func(secret string) string {
+		d := resourceEventHook().Data(nil)
+		_ = d.Set("name", "test-"+secret)
+		_ = d.Set("status", statusActive)
+		_ = d.Set("events", convertStringSliceToSet([]string{"user.lifecycle.create"}))
+		_ = d.Set("channel", map[string]interface{}{"type": "HTTP", "version": "1.0.0", "uri": receiver.URL})
+		_ = d.Set("auth", map[string]interface{}{"type": "HEADER", "key": "Authorization", "value": secret})
+		_ = d.Set("headers", testMakeEventHookHeadersSet([]*sdk.EventHookChannelConfigHeader{{Key: "x-env", Value: "test"}}))
+		if diags := resourceEventHookCreate(ctx, d, m); diags.HasError() {
+			t.Fatalf("failed to create event hook: %v", diags)
+		}
+		return d.Id()
+	}
+	verify := func(id string) diag.Diagnostics {
+		d := resourceEventHookVerification().Data(nil)
+		_ = d.Set("event_hook_id", id)
+		return resourceEventHookVerificationCreate(ctx, d, m)
+	}
+
+	if diags := verify(newHook("wrong")); !diags.HasError() || receiver.Refused() != 1 {
+		t.Errorf("expected the verification of the hook with the wrong secret to fail, got %v", diags)
+	}
+	id := newHook("secret")
+	if diags := verify(id); diags.HasError() || !receiver.Verified() {
+		t.Fatalf("failed to verify event hook: %v", diags)
+	}
+	hook, _, err := getOktaClientFromMetadata(m).EventHook.GetEventHook(ctx, id)
+	if err != nil || hook.VerificationStatus != "VERIFIED" {
+		t.Fatalf("expected the hook to be verified, got %v: %v", hook, err)
+	}
+
+	user, _, err := getOktaClientFromMetadata(m).User.CreateUser(ctx, sdk.CreateUserRequest{
+		Profile: &sdk.UserProfile{"login": "jane@example.com", "email": "jane@example.com", "firstName": "Jane", "lastName": "Doe"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	events := receiver.Events()
+	if len(events) != 1 || events[0].EventType != "user.lifecycle.create" || events[0].Target[0].ID != user.Id {
+		t.Errorf("expected the creation of the user to be delivered once, got %v", events)
+	}
+}
+
 func eventHookExists(id string) (bool, error) {
 	client := sdkV2ClientForTest()
 	eh, resp, err := client.EventHook.GetEventHook(context.Background(), id)
@@ -3,6 +3,8 @@ package okta
 import (
 	"context"
 	"fmt"
+	"math"
+	"net/http"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -41,6 +43,19 @@ func resourceGroupMemberships() *schema.Resource {
 				Default:     false,
 				Description: "The resource concerns itself with all users added/deleted to the group; even those managed outside of the resource.",
 			},
+			"authoritative": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       false,
+				ConflictsWith: []string{"track_all_users"},
+				Description:   "The resource is the source of truth for the group's members. Members that are not in `users`, such as users added through the admin console, show as drift and are removed from the group on apply.",
+			},
+			"max_removals": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ValidateDiagFunc: intBetween(1, math.MaxInt32),
+				Description:      "Safety cap that fails the apply if more than this number of users would be removed from the group. No limit when unset.",
+			},
 		},
 	}
 }
@@ -50,6 +65,18 @@ func resourceGroupMembershipsCreate(ctx context.Context, d *schema.ResourceData,
 	users := convertInterfaceToStringSetNullable(d.Get("users"))
 	client := getOktaClientFromMetadata(m)
 
+	if d.Get("authoritative").(bool) {
+		extraUsers, err := listExtraGroupMembers(ctx, m, groupId, users)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if err := checkMaxRemovals(d, groupId, len(extraUsers)); err != nil {
+			return diag.FromErr(err)
+		}
+		if err := removeGroupMembers(ctx, client, groupId, extraUsers); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 	if len(users) == 0 {
 		d.SetId(groupId)
 		return nil
@@ -89,6 +116,31 @@ func resourceGroupMembershipsRead(ctx context.Context, d *schema.ResourceData, m
 	oldUsers := convertInterfaceToStringSetNullable(d.Get("users"))
 	trackAllUsers := d.Get("track_all_users").(bool)
 
+	// Authoritative behavior, the group's members are the users.
+	if d.Get("authoritative").(bool) {
+		var groupNotFound bool
+		members, err := collectPages(ctx, func() ([]*sdk.User, *sdk.Response, error) {
+			users, resp, err := client.Group.ListGroupUsers(ctx, groupId, &query.Params{Limit: maxGroupUsersPageSize})
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				groupNotFound = true
+			}
+			return users, resp, suppressErrorOn404(resp, err)
+		})
+		if err != nil {
+			return diag.Errorf("An error occured listing users for group %q, error: %+v", groupId, err)
+		}
+		if groupNotFound {
+			d.SetId("")
+			return nil
+		}
+		userIDs := make([]string, len(members))
+		for i := range members {
+			userIDs[i] = members[i].Id
+		}
+		_ = d.Set("users", convertStringSliceToSet(userIDs))
+		return nil
+	}
+
 	// New behavior, tracking all users.
 	if trackAllUsers {
 		changed, newUserIDs, err := checkIfUsersHaveChanged(ctx, client, groupId, &oldUsers)
@@ -138,19 +190,64 @@ func resourceGroupMembershipsUpdate(ctx context.Context, d *schema.ResourceData,
 	usersToAdd := convertInterfaceArrToStringArr(newSet.Difference(oldSet).List())
 	usersToRemove := convertInterfaceArrToStringArr(oldSet.Difference(newSet).List())
 
+	// The refresh before the switch to authoritative didn't read the members
+	// that aren't in users, they're removed now rather than on the next apply.
+	if d.HasChange("authoritative") && d.Get("authoritative").(bool) {
+		extraUsers, err := listExtraGroupMembers(ctx, m, groupId, convertInterfaceArrToStringArr(newSet.List()))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		for _, id := range extraUsers {
+			if !contains(usersToRemove, id) {
+				usersToRemove = append(usersToRemove, id)
+			}
+		}
+	}
+
+	if err := checkMaxRemovals(d, groupId, len(usersToRemove)); err != nil {
+		return diag.FromErr(err)
+	}
+
 	err := addGroupMembers(ctx, client, groupId, usersToAdd)
 	if err != nil {
-		diag.FromErr(err)
+		return diag.FromErr(err)
 	}
 
 	err = removeGroupMembers(ctx, client, groupId, usersToRemove)
 	if err != nil {
-		diag.FromErr(err)
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
+// listExtraGroupMembers returns the IDs of the group's members that aren't
+// in users.
+func listExtraGroupMembers(ctx context.Context, m interface{}, groupId string, users []string) ([]string, error) {
+	members, err := listGroupUsers(ctx, m, groupId)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list users for group (%s) from API, error: %+v", groupId, err)
+	}
+	configured := toStrIndexedMap(&users)
+	var extraUsers []string
+	for _, member := range members {
+		if _, found := (*configured)[member.Id]; !found {
+			extraUsers = append(extraUsers, member.Id)
+		}
+	}
+	return extraUsers, nil
+}
+
+// checkMaxRemovals fails when removing count users from the group would
+// exceed the max_removals safety cap.
+func checkMaxRemovals(d *schema.ResourceData, groupId string, count int) error {
+	maxRemovals, ok := d.GetOk("max_removals")
+	if !ok || count <= maxRemovals.(int) {
+		return nil
+	}
+	return fmt.Errorf("removing %d users from group (%s) exceeds max_removals of %d, no changes were made", count, groupId, maxRemovals.(int))
+}
+
 // checkIfUsersHaveChanged If the function returns true then users have been
 // changed and the returned user ids should be considered the new set of users.
 // Returns error for API errors. Returns false if no users have changed and the
@@ -168,33 +265,19 @@ func checkIfUsersHaveChanged(ctx context.Context, client *sdk.Client, groupId st
 	// Collect all user ids that are returned from the API
 	usersFromAPI := []string{}
 
-	groupUsers, resp, err := client.Group.ListGroupUsers(ctx, groupId, &query.Params{Limit: defaultPaginationLimit})
-	if err := suppressErrorOn404(resp, err); err != nil {
-		return false, &noop, fmt.Errorf("unable to list users for group (%s) from API, error: %+v", groupId, err)
-	}
-
-	for _, user := range groupUsers {
+	err := forEachPage(ctx, func() ([]*sdk.User, *sdk.Response, error) {
+		groupUsers, resp, err := client.Group.ListGroupUsers(ctx, groupId, &query.Params{Limit: maxGroupUsersPageSize})
+		return groupUsers, resp, suppressErrorOn404(resp, err)
+	}, func(user *sdk.User) bool {
 		// if the new user id is not in the old users map then the list of users has changed
 		if _, found := (*oldUsers)[user.Id]; !found {
 			changed = true
 		}
 		usersFromAPI = append(usersFromAPI, user.Id)
-	}
-
-	for resp.HasNextPage() {
-		groupUsers = nil
-		resp, err = resp.Next(context.Background(), &groupUsers)
-		if err != nil {
-			return false, &noop, fmt.Errorf("unable to list users for group (%s) from API, error: %+v", groupId, err)
-		}
-
-		for _, user := range groupUsers {
-			// if the new user id is not in the old users map then the list of users has changed
-			if _, found := (*oldUsers)[user.Id]; !found {
-				changed = true
-			}
-			usersFromAPI = append(usersFromAPI, user.Id)
-		}
+		return true
+	})
+	if err != nil {
+		return false, &noop, fmt.Errorf("unable to list users for group (%s) from API, error: %+v", groupId, err)
 	}
 	if len(*oldUsers) != len(usersFromAPI) {
 		changed = true
@@ -223,34 +306,20 @@ func checkIfUsersHaveBeenRemoved(ctx context.Context, client *sdk.Client, groupI
 	// all of our user ids and no longer have to make API calls.
 	oldUsers := toStrIndexedMap(users)
 
-	groupUsers, resp, err := client.Group.ListGroupUsers(ctx, groupId, &query.Params{Limit: defaultPaginationLimit})
-	if err := suppressErrorOn404(resp, err); err != nil {
-		return false, &noop, fmt.Errorf("unable to list users for group (%s) from API, error: %+v", groupId, err)
-	}
-
-	for _, user := range groupUsers {
+	err := forEachPage(ctx, func() ([]*sdk.User, *sdk.Response, error) {
+		groupUsers, resp, err := client.Group.ListGroupUsers(ctx, groupId, &query.Params{Limit: maxGroupUsersPageSize})
+		return groupUsers, resp, suppressErrorOn404(resp, err)
+	}, func(user *sdk.User) bool {
 		// Deleting user from API from the old users map
 		delete(*oldUsers, user.Id)
-		if len(*oldUsers) == 0 {
-			// All old users have been accounted for.
-			return false, &noop, nil
-		}
+		return len(*oldUsers) > 0
+	})
+	if err != nil {
+		return false, &noop, fmt.Errorf("unable to list users for group (%s) from API, error: %+v", groupId, err)
 	}
-
-	for resp.HasNextPage() {
-		groupUsers = nil
-		resp, err = resp.Next(context.Background(), &groupUsers)
-		if err != nil {
-			return false, &noop, fmt.Errorf("unable to list users for group (%s) from API, error: %+v", groupId, err)
-		}
-		for _, user := range groupUsers {
-			// Deleting user from API from the old users map
-			delete(*oldUsers, user.Id)
-			if len(*oldUsers) == 0 {
-				// All old users have been accounted for.
-				return false, &noop, nil
-			}
-		}
+	if len(*oldUsers) == 0 {
+		// All old users have been accounted for.
+		return false, &noop, nil
 	}
 
 	// Any old users left are the IDs that have been removed from group.
@@ -270,7 +339,7 @@ func checkIfUsersHaveBeenRemoved(ctx context.Context, client *sdk.Client, groupI
 }
 
 func checkIfGroupHasUsers(ctx context.Context, client *sdk.Client, groupId string, users []string) (bool, error) {
-	groupUsers, resp, err := client.Group.ListGroupUsers(ctx, groupId, &query.Params{Limit: defaultPaginationLimit})
+	groupUsers, resp, err := client.Group.ListGroupUsers(ctx, groupId, &query.Params{Limit: maxGroupUsersPageSize})
 	if err := suppressErrorOn404(resp, err); err != nil {
 		return false, fmt.Errorf("unable to return membership for group (%s) from API", groupId)
 	}
@@ -1,11 +1,15 @@
 package okta
 
 import (
+	"context"
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/okta/terraform-provider-okta/okta/internal/mockokta"
+	"github.com/okta/terraform-provider-okta/sdk"
 )
 
 func TestAccResourceOktaGroupMemberships_crud(t *testing.T) {
@@ -33,6 +37,80 @@ func TestAccResourceOktaGroupMemberships_crud(t *testing.T) {
 	})
 }
 
+// TestAccResourceOktaGroupMemberships_Authoritative checks an authoritative
+// resource removes members that aren't configured, unless doing so exceeds
+// max_removals, and shows members added outside of Terraform as drift.
+func TestAccResourceOktaGroupMemberships_Authoritative(t *testing.T) {
+	mgr := newFixtureManager(groupMemberships, t.Name())
+	config := func(users, maxRemovals string) string {
+		return mgr.ConfigReplace(fmt.Sprintf(`
+resource "okta_group" "test" {
+  name        = "testAcc_replace_with_uuid"
+  description = "testing, testing"
+}
+resource "okta_user" "test" {
+  count      = 3
+  first_name = "TestAcc"
+  last_name  = "Smith${count.index}"
+  login      = "testAcc${count.index}-replace_with_uuid@example.com"
+  email      = "testAcc${count.index}-replace_with_uuid@example.com"
+}
+resource "okta_group_memberships" "test" {
+  group_id      = okta_group.test.id
+  users         = %s
+  authoritative = true
+  %s
+}
+`, users, maxRemovals))
+	}
+	var groupID, userID string
+	resourceName := fmt.Sprintf("%s.test", groupMemberships)
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		CheckDestroy:      checkUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config("okta_user.test[*].id", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "users.#", "3"),
+				),
+			},
+			{
+				Config:      config("[okta_user.test[0].id]", "max_removals = 1"),
+				ExpectError: regexp.MustCompile(`exceeds max_removals of 1`),
+			},
+			{
+				Config: config("[okta_user.test[0].id]", "max_removals = 2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "users.#", "1"),
+					resource.TestCheckResourceAttrWith(resourceName, "group_id", func(value string) error {
+						groupID = value
+						return nil
+					}),
+					resource.TestCheckResourceAttrWith("okta_user.test.1", "id", func(value string) error {
+						userID = value
+						return nil
+					}),
+				),
+			},
+			{
+				PreConfig: func() {
+					_, err := sdkV2ClientForTest().Group.AddUserToGroup(context.Background(), groupID, userID)
+					if err != nil {
+						t.Fatalf("failed to add user to group: %v", err)
+					}
+				},
+				Config:             config("[okta_user.test[0].id]", "max_removals = 2"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
 // TestAccResourceOktaGroupMemberships_Issue1072 addresses https://github.com/okta/terraform-provider-okta/issues/1072
 func TestAccResourceOktaGroupMemberships_Issue1072(t *testing.T) {
 	oktaResourceTest(t, resource.TestCase{
@@ -306,3 +384,60 @@ resource "okta_user" "test-%03d" {
 }
 `, i, i, i, i)
 }
+
+func TestResourceGroupMembershipsSwitchToAuthoritative(t *testing.T) {
+	server := mockokta.NewServer()
+	t.Cleanup(server.Close)
+	m := newTestConfig(t, server)
+	ctx := context.Background()
+	client := getOktaClientFromMetadata(m)
+
+	group, _, err := client.Group.CreateGroup(ctx, sdk.Group{Profile: &sdk.GroupProfile{Name: "Engineering"}})
+	if err != nil {
+		t.Fatalf("failed to create group: %v", err)
+	}
+	var userIDs []string
+	for i := 0; i < 3; i++ {
+		login := fmt.Sprintf("user%d@example.com", i)
+		user, _, err := client.User.CreateUser(ctx, sdk.CreateUserRequest{
+			Profile: &sdk.UserProfile{"login": login, "email": login, "firstName": "User", "lastName": fmt.Sprint(i)},
+		}, nil)
+		if err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+		if _, err := client.Group.AddUserToGroup(ctx, group.Id, user.Id); err != nil {
+			t.Fatalf("failed to add user to group: %v", err)
+		}
+		userIDs = append(userIDs, user.Id)
+	}
+
+	r := resourceGroupMemberships()
+	d := r.Data(nil)
+	d.SetId(group.Id)
+	_ = d.Set("group_id", group.Id)
+	_ = d.Set("users", convertStringSliceToSet(userIDs[:1]))
+	if diags := r.ReadContext(ctx, d, m); diags.HasError() {
+		t.Fatalf("failed to read: %v", diags)
+	}
+	config := map[string]interface{}{"group_id": group.Id, "users": []interface{}{userIDs[0]}, "authoritative": true, "max_removals": 1}
+	diags := r.UpdateContext(ctx, updateResourceData(t, r, d, config, m), m)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "removing 2 users") {
+		t.Errorf("expected the extra members to exceed max_removals, got %v", diags)
+	}
+	config["max_removals"] = 2
+	d = updateResourceData(t, r, d, config, m)
+	if diags := r.UpdateContext(ctx, d, m); diags.HasError() {
+		t.Fatalf("failed to update: %v", diags)
+	}
+	members, err := listGroupUserIDs(ctx, m, group.Id)
+	if err != nil || len(members) != 1 || members[0] != userIDs[0] {
+		t.Errorf("expected the extra members to be removed on the switch to authoritative, got %v: %v", members, err)
+	}
+
+	if _, err := client.Group.DeleteGroup(ctx, group.Id); err != nil {
+		t.Fatalf("failed to delete group: %v", err)
+	}
+	if diags := r.ReadContext(ctx, d, m); diags.HasError() || d.Id() != "" {
+		t.Errorf("expected the membership to be removed from state with its group, got %q: %v", d.Id(), diags)
+	}
+}
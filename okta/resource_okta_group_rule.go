@@ -2,12 +2,14 @@ package okta
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/internal/expression"
 	"github.com/okta/terraform-provider-okta/sdk"
 	"github.com/okta/terraform-provider-okta/sdk/query"
 )
@@ -41,8 +43,9 @@ func resourceGroupRule() *schema.Resource {
 				Optional: true,
 			},
 			"expression_value": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: stringIsOktaExpression("user"),
 			},
 			"status": statusSchema,
 			"remove_assigned_users": {
@@ -57,15 +60,47 @@ func resourceGroupRule() *schema.Resource {
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
 		},
-		CustomizeDiff: customdiff.ForceNewIf("status", func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) bool {
-			g, _, _ := getOktaClientFromMetadata(meta).Group.GetGroupRule(ctx, d.Id(), nil)
-			if g == nil {
-				return false
-			}
-			_ = d.SetNew("status", g.Status)
-			return d.Get("status").(string) == statusInvalid
-		}),
+		CustomizeDiff: customdiff.All(
+			customdiff.ForceNewIf("status", func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) bool {
+				g, _, _ := getOktaClientFromMetadata(meta).Group.GetGroupRule(ctx, d.Id(), nil)
+				if g == nil {
+					return false
+				}
+				_ = d.SetNew("status", g.Status)
+				return d.Get("status").(string) == statusInvalid
+			}),
+			validateGroupRuleExpressionAttributes,
+		),
+	}
+}
+
+// validateGroupRuleExpressionAttributes checks the user attributes the
+// expression reads exist in the org's default user schema. The schema can't
+// always be read, e.g. with a limited admin's API token, in which case the
+// check is skipped.
+func validateGroupRuleExpressionAttributes(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("expression_value") || !d.HasChange("expression_value") {
+		return nil
+	}
+	n, err := expression.Validate(d.Get("expression_value").(string), "user")
+	if err != nil {
+		return nil
+	}
+	attrs := expression.Attributes(n, "user")
+	if len(attrs) == 0 {
+		return nil
 	}
+	us, _, err := getOktaClientFromMetadata(m).UserSchema.GetUserSchema(ctx, "default")
+	if err != nil {
+		logger(m).Warn("failed to get user schema, skipping group rule expression attribute validation", "error", err)
+		return nil
+	}
+	for _, attr := range attrs {
+		if userSchemaBaseAttribute(us, attr) == nil && userSchemaCustomAttribute(us, attr) == nil {
+			return fmt.Errorf("expression_value references 'user.%s' which is not an attribute of the user schema", attr)
+		}
+	}
+	return nil
 }
 
 func resourceGroupRuleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
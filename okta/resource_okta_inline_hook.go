@@ -30,6 +30,7 @@ func resourceInlineHook() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: validateInlineHookContract,
 		// For those familiar with Terraform schemas be sure to check the base hook schema and/or
 		// the examples in the documentation
 		Schema: map[string]*schema.Schema{
@@ -96,7 +97,7 @@ func resourceInlineHookCreate(ctx context.Context, d *schema.ResourceData, m int
 	if err != nil {
 		return diag.Errorf("failed to change inline hook's status: %v", err)
 	}
-	return resourceInlineHookRead(ctx, d, m)
+	return append(inlineHookVersionWarnings(d), resourceInlineHookRead(ctx, d, m)...)
 }
 
 func resourceInlineHookRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -134,7 +135,7 @@ func resourceInlineHookUpdate(ctx context.Context, d *schema.ResourceData, m int
 	if err != nil {
 		return diag.Errorf("failed to change inline hook's status: %v", err)
 	}
-	return resourceInlineHookRead(ctx, d, m)
+	return append(inlineHookVersionWarnings(d), resourceInlineHookRead(ctx, d, m)...)
 }
 
 func resourceInlineHookDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -2,10 +2,14 @@ package okta
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccResourceOktaInlineHook_crud(t *testing.T) {
@@ -109,3 +113,58 @@ func inlineHookExists(id string) (bool, error) {
 	}
 	return resp.StatusCode != http.StatusNotFound, nil
 }
+
+func TestResourceInlineHookContract(t *testing.T) {
+	for hookType, contract := range inlineHookContracts {
+		if !json.Valid([]byte(contract.samplePayload)) {
+			t.Errorf("expected the sample payload of %s to be valid JSON", hookType)
+		}
+	}
+	r := resourceInlineHook()
+	tests := []struct {
+		hookType string
+		version  string
+		channel  map[string]interface{}
+		err      string
+	}{
+		{"com.okta.oauth2.tokens.transform", "1.0.1", map[string]interface{}{"version": "1.0.0", "uri": "https://example.com/test"}, ""},
+		{"com.okta.user.pre-registration", "1.0.3", map[string]interface{}{"version": "1.0.0", "uri": "https://example.com/test", "method": "POST"}, ""},
+		{"com.okta.telephony.provider", "1.0.2", map[string]interface{}{"version": "1.0.0", "uri": "https://example.com/test"}, ""},
+		{"com.okta.import.transform", "1.0.2", map[string]interface{}{"version": "2.0.0", "uri": "https://example.com/test"}, ""},
+		{"com.okta.saml.tokens.transform", "1.0.2", map[string]interface{}{"version": "1.0.0", "uri": "https://example.com/test", "method": "GET"}, "channel method 'GET' is not supported"},
+		{"com.okta.user.password.import", "1.0.0", map[string]interface{}{"version": "1.0.0", "uri": "https://example.com/test"}, "'com.okta.user.password.import' is not an inline hook type"},
+	}
+	for _, test := range tests {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":    "test",
+			"type":    test.hookType,
+			"version": test.version,
+			"channel": test.channel,
+		})
+		_, err := r.SimpleDiff(context.Background(), &terraform.InstanceState{}, config, nil)
+		if test.err == "" && err != nil {
+			t.Errorf("expected %s %s to be valid, got %v", test.hookType, test.version, err)
+		}
+		if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+			t.Errorf("expected error %q for %s %s, got %v", test.err, test.hookType, test.version, err)
+		}
+	}
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":    "test",
+		"type":    "com.okta.telephony.provider",
+		"version": "1.0.2",
+		"channel": map[string]interface{}{"version": "2.0.0", "uri": "https://example.com/test"},
+	})
+	diags := inlineHookVersionWarnings(d)
+	if len(diags) != 2 || diags.HasError() ||
+		!strings.Contains(diags[0].Summary, "Version '1.0.2' is not a known version") ||
+		!strings.Contains(diags[1].Summary, "Channel version '2.0.0' is not a known version") ||
+		!strings.Contains(diags[0].Detail, "https://developer.okta.com/docs/reference/telephony-hook/") {
+		t.Errorf("expected warnings about the unknown versions, got %+v", diags)
+	}
+	_ = d.Set("version", "1.0.0")
+	_ = d.Set("channel", map[string]interface{}{"version": "1.0.0", "uri": "https://example.com/test"})
+	if diags := inlineHookVersionWarnings(d); len(diags) != 0 {
+		t.Errorf("expected no warnings for known versions, got %+v", diags)
+	}
+}
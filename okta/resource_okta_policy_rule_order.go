@@ -0,0 +1,236 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+func resourcePolicyRuleOrder() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourcePolicyRuleOrderCreate,
+		ReadContext:   resourcePolicyRuleOrderRead,
+		UpdateContext: resourcePolicyRuleOrderUpdate,
+		DeleteContext: resourcePolicyRuleOrderDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				_ = d.Set("policy_id", d.Id())
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		Schema: map[string]*schema.Schema{
+			"policy_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the policy whose rules are ordered",
+			},
+			"rule_ids": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "IDs of every rule of the policy, other than the default rule, from highest to lowest priority",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourcePolicyRuleOrderCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	policyID := d.Get("policy_id").(string)
+	if err := orderPolicyRules(ctx, m, policyID, convertInterfaceToStringArr(d.Get("rule_ids"))); err != nil {
+		return diag.Errorf("failed to order rules of policy (%s): %v", policyID, err)
+	}
+	d.SetId(policyID)
+	return resourcePolicyRuleOrderRead(ctx, d, m)
+}
+
+func resourcePolicyRuleOrderRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	rules, resp, err := getAPISupplementFromMetadata(m).ListPolicyRuleSummaries(ctx, d.Id())
+	if err := suppressErrorOn404(resp, err); err != nil {
+		return diag.Errorf("failed to list rules of policy (%s): %v", d.Id(), err)
+	}
+	if rules == nil {
+		d.SetId("")
+		return nil
+	}
+	_ = d.Set("policy_id", d.Id())
+	_ = d.Set("rule_ids", policyRuleIDs(orderedPolicyRules(rules)))
+	return nil
+}
+
+func resourcePolicyRuleOrderUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := orderPolicyRules(ctx, m, d.Id(), convertInterfaceToStringArr(d.Get("rule_ids"))); err != nil {
+		return diag.Errorf("failed to order rules of policy (%s): %v", d.Id(), err)
+	}
+	return resourcePolicyRuleOrderRead(ctx, d, m)
+}
+
+// resourcePolicyRuleOrderDelete leaves the rules where they are, there is no
+// order to restore.
+func resourcePolicyRuleOrderDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return nil
+}
+
+// orderPolicyRules moves the rules of the policy into the order of ruleIDs
+// with as few moves as possible, see policyRuleMoves.
+func orderPolicyRules(ctx context.Context, m interface{}, policyID string, ruleIDs []string) error {
+	client := getAPISupplementFromMetadata(m)
+	rules, _, err := client.ListPolicyRuleSummaries(ctx, policyID)
+	if err != nil {
+		return err
+	}
+	current := orderedPolicyRules(rules)
+	moves, err := policyRuleMoves(current, ruleIDs)
+	if err != nil {
+		return err
+	}
+	for _, move := range moves {
+		logger(m).Info("moving policy rule", "policy_id", policyID, "rule_id", move.ruleID, "priority", move.priority)
+		if _, err := client.SetPolicyRulePriority(ctx, policyID, move.ruleID, move.priority); err != nil {
+			return fmt.Errorf("failed to move rule (%s) to priority %d: %v", move.ruleID, move.priority, err)
+		}
+	}
+	return nil
+}
+
+// orderedPolicyRules returns the rules, other than system rules such as the
+// default rule which is always last, from highest to lowest priority.
+func orderedPolicyRules(rules []sdk.PolicyRuleSummary) []sdk.PolicyRuleSummary {
+	var result []sdk.PolicyRuleSummary
+	for _, rule := range rules {
+		if rule.System != nil && *rule.System {
+			continue
+		}
+		result = append(result, rule)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Priority < result[j].Priority
+	})
+	return result
+}
+
+func policyRuleIDs(rules []sdk.PolicyRuleSummary) []string {
+	ids := make([]string, len(rules))
+	for i, rule := range rules {
+		ids[i] = rule.Id
+	}
+	return ids
+}
+
+type policyRuleMove struct {
+	ruleID   string
+	priority int64
+}
+
+// policyRuleMoves returns the moves that reorder the current rules into the
+// desired order. Setting a rule's priority inserts it at that position and
+// shifts the rules below it, so the rules forming the longest run already in
+// the desired relative order stay where they are and each other rule is
+// moved, in desired order, to directly after its desired predecessor.
+func policyRuleMoves(current []sdk.PolicyRuleSummary, desired []string) ([]policyRuleMove, error) {
+	positions := make(map[string]int, len(current))
+	for i, rule := range current {
+		positions[rule.Id] = i
+	}
+	seen := make(map[string]bool, len(desired))
+	sequence := make([]int, len(desired))
+	for i, id := range desired {
+		if seen[id] {
+			return nil, fmt.Errorf("rule (%s) is listed more than once", id)
+		}
+		seen[id] = true
+		position, ok := positions[id]
+		if !ok {
+			return nil, fmt.Errorf("rule (%s) is not a rule of the policy", id)
+		}
+		sequence[i] = position
+	}
+	var missing []string
+	for _, rule := range current {
+		if !seen[rule.Id] {
+			missing = append(missing, fmt.Sprintf("%s (%s)", rule.Name, rule.Id))
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("every rule of the policy other than the default rule must be listed in rule_ids, missing: %s", strings.Join(missing, ", "))
+	}
+
+	keep := longestIncreasingSubsequence(sequence)
+	order := policyRuleIDs(current)
+	var moves []policyRuleMove
+	for i, id := range desired {
+		if keep[i] {
+			continue
+		}
+		order = removeString(order, id)
+		index := 0
+		if i > 0 {
+			index = indexOfString(order, desired[i-1]) + 1
+		}
+		order = append(order[:index], append([]string{id}, order[index:]...)...)
+		moves = append(moves, policyRuleMove{ruleID: id, priority: int64(index + 1)})
+	}
+	return moves, nil
+}
+
+// longestIncreasingSubsequence returns which elements of sequence are part
+// of one of its longest strictly increasing subsequences.
+func longestIncreasingSubsequence(sequence []int) []bool {
+	// tails[k] is the index of the smallest tail of an increasing subsequence
+	// of length k+1, previous links each index to its predecessor
+	var tails []int
+	previous := make([]int, len(sequence))
+	for i, value := range sequence {
+		lo, hi := 0, len(tails)
+		for lo < hi {
+			mid := (lo + hi) / 2
+			if sequence[tails[mid]] < value {
+				lo = mid + 1
+			} else {
+				hi = mid
+			}
+		}
+		previous[i] = -1
+		if lo > 0 {
+			previous[i] = tails[lo-1]
+		}
+		if lo == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[lo] = i
+		}
+	}
+	result := make([]bool, len(sequence))
+	if len(tails) == 0 {
+		return result
+	}
+	for i := tails[len(tails)-1]; i >= 0; i = previous[i] {
+		result[i] = true
+	}
+	return result
+}
+
+func removeString(list []string, s string) []string {
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		if item != s {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func indexOfString(list []string, s string) int {
+	for i, item := range list {
+		if item == s {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,139 @@
+package okta
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+func TestPolicyRuleMoves(t *testing.T) {
+	tests := []struct {
+		current []string
+		desired []string
+		moves   []policyRuleMove
+		err     string
+	}{
+		{
+			current: []string{"a", "b", "c"},
+			desired: []string{"a", "b", "c"},
+		},
+		{
+			current: []string{"d", "a", "b", "c"},
+			desired: []string{"a", "b", "c", "d"},
+			moves:   []policyRuleMove{{"d", 4}},
+		},
+		{
+			current: []string{"b", "c", "d", "a"},
+			desired: []string{"a", "b", "c", "d"},
+			moves:   []policyRuleMove{{"a", 1}},
+		},
+		{
+			current: []string{"a", "b", "c", "d", "e"},
+			desired: []string{"e", "d", "c", "b", "a"},
+			moves:   []policyRuleMove{{"e", 1}, {"d", 2}, {"c", 3}, {"b", 4}},
+		},
+		{
+			current: []string{"a", "b", "c", "d", "e", "f"},
+			desired: []string{"b", "a", "c", "f", "d", "e"},
+			moves:   []policyRuleMove{{"b", 1}, {"f", 4}},
+		},
+		{
+			current: []string{"a", "b"},
+			desired: []string{"a", "b", "a"},
+			err:     "rule (a) is listed more than once",
+		},
+		{
+			current: []string{"a", "b"},
+			desired: []string{"a", "b", "c"},
+			err:     "rule (c) is not a rule of the policy",
+		},
+		{
+			current: []string{"a", "b", "c"},
+			desired: []string{"c", "a"},
+			err:     "missing: rule b (b)",
+		},
+	}
+	for _, test := range tests {
+		current := make([]sdk.PolicyRuleSummary, len(test.current))
+		for i, id := range test.current {
+			current[i] = sdk.PolicyRuleSummary{Id: id, Name: "rule " + id, Priority: int64(i + 1)}
+		}
+		moves, err := policyRuleMoves(current, test.desired)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%v to %v: expected error %q, got %v", test.current, test.desired, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v to %v: unexpected error %v", test.current, test.desired, err)
+			continue
+		}
+		if !reflect.DeepEqual(moves, test.moves) {
+			t.Errorf("%v to %v: expected moves %v, got %v", test.current, test.desired, test.moves, moves)
+		}
+
+		// applying the moves, where setting a priority inserts the rule
+		// there, must result in the desired order
+		order := test.current
+		for _, move := range moves {
+			order = removeString(order, move.ruleID)
+			index := int(move.priority - 1)
+			order = append(order[:index], append([]string{move.ruleID}, order[index:]...)...)
+		}
+		if !reflect.DeepEqual(order, test.desired) {
+			t.Errorf("%v to %v: moves resulted in %v", test.current, test.desired, order)
+		}
+	}
+}
+
+func TestAccResourceOktaPolicyRuleOrder_crud(t *testing.T) {
+	mgr := newFixtureManager(policyRuleOrder, t.Name())
+	config := mgr.GetFixtures("basic.tf", t)
+	updatedConfig := mgr.GetFixtures("basic_updated.tf", t)
+	resourceName := fmt.Sprintf("%s.test", policyRuleOrder)
+	rule := func(name string) string {
+		return fmt.Sprintf("%s.%s", policyRuleSignOn, name)
+	}
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		CheckDestroy:      checkRuleDestroy(policyRuleSignOn),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "rule_ids.#", "3"),
+					resource.TestCheckResourceAttrPair(resourceName, "rule_ids.0", rule("first"), "id"),
+					resource.TestCheckResourceAttrPair(resourceName, "rule_ids.1", rule("second"), "id"),
+					resource.TestCheckResourceAttrPair(resourceName, "rule_ids.2", rule("third"), "id"),
+				),
+			},
+			{
+				Config: updatedConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "rule_ids.0", rule("third"), "id"),
+					resource.TestCheckResourceAttrPair(resourceName, "rule_ids.1", rule("first"), "id"),
+					resource.TestCheckResourceAttrPair(resourceName, "rule_ids.2", rule("second"), "id"),
+				),
+			},
+			{
+				// the rules don't configure priority so they don't show the
+				// priorities shifted by the order resource as drift
+				Config:   updatedConfig,
+				PlanOnly: true,
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
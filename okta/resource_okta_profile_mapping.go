@@ -2,8 +2,10 @@ package okta
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -16,12 +18,19 @@ const (
 	dontPush = "DONT_PUSH"
 )
 
+// profileMappingExpressionRoots are the objects a profile mapping expression
+// can read attributes from.
+var profileMappingExpressionRoots = []string{"source", "user", "appuser", "idpuser", "app", "org"}
+
 func resourceProfileMapping() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceProfileMappingCreate,
 		ReadContext:   resourceProfileMappingRead,
 		UpdateContext: resourceProfileMappingUpdate,
 		DeleteContext: resourceProfileMappingDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceProfileMappingImport,
+		},
 		Schema: map[string]*schema.Schema{
 			"source_id": {
 				Type:        schema.TypeString,
@@ -77,8 +86,9 @@ var mappingResource = &schema.Resource{
 			Description: "The mapping property key.",
 		},
 		"expression": {
-			Type:     schema.TypeString,
-			Required: true,
+			Type:             schema.TypeString,
+			Required:         true,
+			ValidateDiagFunc: stringIsOktaExpression(profileMappingExpressionRoots...),
 		},
 		"push_status": {
 			Type:     schema.TypeString,
@@ -123,6 +133,7 @@ func resourceProfileMappingRead(ctx context.Context, d *schema.ResourceData, m i
 		d.SetId("")
 		return nil
 	}
+	_ = d.Set("source_id", mapping.Source.Id)
 	_ = d.Set("source_type", mapping.Source.Type)
 	_ = d.Set("source_name", mapping.Source.Name)
 	_ = d.Set("target_type", mapping.Target.Type)
@@ -188,6 +199,40 @@ func resourceProfileMappingDelete(ctx context.Context, d *schema.ResourceData, m
 	return nil
 }
 
+// resourceProfileMappingImport imports a profile mapping by its ID or by
+// <source_id>/<target_id>. All of the mapping's properties are imported into
+// mappings while delete_when_absent and always_apply are left false so that
+// importing doesn't delete or re-apply anything. A configuration declaring
+// only some of the properties plans to drop the others from the state.
+func resourceProfileMappingImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	var (
+		mapping *sdk.ProfileMapping
+		err     error
+	)
+	parts := strings.Split(d.Id(), "/")
+	switch len(parts) {
+	case 1:
+		mapping, _, err = getOktaClientFromMetadata(m).ProfileMapping.GetProfileMapping(ctx, parts[0])
+	case 2:
+		mapping, _, err = getProfileMappingBySourceID(ctx, parts[0], parts[1], m)
+	default:
+		return nil, errors.New("invalid resource import specifier. Use: terraform import <mapping_id> or terraform import <source_id>/<target_id>")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile mapping: %v", err)
+	}
+	if mapping == nil {
+		return nil, fmt.Errorf("no profile mappings found for import specifier '%s'", d.Id())
+	}
+	d.SetId(mapping.Id)
+	_ = d.Set("source_id", mapping.Source.Id)
+	_ = d.Set("target_id", mapping.Target.Id)
+	_ = d.Set("delete_when_absent", false)
+	_ = d.Set("always_apply", false)
+	_ = d.Set("mappings", flattenMappingProperties(mapping.Properties))
+	return []*schema.ResourceData{d}, nil
+}
+
 func getDeleteProperties(d *schema.ResourceData, actual map[string]*sdk.ProfileMappingProperty) map[string]*sdk.ProfileMappingProperty {
 	toDelete := map[string]*sdk.ProfileMappingProperty{}
 	config := buildMappingProperties(d.Get("mappings").(*schema.Set))
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccResourceOktaProfileMapping_crud(t *testing.T) {
@@ -39,6 +40,25 @@ func TestAccResourceOktaProfileMapping_crud(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "delete_when_absent", "true"),
 				),
 			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"delete_when_absent"},
+			},
+			{
+				ResourceName: resourceName,
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources[resourceName]
+					if !ok {
+						return "", fmt.Errorf("failed to find %s", resourceName)
+					}
+					return fmt.Sprintf("%s/%s", rs.Primary.Attributes["source_id"], rs.Primary.Attributes["target_id"]), nil
+				},
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"delete_when_absent"},
+			},
 		},
 	})
 }
@@ -116,46 +116,24 @@ func resourceUserGroupMembershipsUpdate(ctx context.Context, d *schema.ResourceD
 }
 
 func checkIfUserHasGroups(ctx context.Context, client *sdk.Client, userId string, groups []string) (bool, error) {
-	userGroups, resp, err := client.User.ListUserGroups(ctx, userId)
-	if err := suppressErrorOn404(resp, err); err != nil {
-		return false, fmt.Errorf("unable to return groups for user (%s) from API", userId)
-	}
-	var nextUserGroups []*sdk.Group
-
-	for resp.HasNextPage() {
-		resp, err = resp.Next(ctx, &nextUserGroups)
-
-		if err := suppressErrorOn404(resp, err); err != nil {
-			return false, fmt.Errorf("unable to get next page of groups for user (%s) from API", userId)
-		}
-
-		userGroups = append(userGroups, nextUserGroups...)
-	}
-
-	if len(userGroups) == 0 {
-		return false, nil
-	}
-
-	// Create set of groups
-	expectedGroupSet := make(map[string]bool)
-
+	// Create set of groups that haven't been found yet
+	missing := make(map[string]bool)
 	for _, group := range groups {
-		expectedGroupSet[group] = false
+		missing[group] = true
 	}
-
-	// Use groups pulled from user and mark set if found
-	for _, group := range userGroups {
-		if _, ok := expectedGroupSet[group.Id]; ok {
-			expectedGroupSet[group.Id] = true
-		}
-	}
-
-	// Check set for any missing values
-	for _, state := range expectedGroupSet {
-		if !state {
-			return false, nil
-		}
+	found := 0
+
+	err := forEachPage(ctx, func() ([]*sdk.Group, *sdk.Response, error) {
+		userGroups, resp, err := client.User.ListUserGroups(ctx, userId)
+		return userGroups, resp, suppressErrorOn404(resp, err)
+	}, func(group *sdk.Group) bool {
+		found++
+		delete(missing, group.Id)
+		// stop paging once every expected group has been found
+		return len(missing) > 0
+	})
+	if err != nil {
+		return false, fmt.Errorf("unable to return groups for user (%s) from API", userId)
 	}
-
-	return true, nil
+	return found > 0 && len(missing) == 0, nil
 }
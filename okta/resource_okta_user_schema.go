@@ -0,0 +1,464 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+var userSchemaOneOfResource = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"const": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Enum value",
+		},
+		"title": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Enum title",
+		},
+	},
+}
+
+var userSchemaPropertyResource = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"index": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Subschema unique string identifier",
+		},
+		"title": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Subschema title (display name)",
+		},
+		"type": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Subschema type: string, boolean, number, integer, array, or object",
+		},
+		"description": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Custom Subschema description",
+		},
+		"required": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Whether the subschema is required",
+		},
+		"permissions": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "READ_ONLY",
+			Description: "SubSchema permissions: HIDE, READ_ONLY, or READ_WRITE.",
+		},
+		"scope": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  "NONE",
+		},
+		"master": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "PROFILE_MASTER",
+			Description: "SubSchema profile manager: PROFILE_MASTER or OKTA.",
+		},
+		"enum": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "Custom Subschema enumerated value of the property.",
+		},
+		"one_of": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem:        userSchemaOneOfResource,
+			Description: "Custom Subschema json schemas.",
+		},
+		"array_type": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Subschema array type: string, number, integer, reference. Type field must be an array.",
+		},
+		"array_enum": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "Custom Subschema enumerated value of a property of type array.",
+		},
+		"array_one_of": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem:        userSchemaOneOfResource,
+			Description: "array of valid JSON schemas for property type array.",
+		},
+		"unique": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Subschema unique restriction: UNIQUE_VALIDATED or NOT_UNIQUE.",
+		},
+		"pattern": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The validation pattern of the subschema of type string.",
+		},
+		"min_length": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Description: "Subschema of type string minimum length",
+		},
+		"max_length": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Description: "Subschema of type string maximum length",
+		},
+		"external_name": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Subschema external name",
+		},
+		"external_namespace": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Subschema external namespace",
+		},
+	},
+}
+
+func resourceUserSchema() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceUserSchemaPropertiesCreate,
+		ReadContext:   resourceUserSchemaPropertiesRead,
+		UpdateContext: resourceUserSchemaPropertiesUpdate,
+		DeleteContext: resourceUserSchemaPropertiesDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceUserSchemaPropertiesImport,
+		},
+		CustomizeDiff: validateUserSchemaProperties,
+		Schema: map[string]*schema.Schema{
+			"user_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "default",
+				ForceNew:    true,
+				Description: "User type ID of the schema",
+			},
+			"property": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        userSchemaPropertyResource,
+				Description: "Custom properties of the schema",
+			},
+			"detect_unmanaged_properties": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Read the custom properties of the schema that aren't declared as drift, they are removed on apply",
+			},
+		},
+	}
+}
+
+func resourceUserSchemaPropertiesCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := updateUserSchemaProperties(ctx, d, m); err != nil {
+		return diag.Errorf("failed to update user schema properties: %v", err)
+	}
+	d.SetId(d.Get("user_type").(string))
+	return resourceUserSchemaPropertiesRead(ctx, d, m)
+}
+
+func resourceUserSchemaPropertiesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	s, err := getUserTypeSchema(ctx, m, d.Id())
+	if err != nil {
+		return diag.Errorf("failed to get user schema: %v", err)
+	}
+	managed := map[string]bool{}
+	for _, p := range d.Get("property").(*schema.Set).List() {
+		managed[p.(map[string]interface{})["index"].(string)] = true
+	}
+	detectUnmanaged := d.Get("detect_unmanaged_properties").(bool)
+	var properties []interface{}
+	for _, index := range userSchemaCustomIndexes(s) {
+		if managed[index] || detectUnmanaged {
+			properties = append(properties, flattenUserSchemaProperty(index, userSchemaCustomAttribute(s, index)))
+		}
+	}
+	_ = d.Set("user_type", d.Id())
+	err = setNonPrimitives(d, map[string]interface{}{
+		"property": schema.NewSet(schema.HashResource(userSchemaPropertyResource), properties),
+	})
+	if err != nil {
+		return diag.Errorf("failed to set user schema properties: %v", err)
+	}
+	return nil
+}
+
+func resourceUserSchemaPropertiesUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := updateUserSchemaProperties(ctx, d, m); err != nil {
+		return diag.Errorf("failed to update user schema properties: %v", err)
+	}
+	return resourceUserSchemaPropertiesRead(ctx, d, m)
+}
+
+// resourceUserSchemaPropertiesDelete removes the custom properties of the
+// schema in the state in a single update.
+func resourceUserSchemaPropertiesDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	typeSchemaID, err := getUserTypeSchemaID(ctx, getOktaClientFromMetadata(m), d.Id())
+	if err != nil {
+		return diag.Errorf("failed to get user schema: %v", err)
+	}
+	properties := map[string]*sdk.UserSchemaAttribute{}
+	for _, p := range d.Get("property").(*schema.Set).List() {
+		properties[p.(map[string]interface{})["index"].(string)] = nil
+	}
+	if len(properties) == 0 {
+		return nil
+	}
+	if err := alterUserSchemaProperties(ctx, m, typeSchemaID, properties); err != nil {
+		return diag.Errorf("failed to remove user schema properties: %v", err)
+	}
+	return nil
+}
+
+// resourceUserSchemaPropertiesImport imports the schema of a user type with
+// all its custom properties.
+func resourceUserSchemaPropertiesImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	s, err := getUserTypeSchema(ctx, m, d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user schema: %v", err)
+	}
+	var properties []interface{}
+	for _, index := range userSchemaCustomIndexes(s) {
+		properties = append(properties, flattenUserSchemaProperty(index, userSchemaCustomAttribute(s, index)))
+	}
+	_ = d.Set("user_type", d.Id())
+	_ = d.Set("detect_unmanaged_properties", false)
+	if err := d.Set("property", schema.NewSet(schema.HashResource(userSchemaPropertyResource), properties)); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// validateUserSchemaProperties checks that every property is declared once
+// and that the enums of array properties are declared as array_enum or
+// array_one_of.
+func validateUserSchemaProperties(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	seen := map[string]bool{}
+	for _, p := range d.Get("property").(*schema.Set).List() {
+		property := p.(map[string]interface{})
+		index := property["index"].(string)
+		if index == "" {
+			continue
+		}
+		if seen[index] {
+			return fmt.Errorf("property '%s' is declared more than once", index)
+		}
+		seen[index] = true
+		if property["array_type"].(string) != "" && (len(property["enum"].([]interface{})) > 0 || len(property["one_of"].([]interface{})) > 0) {
+			return fmt.Errorf("property '%s' is an array, its enum values should be declared as array_enum or array_one_of", index)
+		}
+	}
+	return nil
+}
+
+// updateUserSchemaProperties diffs the declared properties against the live
+// schema and sends the changed properties, and the ones no longer declared as
+// null to remove them, in a single update.
+func updateUserSchemaProperties(ctx context.Context, d *schema.ResourceData, m interface{}) error {
+	typeSchemaID, err := getUserTypeSchemaID(ctx, getOktaClientFromMetadata(m), d.Get("user_type").(string))
+	if err != nil {
+		return err
+	}
+	live, _, err := getOktaClientFromMetadata(m).UserSchema.GetUserSchema(ctx, typeSchemaID)
+	if err != nil {
+		return fmt.Errorf("failed to get user schema: %v", err)
+	}
+	stringifyUserSchemaPropertyEnums(live)
+	hash := schema.HashResource(userSchemaPropertyResource)
+	properties := map[string]*sdk.UserSchemaAttribute{}
+	declared := map[string]bool{}
+	for _, p := range d.Get("property").(*schema.Set).List() {
+		property := p.(map[string]interface{})
+		index := property["index"].(string)
+		declared[index] = true
+		if attribute := userSchemaCustomAttribute(live, index); attribute != nil && hash(flattenUserSchemaProperty(index, attribute)) == hash(property) {
+			continue
+		}
+		properties[index] = buildUserSchemaProperty(property)
+	}
+	old, _ := d.GetChange("property")
+	for _, p := range old.(*schema.Set).List() {
+		index := p.(map[string]interface{})["index"].(string)
+		if !declared[index] && userSchemaCustomAttribute(live, index) != nil {
+			properties[index] = nil
+		}
+	}
+	if len(properties) == 0 {
+		return nil
+	}
+	logger(m).Info("updating user schema properties", "user_type", d.Get("user_type").(string), "count", len(properties))
+	return alterUserSchemaProperties(ctx, m, typeSchemaID, properties)
+}
+
+// alterUserSchemaProperties updates the custom properties of a schema, a nil
+// property is removed. Okta responds with 500 or asks to wait for the clean up
+// of removed properties at times, those updates are retried.
+func alterUserSchemaProperties(ctx context.Context, m interface{}, typeSchemaID string, properties map[string]*sdk.UserSchemaAttribute) error {
+	us := &sdk.UserSchema{
+		Definitions: &sdk.UserSchemaDefinitions{
+			Custom: &sdk.UserSchemaPublic{
+				Id:         "#custom",
+				Properties: properties,
+				Type:       "object",
+			},
+		},
+	}
+	retypeUserSchemaPropertyEnums(us)
+	boc := newExponentialBackOffWithContext(ctx, 120*time.Second)
+	return backoff.Retry(func() error {
+		_, resp, err := getOktaClientFromMetadata(m).UserSchema.UpdateUserProfile(ctx, typeSchemaID, *us)
+		if doNotRetry(m, err) {
+			return backoff.Permanent(err)
+		}
+		if err != nil {
+			if resp != nil && resp.StatusCode == 500 {
+				return fmt.Errorf("updating user schema properties caused 500 error: %w", err)
+			}
+			if strings.Contains(err.Error(), "Wait until the data clean up process finishes and then try again") {
+				return err
+			}
+			return backoff.Permanent(err)
+		}
+		return nil
+	}, boc)
+}
+
+func getUserTypeSchema(ctx context.Context, m interface{}, userType string) (*sdk.UserSchema, error) {
+	typeSchemaID, err := getUserTypeSchemaID(ctx, getOktaClientFromMetadata(m), userType)
+	if err != nil {
+		return nil, err
+	}
+	s, _, err := getOktaClientFromMetadata(m).UserSchema.GetUserSchema(ctx, typeSchemaID)
+	if err != nil {
+		return nil, err
+	}
+	stringifyUserSchemaPropertyEnums(s)
+	return s, nil
+}
+
+func userSchemaCustomIndexes(s *sdk.UserSchema) []string {
+	if s == nil || s.Definitions == nil || s.Definitions.Custom == nil {
+		return nil
+	}
+	indexes := make([]string, 0, len(s.Definitions.Custom.Properties))
+	for index, attribute := range s.Definitions.Custom.Properties {
+		if attribute != nil {
+			indexes = append(indexes, index)
+		}
+	}
+	sort.Strings(indexes)
+	return indexes
+}
+
+func buildUserSchemaProperty(property map[string]interface{}) *sdk.UserSchemaAttribute {
+	attribute := &sdk.UserSchemaAttribute{
+		Title:       property["title"].(string),
+		Type:        property["type"].(string),
+		Description: property["description"].(string),
+		Required:    boolPtr(property["required"].(bool)),
+		Permissions: []*sdk.UserSchemaAttributePermission{
+			{
+				Action:    property["permissions"].(string),
+				Principal: "SELF",
+			},
+		},
+		Scope:             property["scope"].(string),
+		Unique:            property["unique"].(string),
+		ExternalName:      property["external_name"].(string),
+		ExternalNamespace: property["external_namespace"].(string),
+	}
+	if master := property["master"].(string); master != "" {
+		attribute.Master = &sdk.UserSchemaAttributeMaster{Type: master}
+	}
+	if enum := property["enum"].([]interface{}); len(enum) > 0 {
+		attribute.Enum = enum
+	}
+	if oneOf := property["one_of"].([]interface{}); len(oneOf) > 0 {
+		attribute.OneOf, _ = buildOneOf(oneOf, attribute.Type)
+	}
+	if arrayType := property["array_type"].(string); arrayType != "" {
+		attribute.Items = &sdk.UserSchemaAttributeItems{Type: arrayType}
+		if enum := property["array_enum"].([]interface{}); len(enum) > 0 {
+			attribute.Items.Enum = enum
+		}
+		if oneOf := property["array_one_of"].([]interface{}); len(oneOf) > 0 {
+			attribute.Items.OneOf, _ = buildOneOf(oneOf, arrayType)
+		}
+	}
+	if pattern := property["pattern"].(string); pattern != "" {
+		attribute.Pattern = stringPtr(pattern)
+	}
+	if min := property["min_length"].(int); min > 0 {
+		attribute.MinLengthPtr = int64Ptr(min)
+	}
+	if max := property["max_length"].(int); max > 0 {
+		attribute.MaxLengthPtr = int64Ptr(max)
+	}
+	return attribute
+}
+
+// flattenUserSchemaProperty returns the property of a schema with string enums
+// as a property block.
+func flattenUserSchemaProperty(index string, attribute *sdk.UserSchemaAttribute) map[string]interface{} {
+	property := map[string]interface{}{
+		"index":              index,
+		"title":              attribute.Title,
+		"type":               attribute.Type,
+		"description":        attribute.Description,
+		"required":           attribute.Required != nil && *attribute.Required,
+		"permissions":        "",
+		"scope":              attribute.Scope,
+		"master":             "",
+		"enum":               append([]interface{}{}, attribute.Enum...),
+		"one_of":             flattenOneOf(attribute.OneOf),
+		"array_type":         "",
+		"array_enum":         []interface{}{},
+		"array_one_of":       []interface{}{},
+		"unique":             attribute.Unique,
+		"pattern":            "",
+		"min_length":         0,
+		"max_length":         0,
+		"external_name":      attribute.ExternalName,
+		"external_namespace": attribute.ExternalNamespace,
+	}
+	if len(attribute.Permissions) > 0 {
+		property["permissions"] = attribute.Permissions[0].Action
+	}
+	if attribute.Master != nil {
+		property["master"] = attribute.Master.Type
+	}
+	if attribute.Items != nil {
+		property["array_type"] = attribute.Items.Type
+		property["array_enum"] = append([]interface{}{}, attribute.Items.Enum...)
+		property["array_one_of"] = flattenOneOf(attribute.Items.OneOf)
+	}
+	if attribute.Pattern != nil {
+		property["pattern"] = *attribute.Pattern
+	}
+	if attribute.MinLengthPtr != nil {
+		property["min_length"] = int(*attribute.MinLengthPtr)
+	}
+	if attribute.MaxLengthPtr != nil {
+		property["max_length"] = int(*attribute.MaxLengthPtr)
+	}
+	return property
+}
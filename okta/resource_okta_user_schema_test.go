@@ -0,0 +1,199 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/okta/terraform-provider-okta/okta/internal/mockokta"
+)
+
+func TestAccResourceOktaUserSchemaProperties_crud(t *testing.T) {
+	mgr := newFixtureManager(userSchema, t.Name())
+	config := mgr.GetFixtures("basic.tf", t)
+	updated := mgr.GetFixtures("basic_updated.tf", t)
+	resourceName := buildResourceFQN(userSchema, mgr.Seed)
+	size := fmt.Sprintf("testAcc_size_%d", mgr.Seed)
+	employeeID := fmt.Sprintf("testAcc_employee_id_%d", mgr.Seed)
+	teams := fmt.Sprintf("testAcc_teams_%d", mgr.Seed)
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		CheckDestroy:      checkUserSchemaPropertiesDestroy(size, employeeID, teams),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "id", "default"),
+					resource.TestCheckResourceAttr(resourceName, "property.#", "3"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "property.*", map[string]string{
+						"index":       size,
+						"title":       "terraform acceptance test",
+						"max_length":  "50",
+						"permissions": "READ_ONLY",
+						"master":      "PROFILE_MASTER",
+						"enum.#":      "3",
+						"one_of.#":    "3",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "property.*", map[string]string{
+						"index":       employeeID,
+						"permissions": "READ_WRITE",
+						"unique":      "UNIQUE_VALIDATED",
+						"pattern":     "[0-9]+",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "property.*", map[string]string{
+						"index":        teams,
+						"type":         "array",
+						"array_type":   "string",
+						"array_enum.#": "2",
+					}),
+				),
+			},
+			{
+				Config: updated,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "property.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "property.*", map[string]string{
+						"index":      size,
+						"title":      "terraform acceptance test updated",
+						"max_length": "70",
+						"master":     "OKTA",
+						"enum.#":     "4",
+						"one_of.#":   "4",
+					}),
+					checkUserSchemaPropertiesDestroy(teams),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateCheck: func(s []*terraform.InstanceState) error {
+					if len(s) != 1 || s[0].ID != "default" {
+						return fmt.Errorf("expected the default user schema to be imported, got %v", s)
+					}
+					return nil
+				},
+				// the import holds all the custom properties of the schema
+				// and not only the ones of the config
+				ImportStateVerifyIgnore: []string{"property"},
+			},
+		},
+	})
+}
+
+// checkUserSchemaPropertiesDestroy checks that the custom properties are no
+// longer in the default user schema.
+func checkUserSchemaPropertiesDestroy(indexes ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		us, _, err := sdkV2ClientForTest().UserSchema.GetUserSchema(context.Background(), "default")
+		if err != nil {
+			return fmt.Errorf("failed to get user schema: %v", err)
+		}
+		for _, index := range indexes {
+			if userSchemaCustomAttribute(us, index) != nil {
+				return fmt.Errorf("user schema property '%s' still exists", index)
+			}
+		}
+		return nil
+	}
+}
+
+func TestResourceUserSchemaProperties(t *testing.T) {
+	server := mockokta.NewServer()
+	t.Cleanup(server.Close)
+	var updates int32
+	m := newTestConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/api/v1/meta/schemas/user/") {
+			atomic.AddInt32(&updates, 1)
+		}
+		server.ServeHTTP(w, r)
+	}))
+	ctx := context.Background()
+	r := resourceUserSchema()
+	property := func(index, title string) map[string]interface{} {
+		return map[string]interface{}{"index": index, "title": title, "type": "string"}
+	}
+	// updateData returns the data of an update to the properties
+	updateData := func(d *schema.ResourceData, detectUnmanaged bool, properties ...interface{}) *schema.ResourceData {
+		return updateResourceData(t, r, d, map[string]interface{}{"property": properties, "detect_unmanaged_properties": detectUnmanaged}, m)
+	}
+
+	properties := make([]interface{}, 120)
+	for i := range properties {
+		properties[i] = property(fmt.Sprintf("attr%d", i), "attribute")
+	}
+	d := r.Data(nil)
+	_ = d.Set("user_type", "default")
+	_ = d.Set("property", schema.NewSet(schema.HashResource(userSchemaPropertyResource), properties))
+	if diags := r.CreateContext(ctx, d, m); diags.HasError() {
+		t.Fatalf("failed to create: %v", diags)
+	}
+	if atomic.LoadInt32(&updates) != 1 || d.Get("property").(*schema.Set).Len() != 120 {
+		t.Fatalf("expected the 120 properties to be created in a single update, got %d updates", atomic.LoadInt32(&updates))
+	}
+
+	// only the changed and removed properties are sent
+	properties[0] = property("attr0", "attribute renamed")
+	d = updateData(d, false, properties[:119]...)
+	if diags := r.UpdateContext(ctx, d, m); diags.HasError() {
+		t.Fatalf("failed to update: %v", diags)
+	}
+	us, _, _ := getOktaClientFromMetadata(m).UserSchema.GetUserSchema(ctx, "default")
+	if atomic.LoadInt32(&updates) != 2 || userSchemaCustomAttribute(us, "attr0").Title != "attribute renamed" || userSchemaCustomAttribute(us, "attr119") != nil {
+		t.Errorf("expected attr0 to be renamed and attr119 to be removed in a single update, got %d updates", atomic.LoadInt32(&updates))
+	}
+	d = updateData(d, false, properties[:119]...)
+	if diags := r.UpdateContext(ctx, d, m); diags.HasError() {
+		t.Fatalf("failed to update: %v", diags)
+	}
+	if atomic.LoadInt32(&updates) != 2 {
+		t.Errorf("expected no update without changes, got %d updates", atomic.LoadInt32(&updates))
+	}
+
+	// unmanaged properties are only read when they are detected as drift
+	other := resourceUserSchema().Data(nil)
+	_ = other.Set("user_type", "default")
+	_ = other.Set("property", schema.NewSet(schema.HashResource(userSchemaPropertyResource), []interface{}{property("unmanaged", "unmanaged")}))
+	if diags := r.CreateContext(ctx, other, m); diags.HasError() {
+		t.Fatalf("failed to create: %v", diags)
+	}
+	if diags := r.ReadContext(ctx, d, m); diags.HasError() || d.Get("property").(*schema.Set).Len() != 119 {
+		t.Fatalf("expected the unmanaged property not to be read: %v", diags)
+	}
+	d = updateData(d, true, properties[:119]...)
+	if diags := r.ReadContext(ctx, d, m); diags.HasError() || d.Get("property").(*schema.Set).Len() != 120 {
+		t.Fatalf("expected the unmanaged property to be read as drift: %v", diags)
+	}
+	d = updateData(d, true, properties[:119]...)
+	if diags := r.UpdateContext(ctx, d, m); diags.HasError() {
+		t.Fatalf("failed to update: %v", diags)
+	}
+	us, _, _ = getOktaClientFromMetadata(m).UserSchema.GetUserSchema(ctx, "default")
+	if userSchemaCustomAttribute(us, "unmanaged") != nil {
+		t.Errorf("expected the unmanaged property to be removed")
+	}
+
+	if diags := r.DeleteContext(ctx, d, m); diags.HasError() {
+		t.Fatalf("failed to delete: %v", diags)
+	}
+	us, _, _ = getOktaClientFromMetadata(m).UserSchema.GetUserSchema(ctx, "default")
+	if len(userSchemaCustomIndexes(us)) != 0 {
+		t.Errorf("expected the properties to be removed, got %v", userSchemaCustomIndexes(us))
+	}
+
+	_, err := r.SimpleDiff(ctx, &terraform.InstanceState{}, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"property": []interface{}{property("attr0", "a"), property("attr0", "b")},
+	}), m)
+	if err == nil || !strings.Contains(err.Error(), "declared more than once") {
+		t.Errorf("expected the duplicate property to be rejected, got %v", err)
+	}
+}
@@ -1,6 +1,10 @@
 package okta
 
 import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
 	"strings"
 
 	"github.com/crewjam/saml"
@@ -48,3 +52,89 @@ func syncSamlCertificates(d *schema.ResourceData, descriptors []saml.KeyDescript
 		}
 	}
 }
+
+// samlCertificateFingerprints returns the SHA-1 and SHA-256 fingerprints, as
+// colon separated upper case hex, of a base64 encoded certificate from SAML
+// metadata.
+func samlCertificateFingerprints(data string) (string, string, error) {
+	der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(data), ""))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to decode certificate: %v", err)
+	}
+	sha1Sum := sha1.Sum(der)
+	sha256Sum := sha256.Sum256(der)
+	return fingerprint(sha1Sum[:]), fingerprint(sha256Sum[:]), nil
+}
+
+func fingerprint(sum []byte) string {
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+// samlSigningCertificates returns the certificates of the key descriptors used
+// for signing, a key descriptor without a use is used for both signing and
+// encryption.
+func samlSigningCertificates(descriptors []saml.KeyDescriptor) []string {
+	var certs []string
+	for _, desc := range descriptors {
+		if desc.Use != "" && desc.Use != "signing" {
+			continue
+		}
+		for _, cert := range desc.KeyInfo.X509Data.X509Certificates {
+			certs = append(certs, strings.Join(strings.Fields(cert.Data), ""))
+		}
+	}
+	return certs
+}
+
+// samlShibbolethConfig renders the elements of the ApplicationDefaults'
+// Sessions element of a Shibboleth SP shibboleth2.xml that use the IdP.
+func samlShibbolethConfig(entityID, metadataURL string) string {
+	return fmt.Sprintf(`<SSO entityID="%s">SAML2</SSO>
+<MetadataProvider type="XML" validate="true" url="%s" backingFilePath="okta-idp-metadata.xml" maxRefreshDelay="7200"/>
+`, entityID, metadataURL)
+}
+
+// samlADFSConfig renders the PowerShell command adding the IdP as an AD FS
+// claims provider trust.
+func samlADFSConfig(entityID, metadataURL string) string {
+	return fmt.Sprintf(`Add-AdfsClaimsProviderTrust -Name "Okta %s" -MetadataUrl "%s" -MonitoringEnabled $true -AutoUpdateEnabled $true
+`, entityID, metadataURL)
+}
+
+// samlSimpleSAMLphpConfig renders the entry of the IdP for a simpleSAMLphp
+// metadata/saml20-idp-remote.php file.
+func samlSimpleSAMLphpConfig(entityID string, sso, slo []saml.Endpoint, certs []string, nameIDFormats []saml.NameIDFormat) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "$metadata['%s'] = [\n", phpString(entityID))
+	writeEndpoints := func(name string, endpoints []saml.Endpoint) {
+		if len(endpoints) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "    '%s' => [\n", name)
+		for _, endpoint := range endpoints {
+			fmt.Fprintf(&b, "        ['Binding' => '%s', 'Location' => '%s'],\n", phpString(endpoint.Binding), phpString(endpoint.Location))
+		}
+		b.WriteString("    ],\n")
+	}
+	writeEndpoints("SingleSignOnService", sso)
+	writeEndpoints("SingleLogoutService", slo)
+	if len(nameIDFormats) > 0 {
+		fmt.Fprintf(&b, "    'NameIDFormat' => '%s',\n", phpString(string(nameIDFormats[0])))
+	}
+	b.WriteString("    'keys' => [\n")
+	for _, cert := range certs {
+		fmt.Fprintf(&b, "        ['type' => 'X509Certificate', 'signing' => true, 'encryption' => false, 'X509Certificate' => '%s'],\n", phpString(cert))
+	}
+	b.WriteString("    ],\n];\n")
+	return b.String()
+}
+
+// phpString escapes the backslashes and single quotes of a value of a single
+// quoted PHP string.
+func phpString(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s)
+}
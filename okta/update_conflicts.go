@@ -0,0 +1,205 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/internal/transport"
+)
+
+const updateConflictsDetail = "Review a new plan and apply again to overwrite the changes made outside of Terraform, " +
+	"or set the provider's detect_update_conflicts to false."
+
+// withUpdateConflictDetection refuses the update of a resource, when the
+// provider is configured with detect_update_conflicts, if reading the object
+// again shows its configurable attributes differ from the state, and checks
+// the objects read are not updated by someone else before being overwritten,
+// see transport.NewConflictTransport. The resource's read function is
+// captured as is, call it before the read function is wrapped.
+func withUpdateConflictDetection(name string, r *schema.Resource) schema.UpdateContextFunc {
+	update, read := r.UpdateContext, r.ReadContext
+	if update == nil || read == nil {
+		return update
+	}
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		if config, ok := m.(*Config); !ok || !config.detectUpdateConflicts {
+			return update(ctx, d, m)
+		}
+		resource := fmt.Sprintf("%s (%s)", name, d.Id())
+		ctx, conflicts := transport.WithUpdateConflicts(ctx)
+		current := r.Data(nil)
+		current.SetId(d.Id())
+		for k := range r.Schema {
+			old, _ := d.GetChange(k)
+			_ = current.Set(k, old)
+		}
+		if diags := read(ctx, current, m); diags.HasError() {
+			return diags
+		}
+		if current.Id() == "" {
+			return diag.Errorf("Refused to update %s, it was deleted outside of Terraform", resource)
+		}
+		if changed := changedAttributes(r, d, current); len(changed) > 0 {
+			return diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Refused to update %s, it was changed outside of Terraform", resource),
+				Detail: fmt.Sprintf("The provider is configured with detect_update_conflicts = true and these attributes differ from the state:\n\n  %s\n\n%s",
+					strings.Join(changed, "\n  "), updateConflictsDetail),
+			}}
+		}
+		diags := update(ctx, d, m)
+		return append(diags, updateConflictsDiagnostics(resource, conflicts)...)
+	}
+}
+
+// changedAttributes returns the configurable attributes whose value in the
+// state differs from the one just read.
+func changedAttributes(r *schema.Resource, d, current *schema.ResourceData) []string {
+	var changed []string
+	for k, s := range r.Schema {
+		if !s.Optional && !s.Required {
+			continue
+		}
+		old, _ := d.GetChange(k)
+		if !reflect.DeepEqual(comparableAttribute(old), comparableAttribute(current.Get(k))) {
+			changed = append(changed, k)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// comparableAttribute replaces the sets of an attribute value with their
+// elements, sets can't be compared with reflect.DeepEqual.
+func comparableAttribute(v interface{}) interface{} {
+	switch v := v.(type) {
+	case *schema.Set:
+		return comparableAttribute(v.List())
+	case []interface{}:
+		arr := make([]interface{}, len(v))
+		for i := range v {
+			arr[i] = comparableAttribute(v[i])
+		}
+		return arr
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k := range v {
+			m[k] = comparableAttribute(v[k])
+		}
+		return m
+	}
+	return v
+}
+
+// updateConflictsDiagnostics returns an error naming the PUT requests refused
+// by the http transport, as the object changed after being read.
+func updateConflictsDiagnostics(resource string, conflicts *transport.UpdateConflicts) diag.Diagnostics {
+	refused := conflicts.Conflicts()
+	if len(refused) == 0 {
+		return nil
+	}
+	detail := "The provider is configured with detect_update_conflicts = true, the refused requests were:\n"
+	for _, err := range refused {
+		detail += fmt.Sprintf("\n  %s", err)
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf("Refused to update %s, it was changed outside of Terraform during the update", resource),
+		Detail:   detail + "\n\n" + updateConflictsDetail,
+	}}
+}
+
+// conflictDetectingResource is a framework resource whose update is refused,
+// when the provider is configured with detect_update_conflicts, if reading
+// the object again shows it differs from the state, e.g. its last_update
+// moved.
+type conflictDetectingResource struct {
+	resource.Resource
+	config *Config
+}
+
+func withFrameworkUpdateConflictDetection(newResource func() resource.Resource) func() resource.Resource {
+	return func() resource.Resource {
+		return &conflictDetectingResource{Resource: newResource()}
+	}
+}
+
+func (r *conflictDetectingResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if config, ok := req.ProviderData.(*Config); ok {
+		r.config = config
+	}
+	if configurable, ok := r.Resource.(resource.ResourceWithConfigure); ok {
+		configurable.Configure(ctx, req, resp)
+	}
+}
+
+func (r *conflictDetectingResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importable, ok := r.Resource.(resource.ResourceWithImportState)
+	if !ok {
+		resp.Diagnostics.AddError("Resource Import Not Implemented", "This resource does not support import.")
+		return
+	}
+	importable.ImportState(ctx, req, resp)
+}
+
+func (r *conflictDetectingResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if r.config == nil || !r.config.detectUpdateConflicts {
+		r.Resource.Update(ctx, req, resp)
+		return
+	}
+	var metadata resource.MetadataResponse
+	r.Resource.Metadata(ctx, resource.MetadataRequest{ProviderTypeName: "okta"}, &metadata)
+
+	ctx, conflicts := transport.WithUpdateConflicts(ctx)
+	current := &resource.ReadResponse{
+		State:   tfsdk.State{Schema: req.State.Schema, Raw: req.State.Raw.Copy()},
+		Private: resp.Private,
+	}
+	r.Resource.Read(ctx, resource.ReadRequest{State: req.State, Private: req.Private, ProviderMeta: req.ProviderMeta}, current)
+	if current.Diagnostics.HasError() {
+		resp.Diagnostics.Append(current.Diagnostics...)
+		return
+	}
+	if current.State.Raw.IsNull() {
+		resp.Diagnostics.AddError(fmt.Sprintf("Refused to update %s, it was deleted outside of Terraform", metadata.TypeName), updateConflictsDetail)
+		return
+	}
+	diffs, err := req.State.Raw.Diff(current.State.Raw)
+	if err != nil {
+		resp.Diagnostics.AddError("failed to compare the state with the object read", err.Error())
+		return
+	}
+	changed := map[string]bool{}
+	for _, diff := range diffs {
+		if steps := diff.Path.Steps(); len(steps) > 0 {
+			if name, ok := steps[0].(tftypes.AttributeName); ok {
+				changed[string(name)] = true
+			}
+		}
+	}
+	if len(changed) > 0 {
+		var names []string
+		for name := range changed {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Refused to update %s, it was changed outside of Terraform", metadata.TypeName),
+			fmt.Sprintf("The provider is configured with detect_update_conflicts = true and these attributes differ from the state:\n\n  %s\n\n%s",
+				strings.Join(names, "\n  "), updateConflictsDetail),
+		)
+		return
+	}
+	r.Resource.Update(ctx, req, resp)
+	for _, d := range updateConflictsDiagnostics(metadata.TypeName, conflicts) {
+		resp.Diagnostics.AddError(d.Summary, d.Detail)
+	}
+}
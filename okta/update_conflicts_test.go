@@ -0,0 +1,109 @@
+package okta
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestWithUpdateConflictDetection(t *testing.T) {
+	var (
+		remote  map[string]interface{}
+		updated bool
+	)
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Required: true},
+			"members": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"password":      {Type: schema.TypeString, Optional: true, Sensitive: true},
+			"last_modified": {Type: schema.TypeString, Computed: true},
+		},
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			if remote == nil {
+				d.SetId("")
+				return nil
+			}
+			// the password is write only, the read keeps the one in state
+			_ = d.Set("name", remote["name"])
+			_ = d.Set("members", convertStringSliceToSet(remote["members"].([]string)))
+			_ = d.Set("last_modified", remote["last_modified"])
+			return nil
+		},
+		UpdateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			updated = true
+			return nil
+		},
+	}
+	update := withUpdateConflictDetection("okta_test", r)
+
+	tests := []struct {
+		name    string
+		remote  map[string]interface{}
+		detect  bool
+		changed string
+	}{
+		{
+			name:   "unchanged",
+			remote: map[string]interface{}{"name": "a", "members": []string{"u2", "u1"}, "last_modified": "t1"},
+			detect: true,
+		},
+		{
+			name:   "computed attribute changed",
+			remote: map[string]interface{}{"name": "a", "members": []string{"u1", "u2"}, "last_modified": "t2"},
+			detect: true,
+		},
+		{
+			name:    "configurable attributes changed",
+			remote:  map[string]interface{}{"name": "b", "members": []string{"u1"}, "last_modified": "t2"},
+			detect:  true,
+			changed: "these attributes differ from the state:\n\n  members\n  name\n\n",
+		},
+		{
+			name:    "deleted",
+			detect:  true,
+			changed: "deleted outside of Terraform",
+		},
+		{
+			name:   "detection disabled",
+			remote: map[string]interface{}{"name": "b", "members": []string{"u1"}, "last_modified": "t2"},
+		},
+	}
+	for _, test := range tests {
+		remote, updated = test.remote, false
+		d := r.Data(&terraform.InstanceState{
+			ID: "id1",
+			Attributes: map[string]string{
+				"id":            "id1",
+				"name":          "a",
+				"members.#":     "2",
+				"members.0":     "u1",
+				"members.1":     "u2",
+				"password":      "secret",
+				"last_modified": "t1",
+			},
+		})
+		m := &Config{logger: hclog.NewNullLogger(), detectUpdateConflicts: test.detect}
+		diags := update(context.Background(), d, m)
+		if test.changed == "" {
+			if diags.HasError() || !updated {
+				t.Errorf("%s: expected the update to be made, got %v", test.name, diags)
+			}
+			continue
+		}
+		if updated {
+			t.Errorf("%s: expected the update to be refused", test.name)
+		}
+		if !diags.HasError() || !strings.Contains(diags[0].Summary+diags[0].Detail, test.changed) {
+			t.Errorf("%s: expected an error containing %q, got %v", test.name, test.changed, diags)
+		}
+	}
+}
@@ -332,29 +332,16 @@ func setAdminRoles(ctx context.Context, d *schema.ResourceData, m interface{}) e
 }
 
 func getGroupsForUser(ctx context.Context, id string, c *sdk.Client) ([]string, error) {
-	groups, response, err := c.User.ListUserGroups(ctx, id)
+	groupIDs := make([]string, 0)
+	err := forEachPage(ctx, func() ([]*sdk.Group, *sdk.Response, error) {
+		return c.User.ListUserGroups(ctx, id)
+	}, func(group *sdk.Group) bool {
+		groupIDs = append(groupIDs, group.Id)
+		return true
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list user groups: %v", err)
 	}
-
-	groupIDs := make([]string, 0)
-
-	for {
-		for _, group := range groups {
-			groupIDs = append(groupIDs, group.Id)
-		}
-
-		if !response.HasNextPage() {
-			break
-		}
-
-		response, err = response.Next(ctx, &groups)
-
-		if err != nil {
-			return nil, fmt.Errorf("failed to list user groups: %v", err)
-		}
-	}
-
 	return groupIDs, nil
 }
 
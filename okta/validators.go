@@ -1,12 +1,14 @@
 package okta
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
+	"github.com/okta/terraform-provider-okta/okta/internal/expression"
 )
 
 func intBetween(min, max int) schema.SchemaValidateDiagFunc {
@@ -65,3 +67,24 @@ func stringIsJSON(i interface{}, k cty.Path) diag.Diagnostics {
 	}
 	return nil
 }
+
+// stringIsOktaExpression checks the syntax of an Okta Expression Language
+// expression, that it only calls known functions and that attribute paths
+// start from one of roots, e.g. "user".
+func stringIsOktaExpression(roots ...string) schema.SchemaValidateDiagFunc {
+	return func(i interface{}, k cty.Path) diag.Diagnostics {
+		v, ok := i.(string)
+		if !ok {
+			return diag.Errorf("expected type of %s to be string", k)
+		}
+		if _, err := expression.Validate(v, roots...); err != nil {
+			return diag.Diagnostics{{
+				Severity:      diag.Error,
+				Summary:       "Invalid Okta expression",
+				Detail:        fmt.Sprintf("%q is not a valid Okta expression: %v", v, err),
+				AttributePath: k,
+			}}
+		}
+		return nil
+	}
+}
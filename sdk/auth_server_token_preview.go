@@ -0,0 +1,43 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// TokenPreviewRequest is the user, grant type and scopes the tokens of a
+// client are previewed for.
+type TokenPreviewRequest struct {
+	UserID    string   `json:"userId"`
+	GrantType string   `json:"grantType"`
+	Scopes    []string `json:"scopes"`
+}
+
+// TokenPreview holds the claims of the ID and access tokens an authorization
+// server would mint for a client, evaluated the way the token preview of the
+// admin console evaluates them, without minting any token.
+type TokenPreview struct {
+	IDToken     map[string]interface{} `json:"idToken"`
+	AccessToken map[string]interface{} `json:"accessToken"`
+}
+
+// PreviewAuthorizationServerTokens evaluates the claims of the ID and access
+// tokens of a client of an authorization server for a user and scopes. The
+// endpoint is the one of the Token Preview tab of the admin console, it isn't
+// part of the documented management API and may change without notice. The
+// request and response shapes are unverified against a live org.
+func (m *APISupplement) PreviewAuthorizationServerTokens(ctx context.Context, authServerID, clientID string, body TokenPreviewRequest) (*TokenPreview, *Response, error) {
+	url := fmt.Sprintf("/api/v1/authorizationServers/%s/clients/%s/tokens/preview", authServerID, clientID)
+	re := m.cloneRequestExecutor()
+	req, err := re.NewRequest(http.MethodPost, url, body)
+	if err != nil {
+		return nil, nil, err
+	}
+	var preview *TokenPreview
+	resp, err := re.Do(ctx, req, &preview)
+	if err != nil {
+		return nil, resp, err
+	}
+	return preview, resp, nil
+}
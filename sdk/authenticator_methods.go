@@ -0,0 +1,91 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// AuthenticatorMethod is a way an OIE authenticator verifies a user, e.g. the
+// push, totp and signed_nonce methods of Okta Verify or the sms and voice
+// methods of the phone authenticator.
+type AuthenticatorMethod struct {
+	Type     string                 `json:"type,omitempty"`
+	Status   string                 `json:"status,omitempty"`
+	Settings map[string]interface{} `json:"settings,omitempty"`
+	Links    interface{}            `json:"_links,omitempty"`
+}
+
+// ListAuthenticatorMethods lists the methods of an authenticator.
+func (m *APISupplement) ListAuthenticatorMethods(ctx context.Context, authenticatorID string) ([]*AuthenticatorMethod, *Response, error) {
+	url := fmt.Sprintf("/api/v1/authenticators/%s/methods", authenticatorID)
+	re := m.cloneRequestExecutor()
+	req, err := re.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var methods []*AuthenticatorMethod
+	resp, err := re.Do(ctx, req, &methods)
+	if err != nil {
+		return nil, resp, err
+	}
+	return methods, resp, nil
+}
+
+// GetAuthenticatorMethod gets a method of an authenticator by type.
+func (m *APISupplement) GetAuthenticatorMethod(ctx context.Context, authenticatorID, methodType string) (*AuthenticatorMethod, *Response, error) {
+	url := fmt.Sprintf("/api/v1/authenticators/%s/methods/%s", authenticatorID, methodType)
+	re := m.cloneRequestExecutor()
+	req, err := re.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var method *AuthenticatorMethod
+	resp, err := re.Do(ctx, req, &method)
+	if err != nil {
+		return nil, resp, err
+	}
+	return method, resp, nil
+}
+
+// UpdateAuthenticatorMethod replaces the settings of a method of an
+// authenticator.
+func (m *APISupplement) UpdateAuthenticatorMethod(ctx context.Context, authenticatorID, methodType string, body AuthenticatorMethod) (*AuthenticatorMethod, *Response, error) {
+	url := fmt.Sprintf("/api/v1/authenticators/%s/methods/%s", authenticatorID, methodType)
+	re := m.cloneRequestExecutor()
+	req, err := re.WithAccept("application/json").WithContentType("application/json").NewRequest(http.MethodPut, url, body)
+	if err != nil {
+		return nil, nil, err
+	}
+	var method *AuthenticatorMethod
+	resp, err := re.Do(ctx, req, &method)
+	if err != nil {
+		return nil, resp, err
+	}
+	return method, resp, nil
+}
+
+// ActivateAuthenticatorMethod allows users to verify with the method.
+func (m *APISupplement) ActivateAuthenticatorMethod(ctx context.Context, authenticatorID, methodType string) (*AuthenticatorMethod, *Response, error) {
+	return m.lifecycleChangeAuthenticatorMethod(ctx, authenticatorID, methodType, "activate")
+}
+
+// DeactivateAuthenticatorMethod denies users to verify with the method.
+func (m *APISupplement) DeactivateAuthenticatorMethod(ctx context.Context, authenticatorID, methodType string) (*AuthenticatorMethod, *Response, error) {
+	return m.lifecycleChangeAuthenticatorMethod(ctx, authenticatorID, methodType, "deactivate")
+}
+
+func (m *APISupplement) lifecycleChangeAuthenticatorMethod(ctx context.Context, authenticatorID, methodType, action string) (*AuthenticatorMethod, *Response, error) {
+	url := fmt.Sprintf("/api/v1/authenticators/%s/methods/%s/lifecycle/%s", authenticatorID, methodType, action)
+	re := m.cloneRequestExecutor()
+	req, err := re.WithAccept("application/json").WithContentType("application/json").NewRequest(http.MethodPost, url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var method *AuthenticatorMethod
+	resp, err := re.Do(ctx, req, &method)
+	if err != nil {
+		return nil, resp, err
+	}
+	return method, resp, nil
+}
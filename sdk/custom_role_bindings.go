@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+
+	"github.com/okta/terraform-provider-okta/sdk/query"
 )
 
 type CreateCustomRoleBindingRequest struct {
@@ -16,6 +18,11 @@ type CustomRoleBinding struct {
 	Links interface{} `json:"_links,omitempty"`
 }
 
+type ListResourceSetBindingsResponse struct {
+	Roles []*CustomRoleBinding `json:"roles,omitempty"`
+	Links *IAMLinks            `json:"_links,omitempty"`
+}
+
 func (m *APISupplement) CreateResourceSetBinding(ctx context.Context, resourceSetID string, body CreateCustomRoleBindingRequest) (*Response, error) {
 	url := fmt.Sprintf("/api/v1/iam/resource-sets/%s/bindings", resourceSetID)
 	re := m.cloneRequestExecutor()
@@ -26,6 +33,26 @@ func (m *APISupplement) CreateResourceSetBinding(ctx context.Context, resourceSe
 	return re.Do(ctx, req, nil)
 }
 
+// ListResourceSetBindings lists the custom roles bound to the resource set,
+// each binding's members hold the role on the resource set.
+func (m *APISupplement) ListResourceSetBindings(ctx context.Context, resourceSetID string, qp *query.Params) (*ListResourceSetBindingsResponse, *Response, error) {
+	url := fmt.Sprintf("/api/v1/iam/resource-sets/%s/bindings", resourceSetID)
+	if qp != nil {
+		url += qp.String()
+	}
+	re := m.cloneRequestExecutor()
+	req, err := re.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var bindings *ListResourceSetBindingsResponse
+	resp, err := re.Do(ctx, req, &bindings)
+	if err != nil {
+		return nil, resp, err
+	}
+	return bindings, resp, nil
+}
+
 func (m *APISupplement) GetResourceSetBinding(ctx context.Context, resourceSetID, customRoleID string) (*CustomRoleBinding, *Response, error) {
 	url := fmt.Sprintf("/api/v1/iam/resource-sets/%s/bindings/%s", resourceSetID, customRoleID)
 	re := m.cloneRequestExecutor()
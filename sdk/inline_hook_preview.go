@@ -0,0 +1,27 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// PreviewInlineHook executes an inline hook with a payload, Okta sends the
+// payload to the endpoint of the hook and returns its response. Unlike
+// ExecuteInlineHook, the response is returned as is, the commands of the
+// hook types don't share a shape.
+func (m *APISupplement) PreviewInlineHook(ctx context.Context, inlineHookID string, payload json.RawMessage) (json.RawMessage, *Response, error) {
+	url := fmt.Sprintf("/api/v1/inlineHooks/%s/execute", inlineHookID)
+	re := m.cloneRequestExecutor()
+	req, err := re.NewRequest(http.MethodPost, url, payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	var response json.RawMessage
+	resp, err := re.Do(ctx, req, &response)
+	if err != nil {
+		return nil, resp, err
+	}
+	return response, resp, nil
+}
@@ -130,3 +130,50 @@ func (m *APISupplement) UpdatePolicyRule(ctx context.Context, policyID, ruleId s
 	}
 	return policyRule, resp, nil
 }
+
+// PolicyRuleSummary is the position of a policy rule of any type, e.g. sign-on
+// or access policy rules, in its policy.
+type PolicyRuleSummary struct {
+	Id       string `json:"id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Priority int64  `json:"priority,omitempty"`
+	System   *bool  `json:"system,omitempty"`
+}
+
+// ListPolicyRuleSummaries enumerates the positions of all policy rules.
+func (m *APISupplement) ListPolicyRuleSummaries(ctx context.Context, policyID string) ([]PolicyRuleSummary, *Response, error) {
+	url := fmt.Sprintf("/api/v1/policies/%v/rules", policyID)
+	req, err := m.RequestExecutor.WithAccept("application/json").WithContentType("application/json").NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var rules []PolicyRuleSummary
+	resp, err := m.RequestExecutor.Do(ctx, req, &rules)
+	if err != nil {
+		return nil, resp, err
+	}
+	return rules, resp, nil
+}
+
+// SetPolicyRulePriority moves a policy rule of any type to the priority. The
+// rule is read and written back as is, other than its priority, so no
+// attributes of rule types unknown to the SDK are lost.
+func (m *APISupplement) SetPolicyRulePriority(ctx context.Context, policyID, ruleID string, priority int64) (*Response, error) {
+	url := fmt.Sprintf("/api/v1/policies/%v/rules/%v", policyID, ruleID)
+	req, err := m.RequestExecutor.WithAccept("application/json").WithContentType("application/json").NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var rule map[string]interface{}
+	resp, err := m.RequestExecutor.Do(ctx, req, &rule)
+	if err != nil {
+		return resp, err
+	}
+	delete(rule, "_links")
+	rule["priority"] = priority
+	req, err = m.RequestExecutor.WithAccept("application/json").WithContentType("application/json").NewRequest(http.MethodPut, url, rule)
+	if err != nil {
+		return nil, err
+	}
+	return m.RequestExecutor.Do(ctx, req, nil)
+}
@@ -0,0 +1,63 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/okta/terraform-provider-okta/sdk/query"
+)
+
+type ListRoleAssigneeUsersResponse struct {
+	Value []*RoleAssignee `json:"value,omitempty"`
+	Links *IAMLinks       `json:"_links,omitempty"`
+}
+
+type RoleAssignee struct {
+	Id    string      `json:"id,omitempty"`
+	Orn   string      `json:"orn,omitempty"`
+	Links interface{} `json:"_links,omitempty"`
+}
+
+// IAMLinks are the links of a page of an IAM API list endpoint, which are
+// returned in the body rather than the Link header.
+type IAMLinks struct {
+	Next *IAMLink `json:"next,omitempty"`
+}
+
+type IAMLink struct {
+	Href string `json:"href,omitempty"`
+}
+
+// After returns the cursor of the next page, empty when there are no more
+// pages.
+func (l *IAMLinks) After() string {
+	if l == nil || l.Next == nil || l.Next.Href == "" {
+		return ""
+	}
+	next, err := url.Parse(l.Next.Href)
+	if err != nil {
+		return ""
+	}
+	return next.Query().Get("after")
+}
+
+// ListRoleAssigneeUsers lists the users with role assignments, the users
+// holding an admin role either directly or through a group.
+func (m *APISupplement) ListRoleAssigneeUsers(ctx context.Context, qp *query.Params) (*ListRoleAssigneeUsersResponse, *Response, error) {
+	url := "/api/v1/iam/assignees/users"
+	if qp != nil {
+		url += qp.String()
+	}
+	re := m.cloneRequestExecutor()
+	req, err := re.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var response *ListRoleAssigneeUsersResponse
+	resp, err := re.Do(ctx, req, &response)
+	if err != nil {
+		return nil, resp, err
+	}
+	return response, resp, nil
+}